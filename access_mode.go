@@ -0,0 +1,59 @@
+// Copyright © 2024 Meroxa, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pulsar
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/apache/pulsar-client-go/pulsar"
+)
+
+// Supported values of DestinationConfig.ProducerAccessMode.
+const (
+	// ProducerAccessModeShared allows multiple producers on the topic.
+	ProducerAccessModeShared = "shared"
+	// ProducerAccessModeExclusive fails to create the producer if another
+	// producer is connected to the topic.
+	ProducerAccessModeExclusive = "exclusive"
+	// ProducerAccessModeWaitForExclusive waits until the producer can acquire
+	// exclusive access to the topic.
+	ProducerAccessModeWaitForExclusive = "waitForExclusive"
+)
+
+var errExclusiveWaitTimeout = errors.New("timed out waiting for exclusive access to the topic")
+
+func toProducerAccessMode(mode string) pulsar.ProducerAccessMode {
+	switch mode {
+	case ProducerAccessModeExclusive:
+		return pulsar.ProducerAccessModeExclusive
+	case ProducerAccessModeWaitForExclusive:
+		return pulsar.ProducerAccessModeWaitForExclusive
+	default:
+		return pulsar.ProducerAccessModeShared
+	}
+}
+
+// createProducerWithTimeout calls create and gives up after timeout. If the
+// producer is created after the timeout it is closed right away. A timeout of
+// 0 waits indefinitely.
+func createProducerWithTimeout(create func() (pulsar.Producer, error), timeout time.Duration) (pulsar.Producer, error) {
+	producer, err := callWithTimeout(create, timeout, func(p pulsar.Producer) { p.Close() })
+	if errors.Is(err, errOperationTimeout) {
+		return nil, fmt.Errorf("%w: %w", errExclusiveWaitTimeout, err)
+	}
+	return producer, err
+}
@@ -0,0 +1,67 @@
+// Copyright © 2024 Meroxa, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pulsar
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/apache/pulsar-client-go/pulsar"
+	"github.com/matryer/is"
+)
+
+// closeTrackingProducer records whether it was closed.
+type closeTrackingProducer struct {
+	pulsar.Producer
+
+	closed chan struct{}
+}
+
+func (p *closeTrackingProducer) Close() {
+	close(p.closed)
+}
+
+func TestCreateProducerWithTimeout_TimesOut(t *testing.T) {
+	is := is.New(t)
+
+	release := make(chan struct{})
+	producer := &closeTrackingProducer{closed: make(chan struct{})}
+
+	_, err := createProducerWithTimeout(func() (pulsar.Producer, error) {
+		<-release
+		return producer, nil
+	}, 10*time.Millisecond)
+	is.True(errors.Is(err, errExclusiveWaitTimeout))
+
+	// the producer created after the timeout is closed
+	close(release)
+	select {
+	case <-producer.closed:
+	case <-time.After(time.Second):
+		t.Fatal("producer created after timeout was not closed")
+	}
+}
+
+func TestCreateProducerWithTimeout_NoTimeout(t *testing.T) {
+	is := is.New(t)
+
+	want := &closeTrackingProducer{closed: make(chan struct{})}
+	got, err := createProducerWithTimeout(func() (pulsar.Producer, error) {
+		return want, nil
+	}, 0)
+	is.NoErr(err)
+	is.Equal(got, want)
+}
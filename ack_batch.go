@@ -0,0 +1,163 @@
+// Copyright © 2024 Meroxa, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pulsar
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/apache/pulsar-client-go/pulsar"
+	sdk "github.com/conduitio/conduit-connector-sdk"
+)
+
+// ackBatch groups acknowledgements until the number of acked messages or their
+// total payload size reaches a threshold. Read and Ack can be called
+// concurrently.
+type ackBatch struct {
+	maxCount int
+	maxBytes int
+	interval time.Duration
+
+	mu sync.Mutex
+	// sizes contains the payload size of read messages by serialized ID.
+	sizes        map[string]int
+	pending      []pulsar.MessageID
+	pendingBytes int
+}
+
+func newAckBatch(maxCount, maxBytes int, interval time.Duration) *ackBatch {
+	return &ackBatch{
+		maxCount: maxCount,
+		maxBytes: maxBytes,
+		interval: interval,
+		sizes:    make(map[string]int),
+	}
+}
+
+// read records the payload size of the message with the serialized ID.
+func (b *ackBatch) read(serializedID []byte, size int) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.sizes[string(serializedID)] = size
+}
+
+// forget stops tracking the message with the serialized ID, which was
+// negatively acknowledged.
+func (b *ackBatch) forget(serializedID []byte) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	delete(b.sizes, string(serializedID))
+}
+
+// add adds the acknowledgement of the message to the batch. It returns the
+// batched acknowledgements once a threshold is reached, or nil otherwise.
+func (b *ackBatch) add(id pulsar.MessageID, serializedID []byte) []pulsar.MessageID {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.pending = append(b.pending, id)
+	b.pendingBytes += b.sizes[string(serializedID)]
+	delete(b.sizes, string(serializedID))
+
+	if (b.maxCount > 0 && len(b.pending) >= b.maxCount) ||
+		(b.maxBytes > 0 && b.pendingBytes >= b.maxBytes) {
+		return b.drainLocked()
+	}
+	return nil
+}
+
+// drain returns the batched acknowledgements and empties the batch.
+func (b *ackBatch) drain() []pulsar.MessageID {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.drainLocked()
+}
+
+func (b *ackBatch) drainLocked() []pulsar.MessageID {
+	ids := b.pending
+	b.pending = nil
+	b.pendingBytes = 0
+	return ids
+}
+
+// ackIDs sends the acknowledgements to the broker. A failed acknowledgement
+// doesn't keep the remaining ones from being sent, the returned error lists
+// every message that couldn't be acked.
+func (s *Source) ackIDs(ids []pulsar.MessageID) error {
+	var errs []error
+	for _, id := range ids {
+		if err := s.ackID(id); err != nil {
+			errs = append(errs, fmt.Errorf("failed to ack message %v: %w", id, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// responseAcker is implemented by consumers that can acknowledge a message and
+// wait for the broker's response.
+type responseAcker interface {
+	AckIDWithResponse(pulsar.MessageID) error
+	AckIDWithResponseCumulative(pulsar.MessageID) error
+}
+
+// ackID acknowledges the message, cumulatively if configured. If acks are
+// flushed on commit, the ack waits for the broker's response. Consumers that
+// don't expose the response acks do the same, because they are created with
+// AckWithResponse.
+func (s *Source) ackID(id pulsar.MessageID) error {
+	s.consumerMu.RLock()
+	defer s.consumerMu.RUnlock()
+	if acker, ok := s.consumer.(responseAcker); ok && s.config.FlushAcksOnCommit {
+		if s.config.AckMode == AckModeCumulative {
+			return acker.AckIDWithResponseCumulative(id)
+		}
+		return acker.AckIDWithResponse(id)
+	}
+	if s.config.AckMode == AckModeCumulative {
+		return s.consumer.AckIDCumulative(id)
+	}
+	return s.consumer.AckID(id)
+}
+
+// flushAcksEvery sends the batched acknowledgements at the flush interval until
+// the returned function is called.
+func (s *Source) flushAcksEvery(ctx context.Context) (stop func()) {
+	ticker := time.NewTicker(s.acks.interval)
+	done := make(chan struct{})
+	stopped := make(chan struct{})
+
+	go func() {
+		defer close(stopped)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-done:
+				return
+			case <-ticker.C:
+				if err := s.ackIDs(s.acks.drain()); err != nil {
+					sdk.Logger(ctx).Warn().Err(err).Msg("failed to flush batched acks")
+				}
+			}
+		}
+	}()
+
+	return func() {
+		close(done)
+		<-stopped
+	}
+}
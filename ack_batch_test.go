@@ -0,0 +1,155 @@
+// Copyright © 2024 Meroxa, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pulsar
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/apache/pulsar-client-go/pulsar"
+	"github.com/matryer/is"
+)
+
+func TestAckBatch_FlushBytes(t *testing.T) {
+	is := is.New(t)
+
+	batch := newAckBatch(0, 100, time.Minute)
+	ids := []pulsar.MessageID{
+		pulsar.NewMessageID(1, 1, 0, 0),
+		pulsar.NewMessageID(1, 2, 0, 0),
+		pulsar.NewMessageID(1, 3, 0, 0),
+	}
+	for i, size := range []int{40, 50, 10} {
+		batch.read(ids[i].Serialize(), size)
+	}
+
+	is.Equal(batch.add(ids[0], ids[0].Serialize()), nil)
+	is.Equal(batch.add(ids[1], ids[1].Serialize()), nil)
+	// 100 bytes reached
+	is.Equal(len(batch.add(ids[2], ids[2].Serialize())), 3)
+	is.Equal(batch.drain(), nil)
+}
+
+func TestAckBatch_FlushCount(t *testing.T) {
+	is := is.New(t)
+
+	batch := newAckBatch(2, 1000, time.Minute)
+	first := pulsar.NewMessageID(1, 1, 0, 0)
+	second := pulsar.NewMessageID(1, 2, 0, 0)
+
+	is.Equal(batch.add(first, first.Serialize()), nil)
+	is.Equal(len(batch.add(second, second.Serialize())), 2)
+}
+
+func TestSource_Ack_FlushBytes(t *testing.T) {
+	is := is.New(t)
+	ctx := context.Background()
+
+	var messages []pulsar.Message
+	for i, size := range []int{600, 300, 200, 50} {
+		messages = append(messages, payloadMessage{
+			readableMessage{fakeMessage{id: pulsar.NewMessageID(1, int64(i), 0, 0)}},
+			bytes.Repeat([]byte("a"), size),
+		})
+	}
+	consumer := &rejectRecordingConsumer{queueConsumer: queueConsumer{messages: messages}}
+	underTest := &Source{
+		consumer: consumer,
+		acks:     newAckBatch(0, 1000, time.Minute),
+	}
+
+	var ackedAfter []int
+	for range messages {
+		rec, err := underTest.Read(ctx)
+		is.NoErr(err)
+		err = underTest.Ack(ctx, rec.Position)
+		is.NoErr(err)
+		ackedAfter = append(ackedAfter, len(consumer.acked))
+	}
+	// the acks are sent once 1000 bytes were acked
+	is.Equal(ackedAfter, []int{0, 0, 3, 3})
+
+	// the remaining ack is sent on teardown
+	underTest.stopAckFlush = func() {}
+	err := underTest.Teardown(ctx)
+	is.NoErr(err)
+	is.Equal(len(consumer.acked), 4)
+}
+
+func TestSource_Configure_AckFlushBytes(t *testing.T) {
+	testCases := []struct {
+		name    string
+		cfg     map[string]string
+		wantErr bool
+	}{
+		{name: "enabled", cfg: map[string]string{SourceConfigAckFlushBytes: "1048576"}},
+		{name: "negative", cfg: map[string]string{SourceConfigAckFlushBytes: "-1"}, wantErr: true},
+		{name: "zero interval", cfg: map[string]string{SourceConfigAckFlushBytes: "1024", SourceConfigAckFlushInterval: "0s"}, wantErr: true},
+		{name: "flush on commit", cfg: map[string]string{SourceConfigAckFlushBytes: "1024", SourceConfigFlushAcksOnCommit: "true"}, wantErr: true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			is := is.New(t)
+
+			cfgMap := newSourceCfg("topic")
+			for k, v := range tc.cfg {
+				cfgMap[k] = v
+			}
+
+			err := (&Source{}).Configure(context.Background(), cfgMap)
+			is.Equal(err != nil, tc.wantErr)
+		})
+	}
+}
+
+// failingAckConsumer fails to ack the messages with the given entry IDs.
+type failingAckConsumer struct {
+	queueConsumer
+	failing map[int64]bool
+	acked   []pulsar.MessageID
+}
+
+func (c *failingAckConsumer) AckID(id pulsar.MessageID) error {
+	c.acked = append(c.acked, id)
+	if c.failing[id.EntryID()] {
+		return errors.New("connection closed")
+	}
+	return nil
+}
+
+func TestSource_AckIDs_ContinuesAfterFailure(t *testing.T) {
+	is := is.New(t)
+
+	consumer := &failingAckConsumer{failing: map[int64]bool{1: true, 3: true}}
+	underTest := &Source{consumer: consumer}
+
+	var ids []pulsar.MessageID
+	for i := range 4 {
+		ids = append(ids, pulsar.NewMessageID(1, int64(i), 0, 0))
+	}
+
+	err := underTest.ackIDs(ids)
+	is.True(err != nil)
+	// every drained ack was sent, and both failures are reported
+	is.Equal(len(consumer.acked), 4)
+	is.Equal(strings.Count(err.Error(), "connection closed"), 2)
+	is.True(strings.Contains(err.Error(), ids[1].String()))
+	is.True(strings.Contains(err.Error(), ids[3].String()))
+}
@@ -0,0 +1,90 @@
+// Copyright © 2024 Meroxa, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pulsar
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// ackLatencyHistogramOpts describe the histogram of ack latencies. It is
+// registered in the same registry as the metrics of the Pulsar client and is
+// exposed on the same Prometheus endpoint.
+var ackLatencyHistogramOpts = prometheus.HistogramOpts{
+	Namespace: "conduit_pulsar",
+	Name:      "source_ack_latency_seconds",
+	Help:      "Time between a record being read and acknowledged by the source.",
+	Buckets:   prometheus.ExponentialBuckets(0.001, 4, 10),
+}
+
+// ackLatencyRecorder measures the time between reading and acknowledging
+// records, which shows how long the pipeline takes to process them.
+type ackLatencyRecorder struct {
+	observer prometheus.Observer
+
+	mu     sync.Mutex
+	readAt map[string]time.Time
+
+	now func() time.Time
+}
+
+func newAckLatencyRecorder(registerer prometheus.Registerer, topic string) (*ackLatencyRecorder, error) {
+	histogram := prometheus.NewHistogramVec(ackLatencyHistogramOpts, []string{"topic"})
+	if err := registerer.Register(histogram); err != nil {
+		// multiple sources share the histogram
+		var alreadyRegistered prometheus.AlreadyRegisteredError
+		if !errors.As(err, &alreadyRegistered) {
+			return nil, fmt.Errorf("failed to register ack latency metric: %w", err)
+		}
+		histogram = alreadyRegistered.ExistingCollector.(*prometheus.HistogramVec)
+	}
+
+	return &ackLatencyRecorder{
+		observer: histogram.WithLabelValues(topic),
+		readAt:   make(map[string]time.Time),
+		now:      time.Now,
+	}, nil
+}
+
+// read records when the message with the serialized ID was read.
+func (r *ackLatencyRecorder) read(msgID []byte) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.readAt[string(msgID)] = r.now()
+}
+
+// forget stops measuring the latency of the message with the serialized ID,
+// which was negatively acknowledged.
+func (r *ackLatencyRecorder) forget(msgID []byte) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	delete(r.readAt, string(msgID))
+}
+
+// acked observes the latency of the message with the serialized ID.
+func (r *ackLatencyRecorder) acked(msgID []byte) {
+	r.mu.Lock()
+	readAt, ok := r.readAt[string(msgID)]
+	delete(r.readAt, string(msgID))
+	r.mu.Unlock()
+
+	if ok {
+		r.observer.Observe(r.now().Sub(readAt).Seconds())
+	}
+}
@@ -0,0 +1,71 @@
+// Copyright © 2024 Meroxa, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pulsar
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/apache/pulsar-client-go/pulsar"
+	"github.com/matryer/is"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+func TestSource_AckLatencyMetrics(t *testing.T) {
+	is := is.New(t)
+	ctx := context.Background()
+
+	registry := prometheus.NewRegistry()
+	recorder, err := newAckLatencyRecorder(registry, "test-topic")
+	is.NoErr(err)
+	clock := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	recorder.now = func() time.Time { return clock }
+
+	consumer := &queueConsumer{messages: []pulsar.Message{
+		readableMessage{fakeMessage{topic: "test-topic", id: pulsar.NewMessageID(1, 1, 0, 0)}},
+	}}
+	underTest := &Source{
+		consumer:   consumer,
+		config:     SourceConfig{Config: Config{Topic: "test-topic"}},
+		ackLatency: recorder,
+	}
+
+	rec, err := underTest.Read(ctx)
+	is.NoErr(err)
+	clock = clock.Add(250 * time.Millisecond)
+	is.NoErr(underTest.Ack(ctx, rec.Position))
+
+	families, err := registry.Gather()
+	is.NoErr(err)
+	is.Equal(len(families), 1)
+	is.Equal(families[0].GetName(), "conduit_pulsar_source_ack_latency_seconds")
+
+	metric := families[0].GetMetric()[0]
+	is.Equal(metric.GetLabel()[0].GetValue(), "test-topic")
+	is.Equal(metric.GetHistogram().GetSampleCount(), uint64(1))
+	is.Equal(metric.GetHistogram().GetSampleSum(), 0.25)
+}
+
+func TestNewAckLatencyRecorder_AlreadyRegistered(t *testing.T) {
+	is := is.New(t)
+
+	registry := prometheus.NewRegistry()
+	_, err := newAckLatencyRecorder(registry, "topic-a")
+	is.NoErr(err)
+	// a second source reuses the registered histogram
+	_, err = newAckLatencyRecorder(registry, "topic-b")
+	is.NoErr(err)
+}
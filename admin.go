@@ -0,0 +1,260 @@
+// Copyright © 2024 Meroxa, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pulsar
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"path"
+	"time"
+
+	"github.com/apache/pulsar-client-go/pulsaradmin"
+	"github.com/apache/pulsar-client-go/pulsaradmin/pkg/admin"
+	"github.com/apache/pulsar-client-go/pulsaradmin/pkg/admin/auth"
+	"github.com/apache/pulsar-client-go/pulsaradmin/pkg/rest"
+	"github.com/apache/pulsar-client-go/pulsaradmin/pkg/utils"
+)
+
+func newAdminClient(cfg Config) (pulsaradmin.Client, error) {
+	adminCfg, err := newAdminConfig(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	client, err := pulsaradmin.NewClient(adminCfg)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create admin client: %w", err)
+	}
+
+	return client, nil
+}
+
+// newAdminRESTClient creates a client for admin endpoints that are not covered
+// by the admin client.
+func newAdminRESTClient(cfg Config) (*rest.Client, error) {
+	adminCfg, err := newAdminConfig(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	authProvider, err := auth.GetAuthProvider(adminCfg)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create admin client: %w", err)
+	}
+
+	return &rest.Client{
+		ServiceURL:  cfg.AdminURL,
+		VersionInfo: admin.ReleaseVersion,
+		HTTPClient: &http.Client{
+			Timeout:   admin.DefaultHTTPTimeOutDuration,
+			Transport: authProvider,
+		},
+	}, nil
+}
+
+func newAdminConfig(cfg Config) (*pulsaradmin.Config, error) {
+	if cfg.AdminURL == "" {
+		return nil, errors.New("adminURL is required for admin operations")
+	}
+
+	adminCfg := &pulsaradmin.Config{
+		WebServiceURL:                 cfg.AdminURL,
+		TLSTrustCertsFilePath:         cfg.TLSTrustCertsFilePath,
+		TLSAllowInsecureConnection:    cfg.TLSAllowInsecureConnection,
+		TLSEnableHostnameVerification: cfg.TLSValidateHostname,
+		TLSCertFile:                   cfg.TLSCertificateFile,
+		TLSKeyFile:                    cfg.TLSKeyFilePath,
+		Token:                         cfg.Token,
+		TokenFile:                     cfg.TokenFilePath,
+	}
+	if cfg.OAuth2IssuerURL != "" {
+		keyFile, err := oauth2KeyFile(cfg)
+		if err != nil {
+			return nil, err
+		}
+		adminCfg.IssuerEndpoint = cfg.OAuth2IssuerURL
+		adminCfg.ClientID = cfg.OAuth2ClientID
+		adminCfg.Audience = cfg.OAuth2Audience
+		adminCfg.Scope = cfg.OAuth2Scope
+		adminCfg.KeyFile = keyFile
+	}
+	return adminCfg, nil
+}
+
+// enableTopicDeduplication turns on broker-side message deduplication for the
+// given topic.
+func enableTopicDeduplication(admin pulsaradmin.Client, topic string) error {
+	topicName, err := utils.GetTopicName(topic)
+	if err != nil {
+		return fmt.Errorf("invalid topic name %q: %w", topic, err)
+	}
+
+	err = admin.Topics().SetDeduplicationStatus(*topicName, true)
+	if err != nil {
+		return fmt.Errorf("failed to enable deduplication on topic %q: %w", topic, adminError(err))
+	}
+
+	return nil
+}
+
+// setDedupSnapshotInterval sets how often the broker takes a snapshot of the
+// deduplication state of the topic. The admin client has no method for this
+// topic policy, so the endpoint is called directly.
+func setDedupSnapshotInterval(client *rest.Client, topic string, interval time.Duration) error {
+	topicName, err := utils.GetTopicName(topic)
+	if err != nil {
+		return fmt.Errorf("invalid topic name %q: %w", topic, err)
+	}
+
+	endpoint := dedupSnapshotIntervalEndpoint(*topicName)
+	err = client.Post(endpoint, int(interval.Seconds()))
+	if err != nil {
+		return fmt.Errorf("failed to set deduplication snapshot interval on topic %q: %w", topic, adminError(err))
+	}
+
+	return nil
+}
+
+func dedupSnapshotIntervalEndpoint(topicName utils.TopicName) string {
+	return path.Join("/admin/v2", topicName.GetRestPath(), "deduplicationSnapshotInterval")
+}
+
+// checkSchemaVersion verifies that the schema version exists on the topic.
+func checkSchemaVersion(admin pulsaradmin.Client, topic string, version int64) error {
+	_, err := admin.Schemas().GetSchemaInfoByVersion(topic, version)
+	var restErr rest.Error
+	if errors.As(err, &restErr) && restErr.Code == http.StatusNotFound {
+		return fmt.Errorf("schema version %d doesn't exist on topic %q", version, topic)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to get schema version %d of topic %q: %w", version, topic, adminError(err))
+	}
+
+	return nil
+}
+
+// getTopicSchema returns the latest schema of the topic, or nil if the topic
+// has no schema.
+func getTopicSchema(admin pulsaradmin.Client, topic string) (*utils.SchemaInfo, error) {
+	info, err := admin.Schemas().GetSchemaInfo(topic)
+	var restErr rest.Error
+	if errors.As(err, &restErr) && restErr.Code == http.StatusNotFound {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get schema of topic %q: %w", topic, adminError(err))
+	}
+	return info, nil
+}
+
+// createTopic creates the given topic with the number of partitions, a
+// non-partitioned topic if partitions is 0. It succeeds if the topic already
+// exists, e.g. because it was auto-created in the meantime.
+func createTopic(admin pulsaradmin.Client, topic string, partitions int) error {
+	topicName, err := utils.GetTopicName(topic)
+	if err != nil {
+		return fmt.Errorf("invalid topic name %q: %w", topic, err)
+	}
+
+	err = admin.Topics().Create(*topicName, partitions)
+	var restErr rest.Error
+	if errors.As(err, &restErr) && restErr.Code == http.StatusConflict {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to create topic %q: %w", topic, adminError(err))
+	}
+
+	return nil
+}
+
+// topicPartitions returns the number of partitions of the topic, or 0 if the
+// topic is not partitioned.
+func topicPartitions(admin pulsaradmin.Client, topic string) (int, error) {
+	topicName, err := utils.GetTopicName(topic)
+	if err != nil {
+		return 0, fmt.Errorf("invalid topic name %q: %w", topic, err)
+	}
+
+	metadata, err := admin.Topics().GetMetadata(*topicName)
+	if err != nil {
+		return 0, fmt.Errorf("failed to get partitions of topic %q: %w", topic, adminError(err))
+	}
+	return metadata.Partitions, nil
+}
+
+// growPartitions doubles the number of partitions of the topic, up to
+// maxPartitions. It returns the new number of partitions, or 0 if the topic
+// already has maxPartitions partitions. Non-partitioned topics can't be grown.
+func growPartitions(admin pulsaradmin.Client, topic string, maxPartitions int) (int, error) {
+	topicName, err := utils.GetTopicName(topic)
+	if err != nil {
+		return 0, fmt.Errorf("invalid topic name %q: %w", topic, err)
+	}
+
+	metadata, err := admin.Topics().GetMetadata(*topicName)
+	if err != nil {
+		return 0, fmt.Errorf("failed to get partitions of topic %q: %w", topic, adminError(err))
+	}
+	if metadata.Partitions == 0 {
+		return 0, fmt.Errorf("topic %q is not partitioned, its partitions can't be grown", topic)
+	}
+	if metadata.Partitions >= maxPartitions {
+		return 0, nil
+	}
+
+	partitions := min(2*metadata.Partitions, maxPartitions)
+	if err := admin.Topics().Update(*topicName, partitions); err != nil {
+		return 0, fmt.Errorf("failed to grow partitions of topic %q to %d: %w", topic, partitions, adminError(err))
+	}
+	return partitions, nil
+}
+
+// resetSubscription moves the cursor of the subscription to the earliest or
+// latest message of the topic. The subscription is created at that position
+// if it doesn't exist yet.
+func resetSubscription(admin pulsaradmin.Client, topic, subscription, position string) error {
+	topicName, err := utils.GetTopicName(topic)
+	if err != nil {
+		return fmt.Errorf("invalid topic name %q: %w", topic, err)
+	}
+
+	msgID := utils.Earliest
+	if position == SubscriptionPositionLatest {
+		msgID = utils.Latest
+	}
+
+	err = admin.Subscriptions().ResetCursorToMessageID(*topicName, subscription, msgID)
+	var restErr rest.Error
+	if errors.As(err, &restErr) && restErr.Code == http.StatusNotFound {
+		err = admin.Subscriptions().Create(*topicName, subscription, msgID)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to reset subscription %q on topic %q: %w", subscription, topic, adminError(err))
+	}
+
+	return nil
+}
+
+// adminError adds context to errors caused by missing admin permissions.
+func adminError(err error) error {
+	var restErr rest.Error
+	if errors.As(err, &restErr) &&
+		(restErr.Code == http.StatusUnauthorized || restErr.Code == http.StatusForbidden) {
+		return fmt.Errorf("connector is not authorized to perform admin operations, please check its permissions: %w", err)
+	}
+	return err
+}
@@ -0,0 +1,51 @@
+// Copyright © 2024 Meroxa, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pulsar
+
+import (
+	"context"
+	"time"
+
+	"github.com/apache/pulsar-client-go/pulsar"
+	sdk "github.com/conduitio/conduit-connector-sdk"
+	"github.com/google/uuid"
+)
+
+// Properties added to produced messages when AuditMetadata is enabled.
+const (
+	auditPropertyConnector  = "conduit.audit.connector"
+	auditPropertyInstanceID = "conduit.audit.instanceID"
+	auditPropertyProducedAt = "conduit.audit.producedAt"
+	auditProducedAtLayout   = time.RFC3339Nano
+)
+
+// auditInstanceID returns the ID of the connector assigned by Conduit, or a
+// random ID if the connector runs outside of Conduit.
+func auditInstanceID(ctx context.Context) string {
+	if id := sdk.ConnectorIDFromContext(ctx); id != "" {
+		return id
+	}
+	return uuid.NewString()
+}
+
+// addAuditProperties records which connector produced the message and when.
+func addAuditProperties(msg *pulsar.ProducerMessage, instanceID string, producedAt time.Time) {
+	if msg.Properties == nil {
+		msg.Properties = make(map[string]string)
+	}
+	msg.Properties[auditPropertyConnector] = Specification().Name
+	msg.Properties[auditPropertyInstanceID] = instanceID
+	msg.Properties[auditPropertyProducedAt] = producedAt.UTC().Format(auditProducedAtLayout)
+}
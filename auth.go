@@ -0,0 +1,76 @@
+// Copyright © 2024 Meroxa, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pulsar
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/apache/pulsar-client-go/oauth2"
+	"github.com/apache/pulsar-client-go/pulsar"
+	"github.com/apache/pulsar-client-go/pulsar/auth"
+)
+
+// newAuthentication returns the authentication provider of the client, or nil
+// if the client authenticates with a TLS certificate or not at all.
+func newAuthentication(cfg Config) (pulsar.Authentication, error) {
+	switch {
+	case cfg.Token != "":
+		return pulsar.NewAuthenticationToken(cfg.Token), nil
+	case cfg.TokenFilePath != "":
+		return pulsar.NewAuthenticationTokenFromFile(cfg.TokenFilePath), nil
+	case cfg.OAuth2IssuerURL == "":
+		return nil, nil
+	}
+
+	keyFile, err := oauth2KeyFile(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	// the provider is created directly, the constructor in the pulsar
+	// package discards errors
+	provider, err := auth.NewAuthenticationOAuth2WithParams(map[string]string{
+		auth.ConfigParamType:      auth.ConfigParamTypeClientCredentials,
+		auth.ConfigParamIssuerURL: cfg.OAuth2IssuerURL,
+		auth.ConfigParamClientID:  cfg.OAuth2ClientID,
+		auth.ConfigParamAudience:  cfg.OAuth2Audience,
+		auth.ConfigParamScope:     cfg.OAuth2Scope,
+		auth.ConfigParamKeyFile:   keyFile,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create OAuth2 authentication: %w", err)
+	}
+	return provider, nil
+}
+
+// oauth2KeyFile returns the key file containing the client credentials. A
+// configured client secret is passed as an inline key file.
+func oauth2KeyFile(cfg Config) (string, error) {
+	if cfg.OAuth2PrivateKeyFile != "" {
+		return cfg.OAuth2PrivateKeyFile, nil
+	}
+
+	keyFile, err := json.Marshal(oauth2.KeyFile{
+		Type:         auth.ConfigParamTypeClientCredentials,
+		ClientID:     cfg.OAuth2ClientID,
+		ClientSecret: cfg.OAuth2ClientSecret,
+		IssuerURL:    cfg.OAuth2IssuerURL,
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to encode OAuth2 client credentials: %w", err)
+	}
+	return oauth2.DATA + string(keyFile), nil
+}
@@ -0,0 +1,210 @@
+// Copyright © 2024 Meroxa, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pulsar
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/apache/pulsar-client-go/oauth2"
+	"github.com/conduitio-labs/conduit-connector-pulsar/test"
+	"github.com/matryer/is"
+)
+
+func TestSource_Configure_OAuth2WithTLSAuthentication(t *testing.T) {
+	is := is.New(t)
+	con := NewSource()
+
+	err := con.Configure(context.Background(), map[string]string{
+		SourceConfigUrl:                test.PulsarURL,
+		SourceConfigTopic:              "test-topic",
+		SourceConfigSubscriptionName:   "test-subscription",
+		SourceConfigTlsCertificateFile: "./test/certs/client.cert.pem",
+		SourceConfigTlsKeyFilePath:     "./test/certs/client.key-pk8.pem",
+		SourceConfigOauth2IssuerURL:    "https://auth.example.com",
+		SourceConfigOauth2ClientID:     "client",
+		SourceConfigOauth2ClientSecret: "secret",
+	})
+	is.True(err != nil)
+}
+
+func TestDestination_Configure_OAuth2WithTLSAuthentication(t *testing.T) {
+	is := is.New(t)
+	con := NewDestination()
+
+	err := con.Configure(context.Background(), map[string]string{
+		DestinationConfigUrl:                  test.PulsarURL,
+		DestinationConfigTopic:                "test-topic",
+		DestinationConfigTlsCertificateFile:   "./test/certs/client.cert.pem",
+		DestinationConfigTlsKeyFilePath:       "./test/certs/client.key-pk8.pem",
+		DestinationConfigOauth2IssuerURL:      "https://auth.example.com",
+		DestinationConfigOauth2PrivateKeyFile: "./test/oauth2.json",
+	})
+	is.True(err != nil)
+}
+
+func TestConfig_ValidateOAuth2(t *testing.T) {
+	testCases := []struct {
+		name    string
+		cfg     Config
+		wantErr bool
+	}{{
+		name: "disabled",
+		cfg:  Config{},
+	}, {
+		name: "client secret",
+		cfg: Config{
+			OAuth2IssuerURL:    "https://auth.example.com",
+			OAuth2ClientID:     "client",
+			OAuth2ClientSecret: "secret",
+		},
+	}, {
+		name: "private key file",
+		cfg: Config{
+			OAuth2IssuerURL:      "https://auth.example.com",
+			OAuth2PrivateKeyFile: "./test/oauth2.json",
+		},
+	}, {
+		name:    "client ID without issuer",
+		cfg:     Config{OAuth2ClientID: "client"},
+		wantErr: true,
+	}, {
+		name:    "no credentials",
+		cfg:     Config{OAuth2IssuerURL: "https://auth.example.com"},
+		wantErr: true,
+	}, {
+		name: "client secret without client ID",
+		cfg: Config{
+			OAuth2IssuerURL:    "https://auth.example.com",
+			OAuth2ClientSecret: "secret",
+		},
+		wantErr: true,
+	}, {
+		name: "client secret and private key file",
+		cfg: Config{
+			OAuth2IssuerURL:      "https://auth.example.com",
+			OAuth2ClientID:       "client",
+			OAuth2ClientSecret:   "secret",
+			OAuth2PrivateKeyFile: "./test/oauth2.json",
+		},
+		wantErr: true,
+	}}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			is := is.New(t)
+			err := tc.cfg.validateOAuth2()
+			is.Equal(err != nil, tc.wantErr)
+		})
+	}
+}
+
+func TestOAuth2KeyFile_ClientSecret(t *testing.T) {
+	is := is.New(t)
+
+	keyFile, err := oauth2KeyFile(Config{
+		OAuth2IssuerURL:    "https://auth.example.com",
+		OAuth2ClientID:     "client",
+		OAuth2ClientSecret: "secret",
+	})
+	is.NoErr(err)
+	is.True(strings.HasPrefix(keyFile, oauth2.DATA))
+
+	var got oauth2.KeyFile
+	err = json.Unmarshal([]byte(strings.TrimPrefix(keyFile, oauth2.DATA)), &got)
+	is.NoErr(err)
+	is.Equal(got.ClientID, "client")
+	is.Equal(got.ClientSecret, "secret")
+	is.Equal(got.IssuerURL, "https://auth.example.com")
+}
+
+func TestNewAuthentication_Disabled(t *testing.T) {
+	is := is.New(t)
+
+	provider, err := newAuthentication(Config{})
+	is.NoErr(err)
+	is.True(provider == nil)
+}
+
+func TestSource_Configure_TokenAndTokenFilePath(t *testing.T) {
+	is := is.New(t)
+
+	cfgMap := newSourceCfg("topic")
+	cfgMap[SourceConfigToken] = "token"
+	cfgMap[SourceConfigTokenFilePath] = "./test/token.jwt"
+
+	err := (&Source{}).Configure(context.Background(), cfgMap)
+	is.True(err != nil)
+}
+
+func TestDestination_Configure_TokenAndTokenFilePath(t *testing.T) {
+	is := is.New(t)
+	con := NewDestination()
+
+	err := con.Configure(context.Background(), map[string]string{
+		DestinationConfigUrl:           test.PulsarURL,
+		DestinationConfigTopic:         "test-topic",
+		DestinationConfigToken:         "token",
+		DestinationConfigTokenFilePath: "./test/token.jwt",
+	})
+	is.True(err != nil)
+}
+
+func TestConfig_ValidateToken(t *testing.T) {
+	testCases := []struct {
+		name    string
+		cfg     Config
+		wantErr bool
+	}{{
+		name: "disabled",
+		cfg:  Config{},
+	}, {
+		name: "token",
+		cfg:  Config{Token: "token"},
+	}, {
+		name: "token file",
+		cfg:  Config{TokenFilePath: "./test/token.jwt"},
+	}, {
+		name:    "token and token file",
+		cfg:     Config{Token: "token", TokenFilePath: "./test/token.jwt"},
+		wantErr: true,
+	}, {
+		name:    "token and OAuth2",
+		cfg:     Config{Token: "token", OAuth2IssuerURL: "https://auth.example.com"},
+		wantErr: true,
+	}, {
+		name:    "token file and TLS authentication",
+		cfg:     Config{TokenFilePath: "./test/token.jwt", TLSCertificateFile: "./test/certs/client.cert.pem"},
+		wantErr: true,
+	}}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			is := is.New(t)
+			err := tc.cfg.validateToken()
+			is.Equal(err != nil, tc.wantErr)
+		})
+	}
+}
+
+func TestNewAuthentication_Token(t *testing.T) {
+	is := is.New(t)
+
+	provider, err := newAuthentication(Config{Token: "token"})
+	is.NoErr(err)
+	is.True(provider != nil)
+}
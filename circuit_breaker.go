@@ -0,0 +1,71 @@
+// Copyright © 2024 Meroxa, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pulsar
+
+import (
+	"errors"
+	"time"
+)
+
+var errCircuitOpen = errors.New("circuit breaker is open, producing is paused")
+
+// circuitBreaker stops producing after a number of consecutive failures, so a
+// broken broker isn't hammered with sends. Once the cooldown elapsed a single
+// send is let through, which closes the circuit if it succeeds or opens it
+// again if it fails.
+type circuitBreaker struct {
+	threshold int
+	cooldown  time.Duration
+
+	failures int
+	// openedAt is zero while the circuit is closed.
+	openedAt time.Time
+
+	now func() time.Time
+}
+
+func newCircuitBreaker(threshold int, cooldown time.Duration) *circuitBreaker {
+	return &circuitBreaker{
+		threshold: threshold,
+		cooldown:  cooldown,
+		now:       time.Now,
+	}
+}
+
+// allow returns errCircuitOpen if the circuit is open and the cooldown did not
+// elapse yet.
+func (b *circuitBreaker) allow() error {
+	if !b.openedAt.IsZero() && b.now().Sub(b.openedAt) < b.cooldown {
+		return errCircuitOpen
+	}
+	return nil
+}
+
+// record tracks the result of a send and returns true if the circuit was
+// opened because of it.
+func (b *circuitBreaker) record(err error) bool {
+	if err == nil {
+		b.failures = 0
+		b.openedAt = time.Time{}
+		return false
+	}
+
+	b.failures++
+	if b.failures < b.threshold {
+		return false
+	}
+	b.openedAt = b.now()
+	return true
+}
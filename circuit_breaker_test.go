@@ -0,0 +1,74 @@
+// Copyright © 2024 Meroxa, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pulsar
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/conduitio/conduit-commons/opencdc"
+	sdk "github.com/conduitio/conduit-connector-sdk"
+	"github.com/google/uuid"
+	"github.com/matryer/is"
+)
+
+func TestDestination_Write_CircuitBreaker(t *testing.T) {
+	is := is.New(t)
+	ctx := context.Background()
+
+	producer := &failingProducer{err: errors.New("broker unavailable"), failures: 3}
+	con := &Destination{
+		producer: producer,
+		breaker:  newCircuitBreaker(2, time.Minute),
+	}
+	clock := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	con.breaker.now = func() time.Time { return clock }
+
+	write := func() error {
+		rec := sdk.Util.Source.NewRecordCreate(
+			[]byte(uuid.NewString()),
+			opencdc.Metadata{},
+			opencdc.RawData("test-key"),
+			opencdc.RawData(exampleMessage),
+		)
+		_, err := con.Write(ctx, []opencdc.Record{rec})
+		return err
+	}
+
+	// the circuit opens after two consecutive failures
+	is.True(write() != nil)
+	is.True(write() != nil)
+	is.Equal(producer.attempts, 2)
+
+	// writes fail fast while the circuit is open
+	err := write()
+	is.True(errors.Is(err, errCircuitOpen))
+	is.Equal(producer.attempts, 2)
+
+	// after the cooldown a single send is attempted, it fails and the circuit
+	// opens again
+	clock = clock.Add(time.Minute)
+	is.True(write() != nil)
+	is.Equal(producer.attempts, 3)
+	is.True(errors.Is(write(), errCircuitOpen))
+
+	// the next attempt after the cooldown succeeds and closes the circuit
+	clock = clock.Add(time.Minute)
+	is.NoErr(write())
+	is.NoErr(write())
+	is.Equal(producer.attempts, 5)
+}
@@ -0,0 +1,92 @@
+// Copyright © 2024 Meroxa, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pulsar
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/apache/pulsar-client-go/pulsar"
+	"github.com/klauspost/compress/zstd"
+)
+
+// Supported values of DestinationConfig.CompressionType.
+const (
+	CompressionTypeNone = "none"
+	CompressionTypeLZ4  = "lz4"
+	CompressionTypeZLib = "zlib"
+	CompressionTypeZSTD = "zstd"
+)
+
+// Supported values of DestinationConfig.CompressionLevel.
+const (
+	CompressionLevelDefault = "default"
+	CompressionLevelFaster  = "faster"
+	CompressionLevelBetter  = "better"
+)
+
+func toCompressionType(compressionType string) pulsar.CompressionType {
+	switch compressionType {
+	case CompressionTypeLZ4:
+		return pulsar.LZ4
+	case CompressionTypeZLib:
+		return pulsar.ZLib
+	case CompressionTypeZSTD:
+		return pulsar.ZSTD
+	default:
+		return pulsar.NoCompression
+	}
+}
+
+func toCompressionLevel(level string) pulsar.CompressionLevel {
+	switch level {
+	case CompressionLevelFaster:
+		return pulsar.Faster
+	case CompressionLevelBetter:
+		return pulsar.Better
+	default:
+		return pulsar.Default
+	}
+}
+
+// dictCompressor compresses payloads with zstd using a shared dictionary,
+// which improves the compression ratio of small, similar payloads. The Pulsar
+// client doesn't support compression dictionaries, so payloads are compressed
+// before they are handed to the producer.
+type dictCompressor struct {
+	encoder *zstd.Encoder
+}
+
+// newDictCompressor loads the zstd dictionary at path, as created by
+// "zstd --train".
+func newDictCompressor(path string) (*dictCompressor, error) {
+	dict, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read compression dictionary: %w", err)
+	}
+	encoder, err := zstd.NewWriter(nil, zstd.WithEncoderDict(dict))
+	if err != nil {
+		return nil, fmt.Errorf("failed to load compression dictionary: %w", err)
+	}
+	return &dictCompressor{encoder: encoder}, nil
+}
+
+func (c *dictCompressor) compress(payload []byte) []byte {
+	return c.encoder.EncodeAll(payload, nil)
+}
+
+func (c *dictCompressor) close() {
+	_ = c.encoder.Close()
+}
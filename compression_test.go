@@ -0,0 +1,145 @@
+// Copyright © 2024 Meroxa, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pulsar
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/apache/pulsar-client-go/pulsar"
+	"github.com/klauspost/compress/zstd"
+	"github.com/matryer/is"
+)
+
+func samplePayload(i int) []byte {
+	return []byte(fmt.Sprintf(`{"id":%d,"customer":"customer-%d","status":"shipped","currency":"EUR","items":[{"sku":"sku-%d","quantity":%d}]}`, i, i%7, i%13, i%3+1))
+}
+
+// writeTestDictionary builds a zstd dictionary from sample payloads and writes
+// it to a temporary file.
+func writeTestDictionary(t *testing.T) string {
+	var samples [][]byte
+	for i := 0; i < 500; i++ {
+		samples = append(samples, samplePayload(i))
+	}
+	dict, err := zstd.BuildDict(zstd.BuildDictOptions{
+		ID:       1,
+		Contents: samples,
+		History:  bytes.Join(samples[:50], nil),
+		Offsets:  [3]int{1, 4, 8},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	path := filepath.Join(t.TempDir(), "test.dict")
+	if err := os.WriteFile(path, dict, 0o600); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestDictCompressor_Compress(t *testing.T) {
+	is := is.New(t)
+
+	path := writeTestDictionary(t)
+	compressor, err := newDictCompressor(path)
+	is.NoErr(err)
+	defer compressor.close()
+
+	plain, err := zstd.NewWriter(nil)
+	is.NoErr(err)
+	defer plain.Close()
+
+	payload := samplePayload(1000)
+	withDict := compressor.compress(payload)
+	withoutDict := plain.EncodeAll(payload, nil)
+	t.Logf("payload: %d bytes, without dictionary: %d bytes, with dictionary: %d bytes", len(payload), len(withoutDict), len(withDict))
+	is.True(len(withDict) < len(withoutDict))
+
+	// the payload can be decompressed with the same dictionary
+	dict, err := os.ReadFile(path)
+	is.NoErr(err)
+	decoder, err := zstd.NewReader(nil, zstd.WithDecoderDicts(dict))
+	is.NoErr(err)
+	defer decoder.Close()
+	decompressed, err := decoder.DecodeAll(withDict, nil)
+	is.NoErr(err)
+	is.Equal(decompressed, payload)
+}
+
+func TestDestination_Configure_InvalidCompressionDictionary(t *testing.T) {
+	is := is.New(t)
+
+	path := filepath.Join(t.TempDir(), "invalid.dict")
+	is.NoErr(os.WriteFile(path, []byte("not a dictionary"), 0o600))
+
+	err := NewDestination().Configure(context.Background(), map[string]string{
+		DestinationConfigUrl:                   "pulsar://localhost:6650",
+		DestinationConfigTopic:                 "test-topic",
+		DestinationConfigCompressionDictionary: path,
+	})
+	is.True(err != nil)
+}
+
+func TestDestination_Configure_CompressionType(t *testing.T) {
+	testCases := []struct {
+		name    string
+		cfg     map[string]string
+		wantErr bool
+	}{
+		{name: "default", cfg: map[string]string{}},
+		{name: "zstd", cfg: map[string]string{DestinationConfigCompressionType: CompressionTypeZSTD}},
+		{name: "lz4 with level", cfg: map[string]string{
+			DestinationConfigCompressionType:  CompressionTypeLZ4,
+			DestinationConfigCompressionLevel: CompressionLevelBetter,
+		}},
+		{name: "unknown type", cfg: map[string]string{DestinationConfigCompressionType: "snappy"}, wantErr: true},
+		{name: "unknown level", cfg: map[string]string{DestinationConfigCompressionLevel: "fastest"}, wantErr: true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			is := is.New(t)
+
+			cfgMap := map[string]string{
+				DestinationConfigUrl:   "pulsar://localhost:6650",
+				DestinationConfigTopic: "test-topic",
+			}
+			for key, val := range tc.cfg {
+				cfgMap[key] = val
+			}
+
+			err := NewDestination().Configure(context.Background(), cfgMap)
+			is.Equal(err != nil, tc.wantErr)
+		})
+	}
+}
+
+func TestToCompressionType(t *testing.T) {
+	is := is.New(t)
+
+	is.Equal(toCompressionType(CompressionTypeNone), pulsar.NoCompression)
+	is.Equal(toCompressionType(CompressionTypeLZ4), pulsar.LZ4)
+	is.Equal(toCompressionType(CompressionTypeZLib), pulsar.ZLib)
+	is.Equal(toCompressionType(CompressionTypeZSTD), pulsar.ZSTD)
+	is.Equal(toCompressionLevel(CompressionLevelDefault), pulsar.Default)
+	is.Equal(toCompressionLevel(CompressionLevelFaster), pulsar.Faster)
+	is.Equal(toCompressionLevel(CompressionLevelBetter), pulsar.Better)
+}
@@ -15,6 +15,11 @@
 package pulsar
 
 import (
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
 	"time"
 )
 
@@ -25,8 +30,18 @@ type Config struct {
 	// URL of the Pulsar instance to connect to.
 	URL string `json:"url" validate:"required"`
 
-	// Topic specifies the Pulsar topic used by the connector.
-	Topic string `json:"topic" validate:"required"`
+	// ListenerName selects the advertised listener of the brokers the client
+	// connects to, for clusters that advertise multiple listeners, e.g. for
+	// internal and external networks. Uses the default listener if empty.
+	ListenerName string `json:"listenerName"`
+
+	// Topic specifies the Pulsar topic used by the connector. In the
+	// destination it can contain a Go template that is executed with the
+	// record to determine the topic, e.g.
+	// `events-{{index .Metadata "tenant"}}`. A producer is created for each
+	// resolved topic when the first record is written to it. Required in the
+	// destination, the source can be configured with Topics instead.
+	Topic string `json:"topic"`
 
 	// ConnectionTimeout specifies the duration for which the client will
 	// attempt to establish a connection before timing out.
@@ -36,6 +51,19 @@ type Config struct {
 	// to have timed out.
 	OperationTimeout time.Duration `json:"operationTimeout"`
 
+	// LookupTimeout bounds looking up the topic before subscribing to it or
+	// producing to it, independently of OperationTimeout. Disabled when set
+	// to 0.
+	LookupTimeout time.Duration `json:"lookupTimeout"`
+
+	// KeepAliveInterval is the interval in which the client pings the
+	// brokers to keep idle connections alive.
+	KeepAliveInterval time.Duration `json:"keepAliveInterval" default:"30s"`
+
+	// MaxBackoff is the upper bound of the exponential backoff between
+	// attempts to reconnect a consumer or producer to a broker.
+	MaxBackoff time.Duration `json:"maxBackoff" default:"60s"`
+
 	// MaxConnectionsPerBroker limits the number of connections to each broker.
 	MaxConnectionsPerBroker int `json:"maxConnectionsPerBroker"`
 
@@ -61,8 +89,125 @@ type Config struct {
 	// TLSValidateHostname configures whether the Pulsar client verifies the validity of the host name from broker (default: false)
 	TLSValidateHostname bool `json:"tlsValidateHostname"`
 
+	// OAuth2IssuerURL is the URL of the OAuth2 authorization server. If set,
+	// the connector authenticates with the OAuth2 client credentials flow
+	// instead of a TLS certificate.
+	OAuth2IssuerURL string `json:"oauth2IssuerURL"`
+
+	// OAuth2ClientID is the OAuth2 client ID, required with
+	// OAuth2ClientSecret.
+	OAuth2ClientID string `json:"oauth2ClientID"`
+
+	// OAuth2ClientSecret is the OAuth2 client secret. Can't be combined with
+	// OAuth2PrivateKeyFile.
+	OAuth2ClientSecret string `json:"oauth2ClientSecret"`
+
+	// OAuth2PrivateKeyFile is the path to a JSON key file containing the
+	// client credentials, as provided by the authorization server. Can't be
+	// combined with OAuth2ClientSecret.
+	OAuth2PrivateKeyFile string `json:"oauth2PrivateKeyFile"`
+
+	// OAuth2Audience is the audience the access token is requested for,
+	// usually the URL of the Pulsar cluster.
+	OAuth2Audience string `json:"oauth2Audience"`
+
+	// OAuth2Scope is the scope of the requested access token.
+	OAuth2Scope string `json:"oauth2Scope"`
+
+	// Token is a JWT token the connector authenticates with. Can't be
+	// combined with TokenFilePath.
+	Token string `json:"token"`
+
+	// TokenFilePath is the path to a file containing a JWT token the connector
+	// authenticates with. The file is read again whenever a token is needed,
+	// so it can be rotated. Can't be combined with Token.
+	TokenFilePath string `json:"tokenFilePath"`
+
 	// DisableLogging disables pulsar client logs
 	DisableLogging bool `json:"disableLogging"`
+
+	// AdminURL is the URL of the Pulsar admin (web service) API. It is only
+	// needed by options that manage topic policies.
+	AdminURL string `json:"adminURL"`
+
+	// SchemaRegistryMaxRetries is the number of times creating the consumer or
+	// producer is retried when it fails, e.g. because the schema registry is
+	// temporarily unavailable. Permanent errors like an invalid configuration
+	// or a closed producer aren't retried. Retries are disabled by default.
+	SchemaRegistryMaxRetries int `json:"schemaRegistryMaxRetries" validate:"gt=-1"`
+
+	// SchemaRegistryRetryBackoff is the delay before the first retry, it is
+	// doubled after each failed attempt up to one minute.
+	SchemaRegistryRetryBackoff time.Duration `json:"schemaRegistryRetryBackoff" default:"1s"`
+}
+
+func (c Config) Validate() error {
+	if c.ListenerName != "" && strings.TrimSpace(c.ListenerName) == "" {
+		return fmt.Errorf("%q must not be blank", "listenerName")
+	}
+	if c.LookupTimeout < 0 {
+		return fmt.Errorf("%q must not be negative", "lookupTimeout")
+	}
+	if c.KeepAliveInterval <= 0 {
+		return fmt.Errorf("%q must be positive", "keepAliveInterval")
+	}
+	if c.MaxBackoff <= 0 {
+		return fmt.Errorf("%q must be positive", "maxBackoff")
+	}
+	if err := c.validateOAuth2(); err != nil {
+		return err
+	}
+	if err := c.validateToken(); err != nil {
+		return err
+	}
+	return nil
+}
+
+// validateOAuth2 checks that either no OAuth2 option or a complete set of
+// client credentials is configured.
+func (c Config) validateOAuth2() error {
+	if c.OAuth2IssuerURL == "" {
+		for name, val := range map[string]string{
+			"oauth2ClientID":       c.OAuth2ClientID,
+			"oauth2ClientSecret":   c.OAuth2ClientSecret,
+			"oauth2PrivateKeyFile": c.OAuth2PrivateKeyFile,
+			"oauth2Audience":       c.OAuth2Audience,
+			"oauth2Scope":          c.OAuth2Scope,
+		} {
+			if val != "" {
+				return fmt.Errorf("%q is required when %q is set", "oauth2IssuerURL", name)
+			}
+		}
+		return nil
+	}
+
+	switch {
+	case c.TLSCertificateFile != "" || c.TLSKeyFilePath != "":
+		return errors.New("TLS authentication and OAuth2 authentication can't be combined")
+	case c.OAuth2ClientSecret != "" && c.OAuth2PrivateKeyFile != "":
+		return fmt.Errorf("%q and %q can't be combined", "oauth2ClientSecret", "oauth2PrivateKeyFile")
+	case c.OAuth2ClientSecret == "" && c.OAuth2PrivateKeyFile == "":
+		return fmt.Errorf("%q or %q is required when %q is set", "oauth2ClientSecret", "oauth2PrivateKeyFile", "oauth2IssuerURL")
+	case c.OAuth2ClientSecret != "" && c.OAuth2ClientID == "":
+		return fmt.Errorf("%q is required when %q is set", "oauth2ClientID", "oauth2ClientSecret")
+	}
+	return nil
+}
+
+// validateToken checks that the token is configured at most once and is not
+// combined with another authentication method.
+func (c Config) validateToken() error {
+	switch {
+	case c.Token != "" && c.TokenFilePath != "":
+		return fmt.Errorf("%q and %q can't be combined, set only one of them", "token", "tokenFilePath")
+	case c.Token == "" && c.TokenFilePath == "":
+		return nil
+	case c.OAuth2IssuerURL != "":
+		return errors.New("token authentication and OAuth2 authentication can't be combined")
+	case c.TLSCertificateFile != "" || c.TLSKeyFilePath != "":
+		return errors.New("token authentication and TLS authentication can't be combined")
+	}
+	return nil
 }
 
 type SourceConfig struct {
@@ -71,8 +216,1340 @@ type SourceConfig struct {
 	// SubscriptionName is the name of the subscription to be used for
 	// consuming messages.
 	SubscriptionName string `json:"subscriptionName"`
+
+	// ConsumerName is the name of the consumer, shown in the stats of the
+	// topic and the admin UI. Generated by the client if empty.
+	ConsumerName string `json:"consumerName"`
+
+	// Topics is a comma separated list of topics consumed under the same
+	// subscription. The position of a record contains the last message read
+	// from each topic, so messages redelivered after a restart are skipped
+	// per topic. Can't be combined with Topic or TopicsPattern.
+	Topics []string `json:"topics"`
+
+	// TopicsPattern is a regular expression, all topics matching it are
+	// consumed under the same subscription, e.g.
+	// `persistent://tenant/ns/events-.*`. Can't be combined with Topic or
+	// Topics.
+	TopicsPattern string `json:"topicsPattern"`
+
+	// AutoDiscoveryPeriod is how often topics matching TopicsPattern are
+	// discovered, so newly created topics are consumed.
+	AutoDiscoveryPeriod time.Duration `json:"autoDiscoveryPeriod" default:"1m"`
+
+	// SubscriptionType defines how messages are delivered to the consumers of
+	// the subscription. Use "shared", "failover" or "key_shared" to run
+	// multiple connector instances against the same subscription.
+	SubscriptionType string `json:"subscriptionType" default:"exclusive" validate:"inclusion=exclusive|shared|failover|key_shared"`
+
+	// ReadCompacted reads the compacted view of the topic, which only
+	// contains the latest message of each key. Only supported by "exclusive"
+	// and "failover" subscriptions.
+	ReadCompacted bool `json:"readCompacted"`
+
+	// AckMode defines how records are acknowledged. "individual" acknowledges
+	// each message, "cumulative" acknowledges a message and all messages
+	// before it in the same partition, which is more efficient. Cumulative
+	// acknowledgements are only supported by "exclusive" and "failover"
+	// subscriptions and can't be combined with options that redeliver
+	// individual messages.
+	AckMode string `json:"ackMode" default:"individual" validate:"inclusion=individual|cumulative"`
+
+	// SubscriptionInitialPosition is the position a new subscription starts
+	// from, the "earliest" message available in the topic or the "latest"
+	// one. Existing subscriptions continue from their stored position. Any
+	// subscription type is accepted with either position, a new "shared" or
+	// "key_shared" subscription starting from "earliest" distributes the
+	// whole backlog across its consumers.
+	SubscriptionInitialPosition string `json:"subscriptionInitialPosition" default:"earliest" validate:"inclusion=earliest|latest"`
+
+	// SubscribeTimeout bounds subscribing to the topic, independently of
+	// OperationTimeout. Disabled when set to 0.
+	SubscribeTimeout time.Duration `json:"subscribeTimeout"`
+
+	// MeasureLag enables logging the lag between the publish time of each
+	// message and the time it was received by the source.
+	MeasureLag bool `json:"measureLag"`
+
+	// ReceiverQueueSize is the number of messages the consumer prefetches.
+	// Uses the client default when set to 0. Can't be combined with
+	// AutoScaleReceiverQueue or AdaptivePrefetch, which size the queue
+	// themselves.
+	ReceiverQueueSize int `json:"receiverQueueSize"`
+
+	// MaxTotalReceiverQueueSizeAcrossPartitions is the number of messages the
+	// consumer prefetches across all partitions of the consumed topics. The
+	// receive queue of each partition is shrunk so their total stays within
+	// the limit. Disabled when set to 0, can't be combined with
+	// TopicsPattern.
+	MaxTotalReceiverQueueSizeAcrossPartitions int `json:"maxTotalReceiverQueueSizeAcrossPartitions"`
+
+	// AutoScaleReceiverQueue enables scaling the receive queue based on the
+	// observed consumption rate. Read buffers the messages that are already
+	// available up to the queue size, which doubles when the buffer is filled
+	// completely and halves when less than half of it is used. The size starts
+	// at AutoScaleReceiverQueueMinSize and stays within the bounds. Can't be
+	// combined with ReadBatchSize or MessageListenerMode.
+	AutoScaleReceiverQueue bool `json:"autoScaleReceiverQueue"`
+
+	// AutoScaleReceiverQueueMinSize is the lower bound of the receive queue
+	// when AutoScaleReceiverQueue is enabled.
+	AutoScaleReceiverQueueMinSize int `json:"autoScaleReceiverQueueMinSize" default:"1" validate:"gt=0"`
+
+	// AutoScaleReceiverQueueMaxSize is the upper bound of the receive queue
+	// when AutoScaleReceiverQueue is enabled.
+	AutoScaleReceiverQueueMaxSize int `json:"autoScaleReceiverQueueMaxSize" default:"1000" validate:"gt=0"`
+
+	// EmitWatermarks emits watermark records carrying the minimum of the
+	// latest event times seen across partitions in the "pulsar.watermark"
+	// metadata field, enabling downstream windowing. Watermark records have
+	// no key and payload and don't need to be acknowledged.
+	EmitWatermarks bool `json:"emitWatermarks"`
+
+	// WatermarkInterval is the minimum time between two watermark records.
+	// A watermark is only emitted if it advanced since the last one.
+	WatermarkInterval time.Duration `json:"watermarkInterval" default:"10s"`
+
+	// AckTimeout is the time after which messages that were not acknowledged
+	// are redelivered, e.g. because processing got stuck. The Go client has
+	// no ack timeout, so it is enforced by the source like
+	// ProcessingDeadline, which it can't be combined with. Messages of a
+	// source that died are redelivered by the broker once the consumer
+	// disconnects. Disabled when set to 0.
+	AckTimeout time.Duration `json:"ackTimeout"`
+
+	// AckTimeoutTickDuration is how often messages are checked for an
+	// expired AckTimeout. Defaults to a quarter of AckTimeout when set to 0.
+	AckTimeoutTickDuration time.Duration `json:"ackTimeoutTickDuration"`
+
+	// ReadBatchSize is the maximum number of messages taken from the consumer
+	// at once. Read waits for the first message and buffers the messages that
+	// are already available up to the batch size, subsequent reads are served
+	// from the buffer. Disabled when set to 0 or 1, can't be combined with
+	// MessageListenerMode or AutoScaleReceiverQueue.
+	ReadBatchSize int `json:"readBatchSize"`
+
+	// NackRedeliveryDelay is the delay after which negatively acknowledged
+	// messages are redelivered.
+	NackRedeliveryDelay time.Duration `json:"nackRedeliveryDelay" default:"1m"`
+
+	// AdaptivePrefetch bounds the number of records that were read but not
+	// yet acknowledged. The bound starts at AdaptivePrefetchMin and grows
+	// while records are acknowledged within AdaptivePrefetchTargetLatency, up
+	// to AdaptivePrefetchMax, and shrinks when acknowledgements are slower.
+	// Can't be combined with AutoScaleReceiverQueue.
+	AdaptivePrefetch bool `json:"adaptivePrefetch"`
+
+	// AdaptivePrefetchMin is the lower bound of unacknowledged records.
+	AdaptivePrefetchMin int `json:"adaptivePrefetchMin" default:"10"`
+
+	// AdaptivePrefetchMax is the upper bound of unacknowledged records, it is
+	// also used as the size of the receive queue of the consumer.
+	AdaptivePrefetchMax int `json:"adaptivePrefetchMax" default:"1000"`
+
+	// AdaptivePrefetchTargetLatency is the time within which records need to
+	// be acknowledged for the bound of unacknowledged records to grow.
+	AdaptivePrefetchTargetLatency time.Duration `json:"adaptivePrefetchTargetLatency" default:"1s"`
+
+	// DLQTopic is the name of the topic where messages that exceeded
+	// DLQMaxDeliveries are routed to. Defaults to
+	// "<topic>-<subscriptionName>-DLQ" when a single topic is consumed.
+	DLQTopic string `json:"dlqTopic"`
+
+	// DLQFailureTopics routes rejected messages to a dead letter topic per
+	// failure type, in the format "type:topic". The failure types are
+	// "deserialization" for payloads that are not valid JSON, "schema" for
+	// messages that don't match the pinned schema version or JSON schema,
+	// and "processing" for messages that exceeded DLQMaxDeliveries, which
+	// replaces DLQTopic. Messages are routed as soon as they are rejected.
+	DLQFailureTopics []string `json:"dlqFailureTopics"`
+
+	// DLQMaxDeliveries is the maximum number of times a message is delivered
+	// before it is routed to the dead letter topic. Negatively acknowledged
+	// messages are redelivered after the nack redelivery delay and count as a
+	// delivery. Dead letter routing is disabled when set to 0.
+	DLQMaxDeliveries int `json:"dlqMaxDeliveries" validate:"gt=-1"`
+
+	// EnableRetry routes failed records to the retry letter topic, which the
+	// source consumes as well, so they are retried after RetryDelay. Messages
+	// are routed to the dead letter topic once they were retried
+	// DLQMaxDeliveries times, or 16 times if it is not set.
+	EnableRetry bool `json:"enableRetry"`
+
+	// RetryLetterTopic is the name of the topic failed records are retried
+	// from. Defaults to "<topic>-<subscriptionName>-RETRY".
+	RetryLetterTopic string `json:"retryLetterTopic"`
+
+	// RetryDelay is the delay after which a failed record is retried the
+	// first time.
+	RetryDelay time.Duration `json:"retryDelay" default:"1m"`
+
+	// RetryDelayMultiplier is the factor the retry delay grows by with each
+	// retry of a record, so the n-th retry happens after
+	// RetryDelay * RetryDelayMultiplier^(n-1). Must be at least 1.
+	RetryDelayMultiplier float64 `json:"retryDelayMultiplier" default:"1"`
+
+	// DLQDiagnosticProperties adds the original topic, failure reason and
+	// redelivery count as properties to messages routed to the dead letter
+	// topic.
+	DLQDiagnosticProperties bool `json:"dlqDiagnosticProperties"`
+
+	// DLQFailurePolicy defines what happens when the dead letter topic is
+	// unavailable when the source is opened. With "block" routing is retried
+	// until it succeeds, with "drop" messages that exceeded DLQMaxDeliveries
+	// are acknowledged and logged, and with "fail" the source fails to open.
+	DLQFailurePolicy string `json:"dlqFailurePolicy" default:"block" validate:"inclusion=block|drop|fail"`
+
+	// DLQSchemaDefinition is an Avro record schema used to wrap messages
+	// routed to the dead letter topic. The record must contain a "payload"
+	// field of type bytes, which is set to the original payload. The optional
+	// fields "key", "orderingKey", "originalTopic", "failureReason",
+	// "redeliveryCount" and "properties" are set to the failure metadata,
+	// other fields must have a default. The schema is registered on the dead
+	// letter topic.
+	DLQSchemaDefinition string `json:"dlqSchemaDefinition"`
+
+	// MessageListenerMode makes the consumer push messages into a channel
+	// that is drained by Read, instead of polling the consumer on each Read.
+	MessageListenerMode bool `json:"messageListenerMode"`
+
+	// InferPayloadType detects whether the payload contains JSON, text or
+	// binary data and sets the "pulsar.contentType" metadata accordingly.
+	// Payloads containing a JSON object are returned as structured data.
+	InferPayloadType bool `json:"inferPayloadType"`
+
+	// PreserveEncryptionContext passes encrypted messages through without
+	// decrypting them and stores their encryption context in the metadata,
+	// so a downstream system can decrypt the payload.
+	PreserveEncryptionContext bool `json:"preserveEncryptionContext"`
+
+	// EnableBatchIndexAck acknowledges individual messages of a batch on the
+	// broker, so that only unacknowledged messages of a batch are redelivered.
+	// Requires acknowledgmentAtBatchIndexLevelEnabled on the broker.
+	EnableBatchIndexAck bool `json:"enableBatchIndexAck"`
+
+	// AutoDecompressPayload detects payloads compressed with gzip or zstd by
+	// the producing application and decompresses them. The detected encoding
+	// is stored in the "pulsar.contentEncoding" metadata. Payloads that can't
+	// be decompressed are returned unchanged.
+	AutoDecompressPayload bool `json:"autoDecompressPayload"`
+
+	// FlushAcksOnCommit sends each acknowledgement to the broker as soon as
+	// Conduit commits the position of the record instead of grouping it with
+	// other acknowledgements, and waits for the broker's response. Errors
+	// returned by the broker fail the ack. Messages of a batch are only
+	// acknowledged once the whole batch is acked, unless enableBatchIndexAck
+	// is set.
+	FlushAcksOnCommit bool `json:"flushAcksOnCommit"`
+
+	// AckFlushCount is the number of acknowledgements the source batches
+	// before sending them to the broker. Replaces the acknowledgement
+	// grouping of the client. Disabled when set to 0.
+	AckFlushCount int `json:"ackFlushCount" validate:"gt=-1"`
+
+	// AckFlushBytes is the total payload size in bytes of the acknowledged
+	// messages at which the batched acknowledgements are sent to the broker,
+	// which keeps the amount of redelivered data bounded when message sizes
+	// vary widely. Replaces the acknowledgement grouping of the client.
+	// Disabled when set to 0.
+	AckFlushBytes int `json:"ackFlushBytes" validate:"gt=-1"`
+
+	// AckFlushInterval is the maximum time acknowledgements are batched when
+	// AckFlushCount or AckFlushBytes is set.
+	AckFlushInterval time.Duration `json:"ackFlushInterval" default:"100ms"`
+
+	// ReaderStartMessageID is a base64 encoded serialized message ID. If set,
+	// the source replays the topic with a reader starting at this message
+	// (inclusive) instead of consuming it through a subscription. Message IDs
+	// are encoded the same way as in the record position.
+	ReaderStartMessageID string `json:"readerStartMessageID"`
+
+	// ReaderMessageLimit is the number of messages read when replaying the
+	// topic from ReaderStartMessageID. Once the limit is reached the source
+	// produces no more records. Unlimited when set to 0.
+	ReaderMessageLimit int `json:"readerMessageLimit" validate:"gt=-1"`
+
+	// MaxReassembledSize is the maximum size in bytes of a message reassembled
+	// from chunks. Larger messages are logged, acknowledged and skipped. The
+	// limit is checked once the client reassembled the message, it keeps
+	// oversized records out of the pipeline. The memory used while chunks are
+	// reassembled is bounded by MaxPendingChunkedMessage and
+	// ExpireTimeOfIncompleteChunk instead. Disabled when set to 0.
+	MaxReassembledSize int `json:"maxReassembledSize" validate:"gt=-1"`
+
+	// MaxPendingChunkedMessage is the maximum number of chunked messages the
+	// client reassembles at the same time. The oldest incomplete message is
+	// discarded when the limit is reached.
+	MaxPendingChunkedMessage int `json:"maxPendingChunkedMessage" default:"100"`
+
+	// ExpireTimeOfIncompleteChunk is the time after which an incomplete
+	// chunked message is discarded by the client.
+	ExpireTimeOfIncompleteChunk time.Duration `json:"expireTimeOfIncompleteChunk" default:"1m"`
+
+	// NackInFlightOnShutdown releases messages that were read but not acked
+	// when the source is torn down by closing the consumer before anything
+	// else, so they are redelivered to other consumers of the subscription
+	// right away.
+	NackInFlightOnShutdown bool `json:"nackInFlightOnShutdown"`
+
+	// MetricsCardinality defines the labels of the Pulsar client metrics,
+	// "none", "tenant", "namespace" or "topic". Labelling by topic is the most
+	// detailed, but creates a time series per topic.
+	MetricsCardinality string `json:"metricsCardinality" default:"namespace" validate:"inclusion=none|tenant|namespace|topic"`
+
+	// MetricsAddress is the address, e.g. ":9090", the metrics of the Pulsar
+	// client and the source are served on under the "/metrics" path, so they
+	// can be scraped by Prometheus. Disabled if empty.
+	MetricsAddress string `json:"metricsAddress"`
+
+	// TeardownTimeout is how long teardown waits for records that were read
+	// but not acknowledged yet. Messages that are still not acknowledged are
+	// redelivered once the consumer is closed. Disabled when set to 0.
+	TeardownTimeout time.Duration `json:"teardownTimeout"`
+
+	// NotifySchemaChange stores the schema version of each message in the
+	// "pulsar.schemaVersion" metadata and logs a warning when it changes. The
+	// first message with a new version gets the "pulsar.schemaChanged"
+	// metadata set to "true".
+	NotifySchemaChange bool `json:"notifySchemaChange"`
+
+	// ResetSubscription moves the subscription to the "earliest" or "latest"
+	// message of the topic using the admin API before subscribing, to replay
+	// the topic without deleting the subscription. The reset is applied each
+	// time the source is opened, so it should be removed after the replay.
+	// Requires AdminURL and SubscriptionName.
+	ResetSubscription string `json:"resetSubscription" validate:"inclusion=earliest|latest"`
+
+	// StartFromTimestamp starts a new subscription at the first message
+	// published at or after this time, e.g. for backfills. Accepts an RFC
+	// 3339 timestamp or unix milliseconds. Ignored when resuming from a
+	// position. Requires a single topic.
+	StartFromTimestamp string `json:"startFromTimestamp"`
+
+	// GlobalOrderingWindow buffers received messages for this long and emits
+	// them in publish time order, approximating a global order across the
+	// partitions of a topic. Every message is delayed by up to the window and
+	// buffered messages are kept in memory. Messages arriving later than the
+	// window after a newer message are still emitted out of order, and the
+	// order relies on the clocks of the producers being in sync. Disabled
+	// when set to 0.
+	GlobalOrderingWindow time.Duration `json:"globalOrderingWindow"`
+
+	// EventTimeFrom is an RFC 3339 timestamp, messages with an earlier event
+	// time are acknowledged and skipped. Messages without an event time are
+	// filtered by their publish time.
+	EventTimeFrom string `json:"eventTimeFrom"`
+
+	// EventTimeTo is an RFC 3339 timestamp, messages with a later event time
+	// are acknowledged and skipped. Together with EventTimeFrom it bounds the
+	// replayed time range.
+	EventTimeTo string `json:"eventTimeTo"`
+
+	// PartitionCheckInterval is the interval at which the partition count of
+	// the topic is checked. If partitions were removed, e.g. by a forced
+	// deletion, the source subscribes again instead of failing. Added
+	// partitions are discovered by the Pulsar client. Disabled when set to 0.
+	PartitionCheckInterval time.Duration `json:"partitionCheckInterval"`
+
+	// AckLatencyMetrics records the time between reading and acknowledging
+	// each record in the "conduit_pulsar_source_ack_latency_seconds"
+	// Prometheus histogram, which helps diagnose slow downstream processing.
+	// The histogram is exposed together with the metrics of the Pulsar client.
+	AckLatencyMetrics bool `json:"ackLatencyMetrics"`
+
+	// PinnedSchemaVersion is the schema version of the topic the source reads,
+	// e.g. "2". Messages using another schema version, or no schema, are
+	// rejected: they are negatively acknowledged and routed to the dead
+	// letter topic if DLQMaxDeliveries is set, otherwise they are
+	// acknowledged and skipped. The version must exist on the topic.
+	// Requires AdminURL.
+	PinnedSchemaVersion string `json:"pinnedSchemaVersion"`
+
+	// ProcessingDeadline is the time within which a read record has to be
+	// acknowledged. Records that are not acknowledged in time are negatively
+	// acknowledged, so the message is redelivered after NackRedeliveryDelay,
+	// or after the retry delay if EnableRetry is set, instead of blocking
+	// progress. Disabled when set to 0.
+	ProcessingDeadline time.Duration `json:"processingDeadline"`
+
+	// JSONSchemaValidation is a JSON schema document, or the path of a file
+	// containing it, that the payloads of consumed messages are validated
+	// against, after decompressing them if AutoDecompressPayload is set.
+	// Messages with invalid payloads are negatively acknowledged, so they
+	// are routed to the dead letter topic once DLQMaxDeliveries is exceeded.
+	// Without a dead letter topic they are acknowledged and dropped.
+	JSONSchemaValidation string `json:"jsonSchemaValidation"`
+
+	// SchemaIncompatibilityAction defines what happens to messages that don't
+	// match PinnedSchemaVersion or JSONSchemaValidation. With "skip" they are
+	// acknowledged and skipped, with "dlq" they are routed to the dead letter
+	// topic and with "raw" they are passed through with the raw payload and
+	// the reason in the "pulsar.schemaIncompatible" metadata field. If unset,
+	// they are handled as described for the schema options.
+	SchemaIncompatibilityAction string `json:"schemaIncompatibilityAction" validate:"inclusion=skip|dlq|raw"`
+
+	// SchemaType is the schema the source subscribes with. With "json" and
+	// "avro" the payload is decoded with SchemaDefinition and returned as
+	// structured data, with "string" and "none" it is returned as raw data.
+	// Messages that can't be decoded are rejected like messages that don't
+	// match JSONSchemaValidation.
+	SchemaType string `json:"schemaType" default:"none" validate:"inclusion=none|json|avro|string"`
+
+	// SchemaDefinition is the Avro schema definition used to decode payloads
+	// when SchemaType is "json" or "avro", or the path of a file containing
+	// it.
+	SchemaDefinition string `json:"schemaDefinition"`
+}
+
+func (c SourceConfig) Validate() error {
+	if err := c.Config.Validate(); err != nil {
+		return err
+	}
+	if err := c.validateTopics(); err != nil {
+		return err
+	}
+	if c.ackBatchingEnabled() {
+		if c.AckFlushInterval <= 0 {
+			return fmt.Errorf("%q must be positive", SourceConfigAckFlushInterval)
+		}
+		if c.FlushAcksOnCommit {
+			return fmt.Errorf("%q can't be combined with batching acknowledgements", SourceConfigFlushAcksOnCommit)
+		}
+	}
+	if c.SubscribeTimeout < 0 {
+		return fmt.Errorf("%q must not be negative", SourceConfigSubscribeTimeout)
+	}
+	if c.GlobalOrderingWindow < 0 {
+		return fmt.Errorf("%q must not be negative", SourceConfigGlobalOrderingWindow)
+	}
+	if c.ProcessingDeadline < 0 {
+		return fmt.Errorf("%q must not be negative", SourceConfigProcessingDeadline)
+	}
+	if c.PartitionCheckInterval < 0 {
+		return fmt.Errorf("%q must not be negative", SourceConfigPartitionCheckInterval)
+	}
+	if _, err := parseEventTimeRange(c.EventTimeFrom, c.EventTimeTo); err != nil {
+		return err
+	}
+	if isTopicTemplate(c.Topic) {
+		return fmt.Errorf("%q can only be a template in the destination", SourceConfigTopic)
+	}
+	if err := c.validateDLQFailureTopics(); err != nil {
+		return err
+	}
+	if err := c.validateRetry(); err != nil {
+		return err
+	}
+	if err := c.validateSchemaIncompatibilityAction(); err != nil {
+		return err
+	}
+	if err := c.validateSchemaType(); err != nil {
+		return err
+	}
+	if c.DLQSchemaDefinition != "" {
+		if _, err := newDLQEnvelope(c.DLQSchemaDefinition); err != nil {
+			return fmt.Errorf("invalid %q: %w", SourceConfigDlqSchemaDefinition, err)
+		}
+	}
+	if c.PinnedSchemaVersion != "" {
+		if _, err := parseSchemaVersion(c.PinnedSchemaVersion); err != nil {
+			return fmt.Errorf("invalid %q: %w", SourceConfigPinnedSchemaVersion, err)
+		}
+		if c.AdminURL == "" {
+			return fmt.Errorf("%q is required when %q is set", SourceConfigAdminURL, SourceConfigPinnedSchemaVersion)
+		}
+	}
+	if c.StartFromTimestamp != "" {
+		if _, err := parseStartTimestamp(c.StartFromTimestamp); err != nil {
+			return fmt.Errorf("invalid %q: %w", SourceConfigStartFromTimestamp, err)
+		}
+		switch {
+		case c.ResetSubscription != "":
+			return fmt.Errorf("%q can't be combined with %q", SourceConfigStartFromTimestamp, SourceConfigResetSubscription)
+		case c.ReaderStartMessageID != "":
+			return fmt.Errorf("%q can't be combined with %q", SourceConfigStartFromTimestamp, SourceConfigReaderStartMessageID)
+		}
+	}
+	if c.ResetSubscription != "" && c.AdminURL == "" {
+		return fmt.Errorf("%q is required when %q is set", SourceConfigAdminURL, SourceConfigResetSubscription)
+	}
+	if c.ResetSubscription != "" && c.SubscriptionName == "" {
+		return fmt.Errorf("%q is required when %q is set", SourceConfigSubscriptionName, SourceConfigResetSubscription)
+	}
+	if c.ReaderStartMessageID != "" {
+		if _, err := parseReaderStartMessageID(c.ReaderStartMessageID); err != nil {
+			return fmt.Errorf("invalid %q: %w", SourceConfigReaderStartMessageID, err)
+		}
+	}
+	if c.ReaderMessageLimit > 0 && c.ReaderStartMessageID == "" {
+		return fmt.Errorf("%q is required when %q is set", SourceConfigReaderStartMessageID, SourceConfigReaderMessageLimit)
+	}
+	if c.ReadCompacted && (c.SubscriptionType == SubscriptionTypeShared || c.SubscriptionType == SubscriptionTypeKeyShared) {
+		return fmt.Errorf("%q requires a %q or %q subscription, got %q", SourceConfigReadCompacted, SubscriptionTypeExclusive, SubscriptionTypeFailover, c.SubscriptionType)
+	}
+	if err := c.validateCumulativeAck(); err != nil {
+		return err
+	}
+	if c.EmitWatermarks {
+		switch {
+		case c.WatermarkInterval < 0:
+			return fmt.Errorf("%q must not be negative", SourceConfigWatermarkInterval)
+		case c.ReaderStartMessageID != "":
+			return fmt.Errorf("%q can't be combined with %q", SourceConfigEmitWatermarks, SourceConfigReaderStartMessageID)
+		}
+	}
+	if c.TeardownTimeout < 0 {
+		return fmt.Errorf("%q must not be negative", SourceConfigTeardownTimeout)
+	}
+	if c.AckTimeout < 0 {
+		return fmt.Errorf("%q must not be negative", SourceConfigAckTimeout)
+	}
+	if c.AckTimeout > 0 && c.ProcessingDeadline > 0 {
+		return fmt.Errorf("%q can't be combined with %q", SourceConfigAckTimeout, SourceConfigProcessingDeadline)
+	}
+	if c.AckTimeoutTickDuration < 0 {
+		return fmt.Errorf("%q must not be negative", SourceConfigAckTimeoutTickDuration)
+	}
+	if c.ReadBatchSize < 0 {
+		return fmt.Errorf("%q must not be negative", SourceConfigReadBatchSize)
+	}
+	if c.ReadBatchSize > 1 && c.MessageListenerMode {
+		return fmt.Errorf("%q can't be combined with %q", SourceConfigReadBatchSize, SourceConfigMessageListenerMode)
+	}
+	if c.ReceiverQueueSize < 0 {
+		return fmt.Errorf("%q must not be negative", SourceConfigReceiverQueueSize)
+	}
+	if c.MaxTotalReceiverQueueSizeAcrossPartitions < 0 {
+		return fmt.Errorf("%q must not be negative", SourceConfigMaxTotalReceiverQueueSizeAcrossPartitions)
+	}
+	if c.MaxTotalReceiverQueueSizeAcrossPartitions > 0 && c.TopicsPattern != "" {
+		return fmt.Errorf("%q can't be combined with %q", SourceConfigMaxTotalReceiverQueueSizeAcrossPartitions, SourceConfigTopicsPattern)
+	}
+	if c.ReceiverQueueSize > 0 {
+		switch {
+		case c.AutoScaleReceiverQueue:
+			return fmt.Errorf("%q can't be combined with %q", SourceConfigReceiverQueueSize, SourceConfigAutoScaleReceiverQueue)
+		case c.AdaptivePrefetch:
+			return fmt.Errorf("%q can't be combined with %q", SourceConfigReceiverQueueSize, SourceConfigAdaptivePrefetch)
+		}
+	}
+	if c.MaxPendingChunkedMessage <= 0 {
+		return fmt.Errorf("%q must be positive", SourceConfigMaxPendingChunkedMessage)
+	}
+	if c.ExpireTimeOfIncompleteChunk <= 0 {
+		return fmt.Errorf("%q must be positive", SourceConfigExpireTimeOfIncompleteChunk)
+	}
+	if c.AutoScaleReceiverQueue {
+		switch {
+		case c.AutoScaleReceiverQueueMaxSize < c.AutoScaleReceiverQueueMinSize:
+			return fmt.Errorf("%q must not be less than %q", SourceConfigAutoScaleReceiverQueueMaxSize, SourceConfigAutoScaleReceiverQueueMinSize)
+		case c.ReadBatchSize > 1:
+			return fmt.Errorf("%q can't be combined with %q", SourceConfigAutoScaleReceiverQueue, SourceConfigReadBatchSize)
+		case c.MessageListenerMode:
+			return fmt.Errorf("%q can't be combined with %q", SourceConfigAutoScaleReceiverQueue, SourceConfigMessageListenerMode)
+		}
+	}
+	if c.NackRedeliveryDelay < 0 {
+		return fmt.Errorf("%q must not be negative", SourceConfigNackRedeliveryDelay)
+	}
+	if c.AdaptivePrefetch {
+		switch {
+		case c.AdaptivePrefetchMin <= 0:
+			return fmt.Errorf("%q must be positive", SourceConfigAdaptivePrefetchMin)
+		case c.AdaptivePrefetchMax < c.AdaptivePrefetchMin:
+			return fmt.Errorf("%q must not be less than %q", SourceConfigAdaptivePrefetchMax, SourceConfigAdaptivePrefetchMin)
+		case c.AdaptivePrefetchTargetLatency <= 0:
+			return fmt.Errorf("%q must be positive", SourceConfigAdaptivePrefetchTargetLatency)
+		case c.AutoScaleReceiverQueue:
+			return fmt.Errorf("%q can't be combined with %q", SourceConfigAdaptivePrefetch, SourceConfigAutoScaleReceiverQueue)
+		case c.ReaderStartMessageID != "":
+			return fmt.Errorf("%q can't be combined with %q", SourceConfigAdaptivePrefetch, SourceConfigReaderStartMessageID)
+		}
+	}
+	return nil
+}
+
+// validateCumulativeAck checks that no option redelivers individual messages
+// when acknowledging cumulatively, as a cumulative ack of a later message
+// would also acknowledge the redelivered message.
+func (c SourceConfig) validateCumulativeAck() error {
+	if c.AckMode != AckModeCumulative {
+		return nil
+	}
+	schemaRedelivery := (c.PinnedSchemaVersion != "" || c.JSONSchemaValidation != "") &&
+		c.SchemaIncompatibilityAction != SchemaIncompatibilityActionSkip &&
+		c.SchemaIncompatibilityAction != SchemaIncompatibilityActionRaw
+	switch {
+	case c.SubscriptionType == SubscriptionTypeShared || c.SubscriptionType == SubscriptionTypeKeyShared:
+		return fmt.Errorf("%q %q can't be combined with %q %q", SourceConfigAckMode, c.AckMode, SourceConfigSubscriptionType, c.SubscriptionType)
+	case c.ProcessingDeadline > 0:
+		return fmt.Errorf("%q %q can't be combined with %q", SourceConfigAckMode, c.AckMode, SourceConfigProcessingDeadline)
+	case c.AckTimeout > 0:
+		return fmt.Errorf("%q %q can't be combined with %q", SourceConfigAckMode, c.AckMode, SourceConfigAckTimeout)
+	case c.DLQMaxDeliveries > 0:
+		return fmt.Errorf("%q %q can't be combined with %q", SourceConfigAckMode, c.AckMode, SourceConfigDlqMaxDeliveries)
+	case schemaRedelivery && c.PinnedSchemaVersion != "":
+		return fmt.Errorf("%q %q can't be combined with %q unless %q is %q or %q", SourceConfigAckMode, c.AckMode, SourceConfigPinnedSchemaVersion, SourceConfigSchemaIncompatibilityAction, SchemaIncompatibilityActionSkip, SchemaIncompatibilityActionRaw)
+	case schemaRedelivery:
+		return fmt.Errorf("%q %q can't be combined with %q unless %q is %q or %q", SourceConfigAckMode, c.AckMode, SourceConfigJsonSchemaValidation, SourceConfigSchemaIncompatibilityAction, SchemaIncompatibilityActionSkip, SchemaIncompatibilityActionRaw)
+	case c.NackInFlightOnShutdown:
+		return fmt.Errorf("%q %q can't be combined with %q", SourceConfigAckMode, c.AckMode, SourceConfigNackInFlightOnShutdown)
+	case c.EnableRetry:
+		return fmt.Errorf("%q %q can't be combined with %q", SourceConfigAckMode, c.AckMode, SourceConfigEnableRetry)
+	}
+	return nil
+}
+
+// validateRetry checks that failed records can be retried from the retry
+// letter topic.
+func (c SourceConfig) validateRetry() error {
+	if !c.EnableRetry {
+		if c.RetryLetterTopic != "" {
+			return fmt.Errorf("%q is required when %q is set", SourceConfigEnableRetry, SourceConfigRetryLetterTopic)
+		}
+		return nil
+	}
+	switch {
+	case c.RetryDelay < 0:
+		return fmt.Errorf("%q must not be negative", SourceConfigRetryDelay)
+	case c.RetryDelayMultiplier < 1:
+		return fmt.Errorf("%q must be at least 1", SourceConfigRetryDelayMultiplier)
+	case c.RetryLetterTopic == "" && len(c.topics()) != 1:
+		return fmt.Errorf("%q is required when %q is enabled and multiple topics are consumed", SourceConfigRetryLetterTopic, SourceConfigEnableRetry)
+	case c.TopicsPattern != "":
+		// the client can't retry messages of pattern subscriptions
+		return fmt.Errorf("%q can't be combined with %q", SourceConfigEnableRetry, SourceConfigTopicsPattern)
+	case c.ReaderStartMessageID != "":
+		return fmt.Errorf("%q can't be combined with %q", SourceConfigEnableRetry, SourceConfigReaderStartMessageID)
+	}
+	return nil
+}
+
+// validateSchemaIncompatibilityAction checks that a schema is configured and
+// incompatible messages can be routed to a dead letter topic if requested.
+func (c SourceConfig) validateSchemaIncompatibilityAction() error {
+	switch {
+	case c.SchemaIncompatibilityAction == "":
+		return nil
+	case c.PinnedSchemaVersion == "" && c.JSONSchemaValidation == "":
+		return fmt.Errorf("%q or %q is required when %q is set", SourceConfigPinnedSchemaVersion, SourceConfigJsonSchemaValidation, SourceConfigSchemaIncompatibilityAction)
+	case c.SchemaIncompatibilityAction == SchemaIncompatibilityActionDLQ && !c.hasSchemaDLQ():
+		return fmt.Errorf("%q or a %q topic in %q is required when %q is %q", SourceConfigDlqMaxDeliveries, FailureTypeSchema, SourceConfigDlqFailureTopics, SourceConfigSchemaIncompatibilityAction, SchemaIncompatibilityActionDLQ)
+	}
+	return nil
+}
+
+// validateSchemaType checks that payloads decoded with a schema are not
+// processed by options expecting the raw payload.
+func (c SourceConfig) validateSchemaType() error {
+	decoded := c.SchemaType == SchemaTypeJSON || c.SchemaType == SchemaTypeAvro
+	switch {
+	case decoded && c.SchemaDefinition == "":
+		return fmt.Errorf("%q is required when %q is %q", SourceConfigSchemaDefinition, SourceConfigSchemaType, c.SchemaType)
+	case !decoded && c.SchemaDefinition != "":
+		return fmt.Errorf("%q requires %q to be %q or %q", SourceConfigSchemaDefinition, SourceConfigSchemaType, SchemaTypeJSON, SchemaTypeAvro)
+	case decoded && c.InferPayloadType:
+		return fmt.Errorf("%q can't be combined with %q", SourceConfigSchemaType, SourceConfigInferPayloadType)
+	case decoded && c.AutoDecompressPayload:
+		return fmt.Errorf("%q can't be combined with %q", SourceConfigSchemaType, SourceConfigAutoDecompressPayload)
+	}
+	return nil
+}
+
+// validateDLQFailureTopics checks the mapping of failure types to dead letter
+// topics and that messages exceeding the max deliveries have a dead letter
+// topic.
+func (c SourceConfig) validateDLQFailureTopics() error {
+	failureTopics, err := parseDLQFailureTopics(c.DLQFailureTopics)
+	if err != nil {
+		return fmt.Errorf("invalid %q: %w", SourceConfigDlqFailureTopics, err)
+	}
+	_, processing := failureTopics[FailureTypeProcessing]
+	switch {
+	case processing && c.DLQTopic != "":
+		return fmt.Errorf("%q can't be combined with a %q topic in %q", SourceConfigDlqTopic, FailureTypeProcessing, SourceConfigDlqFailureTopics)
+	case processing && c.DLQMaxDeliveries == 0:
+		return fmt.Errorf("%q is required when a %q topic is set in %q", SourceConfigDlqMaxDeliveries, FailureTypeProcessing, SourceConfigDlqFailureTopics)
+	case c.DLQMaxDeliveries > 0 && c.DLQTopic == "" && !processing && len(c.topics()) != 1:
+		return fmt.Errorf("%q is required when %q is set and multiple topics are consumed", SourceConfigDlqTopic, SourceConfigDlqMaxDeliveries)
+	case c.EnableRetry && c.DLQTopic == "" && !processing && len(c.topics()) != 1:
+		return fmt.Errorf("%q is required when %q is enabled and multiple topics are consumed", SourceConfigDlqTopic, SourceConfigEnableRetry)
+	case len(failureTopics) > 0 && c.ReaderStartMessageID != "":
+		return fmt.Errorf("%q can't be combined with %q", SourceConfigDlqFailureTopics, SourceConfigReaderStartMessageID)
+	}
+	return nil
+}
+
+// dlqTopic returns the dead letter topic, which defaults to the topic name
+// followed by the subscription name and the suffix "-DLQ", like the dead
+// letter topics created by Pulsar.
+func (c SourceConfig) dlqTopic() string {
+	if c.DLQTopic != "" {
+		return c.DLQTopic
+	}
+	return fmt.Sprintf("%s-%s-DLQ", c.topics()[0], c.SubscriptionName)
+}
+
+// retryLetterTopic returns the configured retry letter topic, or the topic the
+// client uses by default.
+func (c SourceConfig) retryLetterTopic() string {
+	if c.RetryLetterTopic != "" {
+		return c.RetryLetterTopic
+	}
+	return fmt.Sprintf("%s-%s-RETRY", c.topics()[0], c.SubscriptionName)
+}
+
+// ackBatchingEnabled returns true if any flush threshold of the
+// acknowledgement batch is configured.
+func (c SourceConfig) ackBatchingEnabled() bool {
+	return c.AckFlushCount > 0 || c.AckFlushBytes > 0
+}
+
+// validateTopics checks that the topics are configured in exactly one way and
+// that options requiring a single known topic are not combined with others.
+func (c SourceConfig) validateTopics() error {
+	var configured int
+	for _, set := range []bool{c.Topic != "", len(c.Topics) > 0, c.TopicsPattern != ""} {
+		if set {
+			configured++
+		}
+	}
+	switch {
+	case configured == 0:
+		return fmt.Errorf("one of %q, %q or %q is required", SourceConfigTopic, SourceConfigTopics, SourceConfigTopicsPattern)
+	case configured > 1:
+		return fmt.Errorf("only one of %q, %q and %q can be set", SourceConfigTopic, SourceConfigTopics, SourceConfigTopicsPattern)
+	}
+
+	if c.TopicsPattern != "" {
+		if _, err := regexp.Compile(c.TopicsPattern); err != nil {
+			return fmt.Errorf("invalid %q: %w", SourceConfigTopicsPattern, err)
+		}
+		if c.AutoDiscoveryPeriod <= 0 {
+			return fmt.Errorf("%q must be positive", SourceConfigAutoDiscoveryPeriod)
+		}
+		// the matching topics are not known upfront
+		switch {
+		case c.ResetSubscription != "":
+			return fmt.Errorf("%q can't be combined with %q", SourceConfigResetSubscription, SourceConfigTopicsPattern)
+		case c.PinnedSchemaVersion != "":
+			return fmt.Errorf("%q can't be combined with %q", SourceConfigPinnedSchemaVersion, SourceConfigTopicsPattern)
+		}
+	}
+
+	if len(c.topics()) != 1 {
+		switch {
+		case c.ReaderStartMessageID != "":
+			return fmt.Errorf("%q requires a single topic", SourceConfigReaderStartMessageID)
+		case c.PartitionCheckInterval > 0:
+			return fmt.Errorf("%q requires a single topic", SourceConfigPartitionCheckInterval)
+		case c.StartFromTimestamp != "":
+			// multi topic consumers can't seek
+			return fmt.Errorf("%q requires a single topic", SourceConfigStartFromTimestamp)
+		}
+	}
+	return nil
+}
+
+// topics returns the distinct topics the source consumes from. It returns no
+// topics if they are matched by TopicsPattern.
+func (c SourceConfig) topics() []string {
+	var topics []string
+	seen := make(map[string]bool)
+	for _, topic := range append([]string{c.Topic}, c.Topics...) {
+		if topic == "" || seen[topic] {
+			continue
+		}
+		seen[topic] = true
+		topics = append(topics, topic)
+	}
+	return topics
 }
 
 type DestinationConfig struct {
 	Config
+
+	// EnableTopicDeduplication enables broker-side message deduplication on
+	// the topic before producing. Requires AdminURL and admin permissions on
+	// the topic.
+	EnableTopicDeduplication bool `json:"enableTopicDeduplication"`
+
+	// DedupSnapshotInterval is how often the broker takes a snapshot of the
+	// deduplication state of the topic, so it survives broker restarts. The
+	// interval is rounded down to whole seconds. Requires
+	// EnableTopicDeduplication. Uses the broker default when set to 0.
+	DedupSnapshotInterval time.Duration `json:"dedupSnapshotInterval"`
+
+	// NullValueMarker is a hex encoded byte sequence that is sent as the
+	// message payload instead of an empty payload when a record has no data
+	// (e.g. "00"). If empty, records are sent unchanged.
+	NullValueMarker string `json:"nullValueMarker"`
+
+	// ProduceAckTimeout bounds how long the destination waits for the broker
+	// to confirm a sent message before treating the send as failed. It is
+	// independent of the send timeout of the producer and of
+	// OperationTimeout. Disabled when set to 0.
+	ProduceAckTimeout time.Duration `json:"produceAckTimeout"`
+
+	// SendTimeout is the time after which the producer fails a message the
+	// broker didn't confirm. The producer stays usable after a timeout, the
+	// send can be retried by listing "timeout" in ProduceRetryableErrors.
+	// Disabled when set to 0.
+	SendTimeout time.Duration `json:"sendTimeout"`
+
+	// OrderingGuarantee defines how messages are routed to the partitions of
+	// the topic. With "none" messages are spread across all partitions, with
+	// "partition" all messages go to a single partition and with "key"
+	// messages with the same key go to the same partition.
+	OrderingGuarantee string `json:"orderingGuarantee" default:"key" validate:"inclusion=none|partition|key"`
+
+	// PriorityMetadataKey is the metadata key containing the integer priority
+	// of a record. Records in a batch are produced in order of their priority,
+	// highest first. Records with the same key keep their order. Pulsar has
+	// no native message priority, so records written in different batches
+	// are not reordered. Requires an OrderingGuarantee other than "key" and
+	// can't be combined with tracking sequence IDs.
+	PriorityMetadataKey string `json:"priorityMetadataKey"`
+
+	// ForceSinglePartition routes all records to ForceSinglePartitionTarget
+	// regardless of their key, guaranteeing a strict global order. This limits
+	// the throughput to what a single partition can handle. Overrides
+	// OrderingGuarantee.
+	ForceSinglePartition bool `json:"forceSinglePartition"`
+
+	// ForceSinglePartitionTarget is the index of the partition all records are
+	// routed to when ForceSinglePartition is enabled.
+	ForceSinglePartitionTarget int `json:"forceSinglePartitionTarget" validate:"gt=-1"`
+
+	// BacklogQuotaMaxRetries is the number of times sending a message is
+	// retried when the broker rejects it because the backlog quota of the topic
+	// is exceeded. Retries are disabled by default.
+	BacklogQuotaMaxRetries int `json:"backlogQuotaMaxRetries" validate:"gt=-1"`
+
+	// BacklogQuotaRetryBackoff is the delay before the first retry, it is
+	// doubled after each failed attempt up to one minute.
+	BacklogQuotaRetryBackoff time.Duration `json:"backlogQuotaRetryBackoff" default:"1s"`
+
+	// KeyField references the record field used as the message key, which
+	// drives routing to partitions. Can be ".Key", ".Metadata.<key>" or
+	// ".Payload.After.<field>". Defaults to the record key.
+	KeyField string `json:"keyField"`
+
+	// KeyJSONPath is a JSONPath expression selecting the message key in the
+	// JSON payload of the record, e.g. `$.user.id`, for records that don't
+	// carry a separate key. Only child names and array indices are
+	// supported. Can't be combined with KeyField.
+	KeyJSONPath string `json:"keyJSONPath"`
+
+	// KeyJSONPathFallback defines what happens if the key can't be extracted
+	// with KeyJSONPath, because the payload is not JSON or has no value at
+	// the path. With "key" the record key is used, with "empty" the message
+	// is produced without a key and with "fail" the write fails.
+	KeyJSONPathFallback string `json:"keyJSONPathFallback" default:"key" validate:"inclusion=key|empty|fail"`
+
+	// PartitionByField is a JSONPath expression selecting a field in the JSON
+	// payload of the record, e.g. `$.customer.id`. Messages are routed to the
+	// partition computed from a hash of the field value instead of the key,
+	// so records with the same value land on the same partition. The value
+	// is added to the "conduit.partitionKey" property and the write fails if
+	// the payload has no value at the path.
+	PartitionByField string `json:"partitionByField"`
+
+	// OrderingKeyField references the record field used as the ordering key
+	// of the message, e.g. ".Key" to keep ordering by the original key while
+	// routing by KeyField. Same format as KeyField. Defaults to the
+	// "pulsar.orderingKey" metadata set by the source.
+	OrderingKeyField string `json:"orderingKeyField"`
+
+	// LogProduceResults logs the message ID assigned by the broker for each
+	// confirmed message and the error for each failed message.
+	LogProduceResults bool `json:"logProduceResults"`
+
+	// LogProduceResultsSampleRate logs only every n-th confirmed message when
+	// LogProduceResults is enabled. Failed messages are always logged.
+	LogProduceResultsSampleRate int `json:"logProduceResultsSampleRate" default:"1" validate:"gt=0"`
+
+	// ProducerAccessMode defines whether other producers can produce to the
+	// topic at the same time. With "shared" multiple producers are allowed,
+	// with "exclusive" opening the destination fails if another producer is
+	// connected and with "waitForExclusive" the destination waits until it
+	// can acquire exclusive access.
+	ProducerAccessMode string `json:"producerAccessMode" default:"shared" validate:"inclusion=shared|exclusive|waitForExclusive"`
+
+	// ExclusiveWaitTimeout is the maximum time the destination waits for
+	// exclusive access to the topic when ProducerAccessMode is
+	// "waitForExclusive". Waits indefinitely when set to 0.
+	ExclusiveWaitTimeout time.Duration `json:"exclusiveWaitTimeout"`
+
+	// TopicNotFoundPolicy defines what happens when the topic is deleted while
+	// producing to it. With "error" the write fails, with "recreate" the topic
+	// is created using the admin API with the partitions it had and with
+	// "buffer" the destination waits until the topic exists again. The
+	// message is resent in both cases.
+	TopicNotFoundPolicy string `json:"topicNotFoundPolicy" default:"error" validate:"inclusion=error|recreate|buffer"`
+
+	// TopicNotFoundRetryInterval is the delay between attempts to reconnect
+	// to the topic when TopicNotFoundPolicy is "buffer".
+	TopicNotFoundRetryInterval time.Duration `json:"topicNotFoundRetryInterval" default:"1s"`
+
+	// AuditMetadata adds the properties "conduit.audit.connector",
+	// "conduit.audit.instanceID" and "conduit.audit.producedAt" to each
+	// produced message, to track its provenance across pipelines.
+	AuditMetadata bool `json:"auditMetadata"`
+
+	// ForwardAllMetadata produces every metadata field of the record as a
+	// message property. By default only the fields under
+	// "pulsar.properties." are produced, with the prefix stripped, so the
+	// properties read by the source are preserved. A field under the prefix
+	// takes precedence over a field with the same name without it.
+	ForwardAllMetadata bool `json:"forwardAllMetadata"`
+
+	// IdempotencyKeyField references the record field containing an
+	// idempotency key. Records with a key that was already produced within
+	// IdempotencyWindow are skipped. Same format as KeyField. Records with an
+	// empty key are always produced.
+	IdempotencyKeyField string `json:"idempotencyKeyField"`
+
+	// IdempotencyWindow is how long the idempotency key of a produced record
+	// is remembered. Keys are kept in memory and not persisted across
+	// restarts.
+	IdempotencyWindow time.Duration `json:"idempotencyWindow" default:"5m"`
+
+	// AdaptiveThrottling slows down producing when the send queue or the
+	// memory buffer of the client is full, instead of blocking until there
+	// is room. The delay between sends grows while the pressure persists and
+	// shrinks as it eases. Only asynchronous sends fill the queue, so it
+	// requires the write buffer.
+	AdaptiveThrottling bool `json:"adaptiveThrottling"`
+
+	// AdaptiveThrottlingMaxDelay is the maximum delay between sends when
+	// AdaptiveThrottling is enabled.
+	AdaptiveThrottlingMaxDelay time.Duration `json:"adaptiveThrottlingMaxDelay" default:"1s"`
+
+	// LargeMessageTopic is the topic messages with a payload larger than
+	// LargeMessageThreshold are routed to, e.g. for specialized handling.
+	// Smaller messages are produced to Topic.
+	LargeMessageTopic string `json:"largeMessageTopic"`
+
+	// LargeMessageThreshold is the payload size in bytes above which messages
+	// are routed to LargeMessageTopic.
+	LargeMessageThreshold int `json:"largeMessageThreshold" validate:"gt=-1"`
+
+	// ProduceRetryableErrors is a comma separated list of error codes for
+	// which sending a message is retried, all other errors fail the write
+	// right away. Supported codes are "timeout", "producerBusy",
+	// "producerQueueFull", "memoryBufferFull", "messageTooBig",
+	// "topicTerminated", "schemaIncompatible" and "producerFenced".
+	ProduceRetryableErrors []string `json:"produceRetryableErrors" default:"timeout,producerBusy"`
+
+	// ProduceMaxRetries is the number of times sending a message is retried
+	// when it fails with one of ProduceRetryableErrors. Retries are disabled
+	// by default.
+	ProduceMaxRetries int `json:"produceMaxRetries" validate:"gt=-1"`
+
+	// ProduceRetryBackoff is the delay before the first retry, it is doubled
+	// after each failed attempt up to one minute.
+	ProduceRetryBackoff time.Duration `json:"produceRetryBackoff" default:"1s"`
+
+	// EncryptionKeys is a comma separated list of key names used to encrypt
+	// the produced messages. Enables end-to-end encryption when set.
+	EncryptionKeys []string `json:"encryptionKeys"`
+
+	// EncryptionPublicKeyPath is the path to the PEM encoded public key used
+	// to encrypt the data key of the messages. The file is read again on
+	// each key rotation.
+	EncryptionPublicKeyPath string `json:"encryptionPublicKeyPath"`
+
+	// EncryptionKeyRotationInterval is the interval at which a new data key
+	// is generated and the public key is reloaded, so rotated keys are picked
+	// up without a restart. Disabled when set to 0.
+	EncryptionKeyRotationInterval time.Duration `json:"encryptionKeyRotationInterval"`
+
+	// WriteBufferMaxRecords is the number of records sent asynchronously
+	// before the destination flushes the producer and waits for the broker to
+	// confirm them. This is independent of the batching of the producer.
+	// Disabled when set to 0.
+	WriteBufferMaxRecords int `json:"writeBufferMaxRecords" validate:"gt=-1"`
+
+	// WriteBufferMaxBytes is the total payload size in bytes of the records
+	// sent asynchronously before the destination flushes the producer.
+	// Disabled when set to 0.
+	WriteBufferMaxBytes int `json:"writeBufferMaxBytes" validate:"gt=-1"`
+
+	// WriteBufferFlushTimeout is the maximum time records are sent
+	// asynchronously before the destination flushes the producer. Disabled
+	// when set to 0. The buffer is always flushed at the end of a write, so
+	// records are never held back between writes. Buffered records are not
+	// retried, so the write buffer can't be combined with ProduceMaxRetries,
+	// BacklogQuotaMaxRetries or CircuitBreakerThreshold.
+	WriteBufferFlushTimeout time.Duration `json:"writeBufferFlushTimeout"`
+
+	// BatchingMaxMessages is the maximum number of messages the producer
+	// groups in a batch. Batches are only filled by asynchronous sends, i.e.
+	// with the write buffer enabled. Uses the default of the client (1000)
+	// when set to 0.
+	BatchingMaxMessages int `json:"batchingMaxMessages" validate:"gt=-1"`
+
+	// BatchingMaxPublishDelay is the maximum time messages are batched before
+	// the batch is sent. Uses the default of the client (10ms) when set to 0.
+	BatchingMaxPublishDelay time.Duration `json:"batchingMaxPublishDelay"`
+
+	// BatchingMaxSize is the maximum size of a batch in bytes. Uses the
+	// default of the client (128KB) when set to 0.
+	BatchingMaxSize int `json:"batchingMaxSize" validate:"gt=-1"`
+
+	// DisableBatching sends each message on its own. Can't be combined with
+	// the other batching options.
+	DisableBatching bool `json:"disableBatching"`
+
+	// MaxPendingMessages bounds the number of messages the producer has sent
+	// asynchronously and the broker has not confirmed yet. Uses the default
+	// of the client (1000) when set to 0.
+	MaxPendingMessages int `json:"maxPendingMessages" validate:"gt=-1"`
+
+	// BlockIfQueueFull makes sends wait for room when MaxPendingMessages is
+	// reached. If disabled, the send fails instead. Sends never block with
+	// AdaptiveThrottling.
+	BlockIfQueueFull bool `json:"blockIfQueueFull" default:"true"`
+
+	// CompressionDictionary is the path to a zstd dictionary, as created by
+	// "zstd --train". If set, payloads are compressed with zstd using the
+	// dictionary before they are produced. The Pulsar client doesn't support
+	// compression dictionaries, so the payload is compressed by the connector
+	// and consumers need the same dictionary to decompress it.
+	CompressionDictionary string `json:"compressionDictionary"`
+
+	// CompressionType is the codec the producer compresses batches of
+	// messages with. Consumers decompress them transparently. Can't be
+	// combined with CompressionDictionary.
+	CompressionType string `json:"compressionType" default:"none" validate:"inclusion=none|lz4|zlib|zstd"`
+
+	// CompressionLevel trades compression speed for ratio, it is only used
+	// by compression types that support levels.
+	CompressionLevel string `json:"compressionLevel" default:"default" validate:"inclusion=default|faster|better"`
+
+	// CircuitBreakerThreshold is the number of consecutive failed sends after
+	// which the circuit breaker opens. While it is open, writes fail right
+	// away without contacting the broker. Disabled when set to 0.
+	CircuitBreakerThreshold int `json:"circuitBreakerThreshold" validate:"gt=-1"`
+
+	// CircuitBreakerCooldown is how long the circuit breaker stays open before
+	// a single send is attempted again. The circuit closes if that send
+	// succeeds.
+	CircuitBreakerCooldown time.Duration `json:"circuitBreakerCooldown" default:"30s"`
+
+	// FallbackTopic is the topic records are produced to when sends to Topic
+	// fail FallbackThreshold consecutive times, so records are not lost
+	// during an outage of the topic. Can't be combined with a topic
+	// template, the write buffer or tracking sequence IDs.
+	FallbackTopic string `json:"fallbackTopic"`
+
+	// FallbackThreshold is the number of consecutive failed sends to Topic,
+	// including retries of the same record, after which records are produced
+	// to FallbackTopic.
+	FallbackThreshold int `json:"fallbackThreshold" default:"3"`
+
+	// FallbackCooldown is how long records are produced to FallbackTopic
+	// before sending to Topic is tried again.
+	FallbackCooldown time.Duration `json:"fallbackCooldown" default:"30s"`
+
+	// DisableReplicationMetadataKey is the metadata key of a boolean flag that
+	// disables geo-replication of the produced message when set to "true",
+	// even if the topic is replicated. Records without the key are replicated
+	// as usual, other values than booleans fail the write.
+	DisableReplicationMetadataKey string `json:"disableReplicationMetadataKey"`
+
+	// ProducerName is the name of the producer. The broker deduplicates
+	// messages by producer name and sequence ID when deduplication is enabled
+	// on the topic, the name must therefore be unique for the topic and stable
+	// across restarts. Generated by the broker if empty.
+	ProducerName string `json:"producerName"`
+
+	// SequenceStorePath is the directory the sequence ID of the last message
+	// confirmed by the broker is stored in. Messages are produced with
+	// consecutive sequence IDs continuing from the stored one, so records
+	// written again after a restart are deduplicated by the broker if
+	// EnableTopicDeduplication is set. Requires ProducerName and can't be
+	// combined with a topic template, LargeMessageTopic or the write buffer.
+	SequenceStorePath string `json:"sequenceStorePath"`
+
+	// SequenceIDField references the record field the sequence ID of the
+	// message is derived from, e.g. the log sequence number of a change data
+	// capture source. Its value must be an integer that increases with every
+	// record. Replaying the same records produces the same sequence IDs, so
+	// they are deduplicated by the broker if EnableTopicDeduplication is set.
+	// Same format as KeyField. Requires ProducerName and can't be combined
+	// with SequenceStorePath. A warning is logged when a sequence ID doesn't
+	// increase compared to the previous message of the producer.
+	SequenceIDField string `json:"sequenceIDField"`
+
+	// AutoGrowPartitions doubles the number of partitions of the topic, up to
+	// MaxPartitions, when the produce throughput exceeds
+	// AutoGrowPartitionsThreshold. Producers pick up new partitions within a
+	// minute. Requires AdminURL and a partitioned topic, and can't be
+	// combined with a topic template.
+	AutoGrowPartitions bool `json:"autoGrowPartitions"`
+
+	// MaxPartitions is the number of partitions the topic is grown to at most
+	// when AutoGrowPartitions is enabled.
+	MaxPartitions int `json:"maxPartitions" validate:"gt=-1"`
+
+	// AutoGrowPartitionsThreshold is the produce throughput in messages per
+	// second above which partitions are grown.
+	AutoGrowPartitionsThreshold int `json:"autoGrowPartitionsThreshold" validate:"gt=-1"`
+
+	// AutoGrowPartitionsWindow is the period over which the produce
+	// throughput is measured, so partitions are only grown under sustained
+	// load.
+	AutoGrowPartitionsWindow time.Duration `json:"autoGrowPartitionsWindow" default:"1m"`
+
+	// ValidateBeforeSend validates the serialized records against the Avro or
+	// JSON schema of the topic before producing them, so records that
+	// consumers can't read with the schema are rejected locally. Requires
+	// AdminURL and can't be combined with CompressionDictionary.
+	ValidateBeforeSend bool `json:"validateBeforeSend"`
+
+	// TransactionalWrites produces the records of each batch in a single
+	// transaction, which is committed once all messages are sent, or aborted
+	// if any of them fails. The transaction spans all topics the batch is
+	// produced to, including resolved topic templates and LargeMessageTopic.
+	// Requires EnableTransaction and can't be combined with the write
+	// buffer, IdempotencyKeyField or tracking sequence IDs.
+	TransactionalWrites bool `json:"transactionalWrites"`
+
+	// TransactionTimeout is the time after which the broker aborts a
+	// transaction that was not committed.
+	TransactionTimeout time.Duration `json:"transactionTimeout" default:"1m"`
+}
+
+func (c DestinationConfig) Validate() error {
+	if err := c.Config.Validate(); err != nil {
+		return err
+	}
+	if c.Topic == "" {
+		return fmt.Errorf("%q is required", DestinationConfigTopic)
+	}
+	if isTopicTemplate(c.Topic) {
+		if _, err := parseTopicTemplate(c.Topic); err != nil {
+			return fmt.Errorf("invalid %q: %w", DestinationConfigTopic, err)
+		}
+	}
+	if c.CompressionType != CompressionTypeNone && c.CompressionDictionary != "" {
+		return fmt.Errorf("%q can't be combined with %q", DestinationConfigCompressionType, DestinationConfigCompressionDictionary)
+	}
+	if c.DedupSnapshotInterval != 0 {
+		if !c.EnableTopicDeduplication {
+			return fmt.Errorf("%q is required when %q is set", DestinationConfigEnableTopicDeduplication, DestinationConfigDedupSnapshotInterval)
+		}
+		if c.DedupSnapshotInterval < time.Second {
+			return fmt.Errorf("%q must be at least 1s", DestinationConfigDedupSnapshotInterval)
+		}
+	}
+	if c.EnableTopicDeduplication && c.AdminURL == "" {
+		return fmt.Errorf("%q is required when %q is enabled", DestinationConfigAdminURL, DestinationConfigEnableTopicDeduplication)
+	}
+	if _, err := hex.DecodeString(c.NullValueMarker); err != nil {
+		return fmt.Errorf("%q must be a hex encoded byte sequence: %w", DestinationConfigNullValueMarker, err)
+	}
+	if c.KeyField != "" {
+		if err := validateField(c.KeyField); err != nil {
+			return fmt.Errorf("invalid %q: %w", DestinationConfigKeyField, err)
+		}
+	}
+	if c.KeyJSONPath != "" {
+		if c.KeyField != "" {
+			return fmt.Errorf("%q can't be combined with %q", DestinationConfigKeyJSONPath, DestinationConfigKeyField)
+		}
+		if _, err := parseJSONPath(c.KeyJSONPath); err != nil {
+			return fmt.Errorf("invalid %q: %w", DestinationConfigKeyJSONPath, err)
+		}
+	}
+	if c.PartitionByField != "" {
+		if _, err := parseJSONPath(c.PartitionByField); err != nil {
+			return fmt.Errorf("invalid %q: %w", DestinationConfigPartitionByField, err)
+		}
+		if c.ForceSinglePartition {
+			return fmt.Errorf("%q can't be combined with %q", DestinationConfigPartitionByField, DestinationConfigForceSinglePartition)
+		}
+		if c.OrderingGuarantee != OrderingGuaranteeKey {
+			return fmt.Errorf("%q can't be combined with %q %q", DestinationConfigPartitionByField, DestinationConfigOrderingGuarantee, c.OrderingGuarantee)
+		}
+	}
+	if c.OrderingKeyField != "" {
+		if err := validateField(c.OrderingKeyField); err != nil {
+			return fmt.Errorf("invalid %q: %w", DestinationConfigOrderingKeyField, err)
+		}
+	}
+	if c.IdempotencyKeyField != "" {
+		if err := validateField(c.IdempotencyKeyField); err != nil {
+			return fmt.Errorf("invalid %q: %w", DestinationConfigIdempotencyKeyField, err)
+		}
+		if c.IdempotencyWindow <= 0 {
+			return fmt.Errorf("%q must be positive", DestinationConfigIdempotencyWindow)
+		}
+	}
+	if c.LargeMessageTopic != "" {
+		if isTopicTemplate(c.LargeMessageTopic) {
+			return fmt.Errorf("%q can't be a template", DestinationConfigLargeMessageTopic)
+		}
+		if c.LargeMessageThreshold == 0 {
+			return fmt.Errorf("%q is required when %q is set", DestinationConfigLargeMessageThreshold, DestinationConfigLargeMessageTopic)
+		}
+	}
+	if err := validateProduceErrors(c.ProduceRetryableErrors); err != nil {
+		return fmt.Errorf("invalid %q: %w", DestinationConfigProduceRetryableErrors, err)
+	}
+	if c.AdaptiveThrottling && c.AdaptiveThrottlingMaxDelay <= 0 {
+		return fmt.Errorf("%q must be positive", DestinationConfigAdaptiveThrottlingMaxDelay)
+	}
+	if c.AdaptiveThrottling && !c.writeBufferEnabled() {
+		return fmt.Errorf("%q requires the write buffer", DestinationConfigAdaptiveThrottling)
+	}
+	if c.ProduceAckTimeout < 0 {
+		return fmt.Errorf("%q must not be negative", DestinationConfigProduceAckTimeout)
+	}
+	if c.SendTimeout < 0 {
+		return fmt.Errorf("%q must not be negative", DestinationConfigSendTimeout)
+	}
+	if c.TopicNotFoundPolicy == TopicNotFoundPolicyRecreate && c.AdminURL == "" {
+		return fmt.Errorf("%q is required when %q is %q", DestinationConfigAdminURL, DestinationConfigTopicNotFoundPolicy, TopicNotFoundPolicyRecreate)
+	}
+	if c.ExclusiveWaitTimeout < 0 {
+		return fmt.Errorf("%q must not be negative", DestinationConfigExclusiveWaitTimeout)
+	}
+	if len(c.EncryptionKeys) > 0 && c.EncryptionPublicKeyPath == "" {
+		return fmt.Errorf("%q is required when %q is set", DestinationConfigEncryptionPublicKeyPath, DestinationConfigEncryptionKeys)
+	}
+	if c.EncryptionKeyRotationInterval < 0 {
+		return fmt.Errorf("%q must not be negative", DestinationConfigEncryptionKeyRotationInterval)
+	}
+	if c.CircuitBreakerThreshold > 0 && c.CircuitBreakerCooldown <= 0 {
+		return fmt.Errorf("%q must be positive", DestinationConfigCircuitBreakerCooldown)
+	}
+	if c.WriteBufferFlushTimeout < 0 {
+		return fmt.Errorf("%q must not be negative", DestinationConfigWriteBufferFlushTimeout)
+	}
+	if c.BatchingMaxPublishDelay < 0 {
+		return fmt.Errorf("%q must not be negative", DestinationConfigBatchingMaxPublishDelay)
+	}
+	if c.DisableBatching {
+		switch {
+		case c.BatchingMaxMessages > 0:
+			return fmt.Errorf("%q can't be combined with %q", DestinationConfigDisableBatching, DestinationConfigBatchingMaxMessages)
+		case c.BatchingMaxPublishDelay > 0:
+			return fmt.Errorf("%q can't be combined with %q", DestinationConfigDisableBatching, DestinationConfigBatchingMaxPublishDelay)
+		case c.BatchingMaxSize > 0:
+			return fmt.Errorf("%q can't be combined with %q", DestinationConfigDisableBatching, DestinationConfigBatchingMaxSize)
+		}
+	}
+	if c.AutoGrowPartitions {
+		switch {
+		case c.AdminURL == "":
+			return fmt.Errorf("%q is required when %q is enabled", DestinationConfigAdminURL, DestinationConfigAutoGrowPartitions)
+		case c.MaxPartitions == 0:
+			return fmt.Errorf("%q is required when %q is enabled", DestinationConfigMaxPartitions, DestinationConfigAutoGrowPartitions)
+		case c.AutoGrowPartitionsThreshold == 0:
+			return fmt.Errorf("%q is required when %q is enabled", DestinationConfigAutoGrowPartitionsThreshold, DestinationConfigAutoGrowPartitions)
+		case c.AutoGrowPartitionsWindow <= 0:
+			return fmt.Errorf("%q must be positive", DestinationConfigAutoGrowPartitionsWindow)
+		case isTopicTemplate(c.Topic):
+			return fmt.Errorf("%q can't be a template when %q is enabled", DestinationConfigTopic, DestinationConfigAutoGrowPartitions)
+		}
+	}
+	if c.FallbackTopic != "" {
+		switch {
+		case isTopicTemplate(c.FallbackTopic):
+			return fmt.Errorf("%q can't be a template", DestinationConfigFallbackTopic)
+		case isTopicTemplate(c.Topic):
+			return fmt.Errorf("%q can't be a template when %q is set", DestinationConfigTopic, DestinationConfigFallbackTopic)
+		case c.FallbackThreshold <= 0:
+			return fmt.Errorf("%q must be positive", DestinationConfigFallbackThreshold)
+		case c.FallbackCooldown <= 0:
+			return fmt.Errorf("%q must be positive", DestinationConfigFallbackCooldown)
+		case c.writeBufferEnabled():
+			return fmt.Errorf("the write buffer can't be combined with %q", DestinationConfigFallbackTopic)
+		}
+	}
+	if c.ValidateBeforeSend {
+		switch {
+		case c.AdminURL == "":
+			return fmt.Errorf("%q is required when %q is enabled", DestinationConfigAdminURL, DestinationConfigValidateBeforeSend)
+		case c.CompressionDictionary != "":
+			return fmt.Errorf("%q can't be combined with %q", DestinationConfigCompressionDictionary, DestinationConfigValidateBeforeSend)
+		}
+	}
+	if c.TransactionalWrites {
+		switch {
+		case !c.EnableTransaction:
+			return fmt.Errorf("%q is required when %q is enabled", DestinationConfigEnableTransaction, DestinationConfigTransactionalWrites)
+		case c.TransactionTimeout <= 0:
+			return fmt.Errorf("%q must be positive", DestinationConfigTransactionTimeout)
+		case c.writeBufferEnabled():
+			return fmt.Errorf("the write buffer can't be combined with %q", DestinationConfigTransactionalWrites)
+		case c.IdempotencyKeyField != "":
+			return fmt.Errorf("%q can't be combined with %q", DestinationConfigIdempotencyKeyField, DestinationConfigTransactionalWrites)
+		}
+	}
+	if c.SequenceStorePath != "" {
+		if err := c.validateSequenceTracking(); err != nil {
+			return err
+		}
+	}
+	if c.SequenceIDField != "" {
+		if err := validateField(c.SequenceIDField); err != nil {
+			return fmt.Errorf("invalid %q: %w", DestinationConfigSequenceIDField, err)
+		}
+		if c.ProducerName == "" {
+			return fmt.Errorf("%q is required when %q is set", DestinationConfigProducerName, DestinationConfigSequenceIDField)
+		}
+	}
+	if c.PriorityMetadataKey != "" {
+		switch {
+		case c.OrderingGuarantee == OrderingGuaranteeKey:
+			return fmt.Errorf("%q can't be combined with %q %q", DestinationConfigPriorityMetadataKey, DestinationConfigOrderingGuarantee, c.OrderingGuarantee)
+		case c.SequenceIDField != "":
+			return fmt.Errorf("%q can't be combined with %q", DestinationConfigPriorityMetadataKey, DestinationConfigSequenceIDField)
+		case c.SequenceStorePath != "":
+			return fmt.Errorf("%q can't be combined with %q", DestinationConfigPriorityMetadataKey, DestinationConfigSequenceStorePath)
+		}
+	}
+	if c.writeBufferEnabled() {
+		switch {
+		case c.ProduceMaxRetries > 0:
+			return fmt.Errorf("the write buffer can't be combined with %q", DestinationConfigProduceMaxRetries)
+		case c.BacklogQuotaMaxRetries > 0:
+			return fmt.Errorf("the write buffer can't be combined with %q", DestinationConfigBacklogQuotaMaxRetries)
+		case c.CircuitBreakerThreshold > 0:
+			return fmt.Errorf("the write buffer can't be combined with %q", DestinationConfigCircuitBreakerThreshold)
+		}
+	}
+	return nil
+}
+
+// validateSequenceTracking checks that messages can be produced with
+// consecutive sequence IDs by a single named producer.
+func (c DestinationConfig) validateSequenceTracking() error {
+	switch {
+	case c.ProducerName == "":
+		return fmt.Errorf("%q is required when tracking sequence IDs", DestinationConfigProducerName)
+	case isTopicTemplate(c.Topic):
+		return fmt.Errorf("%q can't be a template when tracking sequence IDs", DestinationConfigTopic)
+	case c.LargeMessageTopic != "":
+		return fmt.Errorf("%q can't be combined with tracking sequence IDs", DestinationConfigLargeMessageTopic)
+	case c.writeBufferEnabled():
+		return errors.New("the write buffer can't be combined with tracking sequence IDs")
+	case c.TransactionalWrites:
+		return fmt.Errorf("%q can't be combined with tracking sequence IDs", DestinationConfigTransactionalWrites)
+	case c.FallbackTopic != "":
+		return fmt.Errorf("%q can't be combined with tracking sequence IDs", DestinationConfigFallbackTopic)
+	case c.SequenceIDField != "":
+		return fmt.Errorf("%q can't be combined with tracking sequence IDs", DestinationConfigSequenceIDField)
+	}
+	return nil
+}
+
+// writeBufferEnabled returns true if any flush threshold of the write buffer
+// is configured.
+func (c DestinationConfig) writeBufferEnabled() bool {
+	return c.WriteBufferMaxRecords > 0 || c.WriteBufferMaxBytes > 0 || c.WriteBufferFlushTimeout > 0
 }
@@ -0,0 +1,138 @@
+// Copyright © 2024 Meroxa, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pulsar
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/apache/pulsar-client-go/pulsar"
+	sdk "github.com/conduitio/conduit-connector-sdk"
+)
+
+// minDeadlineCheckInterval bounds how often expired processing deadlines are
+// checked.
+const minDeadlineCheckInterval = 10 * time.Millisecond
+
+// processingDeadlines keeps track of when read messages have to be acked.
+// Read and Ack can be called concurrently.
+type processingDeadlines struct {
+	deadline time.Duration
+
+	mu        sync.Mutex
+	expiresAt map[string]pendingMessage
+
+	now func() time.Time
+}
+
+type pendingMessage struct {
+	id        pulsar.MessageID
+	expiresAt time.Time
+}
+
+func newProcessingDeadlines(deadline time.Duration) *processingDeadlines {
+	return &processingDeadlines{
+		deadline:  deadline,
+		expiresAt: make(map[string]pendingMessage),
+		now:       time.Now,
+	}
+}
+
+func (d *processingDeadlines) add(id pulsar.MessageID) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.expiresAt[string(id.Serialize())] = pendingMessage{id: id, expiresAt: d.now().Add(d.deadline)}
+}
+
+// remove stops tracking the message with the serialized ID.
+func (d *processingDeadlines) remove(serializedID []byte) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	delete(d.expiresAt, string(serializedID))
+}
+
+// expired returns the messages whose deadline passed and stops tracking them.
+func (d *processingDeadlines) expired() []pulsar.MessageID {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	now := d.now()
+	var ids []pulsar.MessageID
+	for key, msg := range d.expiresAt {
+		if !now.Before(msg.expiresAt) {
+			ids = append(ids, msg.id)
+			delete(d.expiresAt, key)
+		}
+	}
+	return ids
+}
+
+// processingDeadline returns the deadline within which read records have to
+// be acked and how often it is checked, from either ProcessingDeadline or
+// AckTimeout. The deadline is 0 if neither is set.
+func (c SourceConfig) processingDeadline() (deadline, checkInterval time.Duration) {
+	if c.AckTimeout > 0 {
+		deadline, checkInterval = c.AckTimeout, c.AckTimeoutTickDuration
+	} else {
+		deadline = c.ProcessingDeadline
+	}
+	if checkInterval == 0 {
+		checkInterval = deadline / 4
+	}
+	return deadline, checkInterval
+}
+
+// enforceDeadlines nacks messages that were not acked within the processing
+// deadline, checking every interval until the returned function is called.
+func (s *Source) enforceDeadlines(ctx context.Context, interval time.Duration) (stop func()) {
+	interval = max(interval, minDeadlineCheckInterval)
+	ticker := time.NewTicker(interval)
+	done := make(chan struct{})
+	stopped := make(chan struct{})
+
+	go func() {
+		defer close(stopped)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-done:
+				return
+			case <-ticker.C:
+				s.nackExpired(ctx)
+			}
+		}
+	}()
+
+	return func() {
+		close(done)
+		<-stopped
+	}
+}
+
+// nackExpired nacks messages whose processing deadline passed, so they are
+// redelivered.
+func (s *Source) nackExpired(ctx context.Context) {
+	ids := s.deadlines.expired()
+	for _, id := range ids {
+		s.nackID(id)
+	}
+	if len(ids) > 0 {
+		sdk.Logger(ctx).Warn().
+			Int("count", len(ids)).
+			Dur("deadline", s.deadlines.deadline).
+			Msg("nacked messages that were not acked within the processing deadline")
+	}
+}
@@ -0,0 +1,137 @@
+// Copyright © 2024 Meroxa, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pulsar
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/apache/pulsar-client-go/pulsar"
+	"github.com/matryer/is"
+)
+
+func TestSource_ProcessingDeadline(t *testing.T) {
+	is := is.New(t)
+	ctx := context.Background()
+
+	fast := pulsar.NewMessageID(1, 1, 0, 0)
+	slow := pulsar.NewMessageID(1, 2, 0, 0)
+	consumer := &rejectRecordingConsumer{queueConsumer: queueConsumer{messages: []pulsar.Message{
+		readableMessage{fakeMessage{id: fast}},
+		readableMessage{fakeMessage{id: slow}},
+	}}}
+	underTest := &Source{
+		consumer:  consumer,
+		deadlines: newProcessingDeadlines(time.Second),
+	}
+	clock := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	underTest.deadlines.now = func() time.Time { return clock }
+
+	fastRec, err := underTest.Read(ctx)
+	is.NoErr(err)
+	_, err = underTest.Read(ctx)
+	is.NoErr(err)
+
+	clock = clock.Add(500 * time.Millisecond)
+	is.NoErr(underTest.Ack(ctx, fastRec.Position))
+	underTest.nackExpired(ctx)
+	is.Equal(len(consumer.nacked), 0) // deadline didn't pass yet
+
+	// the slow record is nacked once its deadline passed
+	clock = clock.Add(500 * time.Millisecond)
+	underTest.nackExpired(ctx)
+	is.Equal(consumer.nacked, []pulsar.MessageID{slow})
+
+	// it is only nacked once
+	clock = clock.Add(time.Second)
+	underTest.nackExpired(ctx)
+	is.Equal(len(consumer.nacked), 1)
+}
+
+func TestSourceConfig_ProcessingDeadline(t *testing.T) {
+	testCases := []struct {
+		name              string
+		cfg               map[string]string
+		wantDeadline      time.Duration
+		wantCheckInterval time.Duration
+	}{
+		{name: "disabled"},
+		{
+			name:              "processing deadline",
+			cfg:               map[string]string{SourceConfigProcessingDeadline: "1m"},
+			wantDeadline:      time.Minute,
+			wantCheckInterval: 15 * time.Second,
+		},
+		{
+			name:              "ack timeout",
+			cfg:               map[string]string{SourceConfigAckTimeout: "30s"},
+			wantDeadline:      30 * time.Second,
+			wantCheckInterval: 7500 * time.Millisecond,
+		},
+		{
+			name: "ack timeout with tick duration",
+			cfg: map[string]string{
+				SourceConfigAckTimeout:             "30s",
+				SourceConfigAckTimeoutTickDuration: "1s",
+			},
+			wantDeadline:      30 * time.Second,
+			wantCheckInterval: time.Second,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			is := is.New(t)
+
+			cfgMap := newSourceCfg("topic")
+			for k, v := range tc.cfg {
+				cfgMap[k] = v
+			}
+			underTest := &Source{}
+			is.NoErr(underTest.Configure(context.Background(), cfgMap))
+
+			deadline, checkInterval := underTest.config.processingDeadline()
+			is.Equal(deadline, tc.wantDeadline)
+			is.Equal(checkInterval, tc.wantCheckInterval)
+		})
+	}
+}
+
+func TestSource_Configure_AckTimeout(t *testing.T) {
+	testCases := []struct {
+		name string
+		cfg  map[string]string
+	}{
+		{name: "negative timeout", cfg: map[string]string{SourceConfigAckTimeout: "-1s"}},
+		{name: "negative tick duration", cfg: map[string]string{SourceConfigAckTimeoutTickDuration: "-1s"}},
+		{name: "processing deadline", cfg: map[string]string{
+			SourceConfigAckTimeout:         "30s",
+			SourceConfigProcessingDeadline: "1m",
+		}},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			is := is.New(t)
+
+			cfgMap := newSourceCfg("topic")
+			for k, v := range tc.cfg {
+				cfgMap[k] = v
+			}
+			is.True((&Source{}).Configure(context.Background(), cfgMap) != nil)
+		})
+	}
+}
@@ -0,0 +1,80 @@
+// Copyright © 2024 Meroxa, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pulsar
+
+import (
+	"bytes"
+	"compress/gzip"
+	"io"
+
+	"github.com/klauspost/compress/zstd"
+)
+
+const (
+	contentEncodingGzip = "gzip"
+	contentEncodingZstd = "zstd"
+)
+
+var (
+	gzipMagic = []byte{0x1f, 0x8b}
+	zstdMagic = []byte{0x28, 0xb5, 0x2f, 0xfd}
+)
+
+// zstdDecoder decodes zstd payloads. It is shared by all messages, since
+// DecodeAll can be called concurrently and creating a decoder allocates its
+// buffers. Creating a decoder without options never fails.
+var zstdDecoder, _ = zstd.NewReader(nil)
+
+// decompressPayload detects payloads that were compressed with gzip or zstd
+// by the producing application and returns the decompressed payload together
+// with the detected encoding. Payloads that are not compressed, or that can't
+// be decompressed, are returned unchanged with an empty encoding.
+func decompressPayload(payload []byte) ([]byte, string) {
+	var (
+		decompressed []byte
+		encoding     string
+		err          error
+	)
+
+	switch {
+	case bytes.HasPrefix(payload, gzipMagic):
+		encoding = contentEncodingGzip
+		decompressed, err = gunzip(payload)
+	case bytes.HasPrefix(payload, zstdMagic):
+		encoding = contentEncodingZstd
+		decompressed, err = unzstd(payload)
+	default:
+		return payload, ""
+	}
+
+	if err != nil {
+		// the magic bytes matched by chance, treat the payload as raw data
+		return payload, ""
+	}
+	return decompressed, encoding
+}
+
+func gunzip(payload []byte) ([]byte, error) {
+	r, err := gzip.NewReader(bytes.NewReader(payload))
+	if err != nil {
+		return nil, err
+	}
+	defer r.Close()
+	return io.ReadAll(r)
+}
+
+func unzstd(payload []byte) ([]byte, error) {
+	return zstdDecoder.DecodeAll(payload, nil)
+}
@@ -0,0 +1,134 @@
+// Copyright © 2024 Meroxa, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pulsar
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"sync"
+	"testing"
+
+	"github.com/apache/pulsar-client-go/pulsar"
+	"github.com/klauspost/compress/zstd"
+	"github.com/matryer/is"
+)
+
+func TestDecompressPayload(t *testing.T) {
+	want := []byte(`{"id":1,"name":"foo"}`)
+
+	testCases := []struct {
+		name         string
+		payload      []byte
+		wantPayload  []byte
+		wantEncoding string
+	}{{
+		name:         "gzip",
+		payload:      gzipBytes(t, want),
+		wantPayload:  want,
+		wantEncoding: contentEncodingGzip,
+	}, {
+		name:         "zstd",
+		payload:      zstdBytes(t, want),
+		wantPayload:  want,
+		wantEncoding: contentEncodingZstd,
+	}, {
+		name:         "uncompressed",
+		payload:      want,
+		wantPayload:  want,
+		wantEncoding: "",
+	}, {
+		name:         "corrupt gzip",
+		payload:      []byte{0x1f, 0x8b, 0x00, 0x01},
+		wantPayload:  []byte{0x1f, 0x8b, 0x00, 0x01},
+		wantEncoding: "",
+	}, {
+		name:         "corrupt zstd",
+		payload:      []byte{0x28, 0xb5, 0x2f, 0xfd, 0x00},
+		wantPayload:  []byte{0x28, 0xb5, 0x2f, 0xfd, 0x00},
+		wantEncoding: "",
+	}}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			is := is.New(t)
+
+			payload, encoding := decompressPayload(tc.payload)
+			is.Equal(payload, tc.wantPayload)
+			is.Equal(encoding, tc.wantEncoding)
+		})
+	}
+}
+
+func TestDecompressPayload_ZstdConcurrent(t *testing.T) {
+	is := is.New(t)
+
+	// the zstd decoder is shared by all messages
+	payloads := make([][]byte, 8)
+	for i := range payloads {
+		payloads[i] = bytes.Repeat([]byte{byte('a' + i)}, 1024)
+	}
+
+	var wg sync.WaitGroup
+	results := make([][]byte, len(payloads))
+	for i, payload := range payloads {
+		compressed := zstdBytes(t, payload)
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			results[i], _ = decompressPayload(compressed)
+		}()
+	}
+	wg.Wait()
+
+	is.Equal(results, payloads)
+}
+
+func TestSource_Read_AutoDecompressPayload(t *testing.T) {
+	is := is.New(t)
+
+	msg := payloadMessage{readableMessage{fakeMessage{id: pulsar.NewMessageID(1, 1, 0, 0)}}, gzipBytes(t, []byte(`{"id": 1}`))}
+	underTest := &Source{
+		consumer: &queueConsumer{messages: []pulsar.Message{msg}},
+		config:   SourceConfig{AutoDecompressPayload: true},
+	}
+
+	rec, err := underTest.Read(context.Background())
+	is.NoErr(err)
+	is.Equal(rec.Metadata[metadataContentEncoding], contentEncodingGzip)
+	is.Equal(rec.Payload.After.Bytes(), []byte(`{"id": 1}`))
+}
+
+func gzipBytes(t *testing.T, data []byte) []byte {
+	is := is.New(t)
+
+	var buf bytes.Buffer
+	w := gzip.NewWriter(&buf)
+	_, err := w.Write(data)
+	is.NoErr(err)
+	is.NoErr(w.Close())
+
+	return buf.Bytes()
+}
+
+func zstdBytes(t *testing.T, data []byte) []byte {
+	is := is.New(t)
+
+	w, err := zstd.NewWriter(nil)
+	is.NoErr(err)
+	defer w.Close()
+
+	return w.EncodeAll(data, nil)
+}
@@ -0,0 +1,62 @@
+// Copyright © 2024 Meroxa, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pulsar
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/apache/pulsar-client-go/pulsar"
+	"github.com/conduitio/conduit-commons/opencdc"
+)
+
+// Metadata keys that schedule the delivery of a produced message. Delayed
+// messages are only held back from shared and key_shared subscriptions.
+const (
+	// metadataDeliverAfter is a duration, e.g. "5m", after which the message
+	// is delivered.
+	metadataDeliverAfter = "pulsar.deliverAfter"
+	// metadataDeliverAt is the time the message is delivered at, as RFC 3339
+	// timestamp or unix milliseconds.
+	metadataDeliverAt = "pulsar.deliverAt"
+)
+
+// setDeliveryTime schedules the delivery of the message according to the
+// metadata of the record. Messages without delivery metadata are delivered
+// immediately.
+func setDeliveryTime(msg *pulsar.ProducerMessage, metadata opencdc.Metadata) error {
+	after, hasAfter := metadata[metadataDeliverAfter]
+	at, hasAt := metadata[metadataDeliverAt]
+	switch {
+	case hasAfter && hasAt:
+		return fmt.Errorf("metadata %q can't be combined with %q", metadataDeliverAfter, metadataDeliverAt)
+	case hasAfter:
+		delay, err := time.ParseDuration(after)
+		if err != nil {
+			return fmt.Errorf("invalid value %q of metadata %q: %w", after, metadataDeliverAfter, err)
+		}
+		if delay < 0 {
+			return fmt.Errorf("metadata %q must not be negative", metadataDeliverAfter)
+		}
+		msg.DeliverAfter = delay
+	case hasAt:
+		deliverAt, err := parseStartTimestamp(at)
+		if err != nil {
+			return fmt.Errorf("invalid value %q of metadata %q: %w", at, metadataDeliverAt, err)
+		}
+		msg.DeliverAt = deliverAt
+	}
+	return nil
+}
@@ -0,0 +1,118 @@
+// Copyright © 2024 Meroxa, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pulsar
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/apache/pulsar-client-go/pulsar"
+	"github.com/conduitio-labs/conduit-connector-pulsar/test"
+	"github.com/conduitio/conduit-commons/opencdc"
+	sdk "github.com/conduitio/conduit-connector-sdk"
+	"github.com/google/uuid"
+	"github.com/matryer/is"
+)
+
+func TestSetDeliveryTime(t *testing.T) {
+	deliverAt := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+
+	testCases := []struct {
+		name      string
+		metadata  opencdc.Metadata
+		wantAfter time.Duration
+		wantAt    time.Time
+		wantErr   bool
+	}{
+		{name: "immediate", metadata: opencdc.Metadata{}},
+		{name: "deliver after", metadata: opencdc.Metadata{metadataDeliverAfter: "5m"}, wantAfter: 5 * time.Minute},
+		{name: "deliver at", metadata: opencdc.Metadata{metadataDeliverAt: "2024-05-01T12:00:00Z"}, wantAt: deliverAt},
+		{name: "deliver at unix millis", metadata: opencdc.Metadata{metadataDeliverAt: "1714564800000"}, wantAt: deliverAt},
+		{name: "invalid duration", metadata: opencdc.Metadata{metadataDeliverAfter: "soon"}, wantErr: true},
+		{name: "negative duration", metadata: opencdc.Metadata{metadataDeliverAfter: "-1s"}, wantErr: true},
+		{name: "invalid timestamp", metadata: opencdc.Metadata{metadataDeliverAt: "tomorrow"}, wantErr: true},
+		{name: "both", metadata: opencdc.Metadata{
+			metadataDeliverAfter: "5m",
+			metadataDeliverAt:    "2024-05-01T12:00:00Z",
+		}, wantErr: true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			is := is.New(t)
+
+			var msg pulsar.ProducerMessage
+			err := setDeliveryTime(&msg, tc.metadata)
+			if tc.wantErr {
+				is.True(err != nil)
+				return
+			}
+			is.NoErr(err)
+			is.Equal(msg.DeliverAfter, tc.wantAfter)
+			is.True(msg.DeliverAt.Equal(tc.wantAt))
+		})
+	}
+}
+
+func TestDestination_Integration_DeliverAfter(t *testing.T) {
+	is := is.New(t)
+	ctx := context.Background()
+
+	topic := test.SetupTopicName(t, is)
+
+	client, err := pulsar.NewClient(pulsar.ClientOptions{URL: test.PulsarURL})
+	is.NoErr(err)
+	defer client.Close()
+
+	// delayed messages are only held back from shared subscriptions
+	consumer, err := client.Subscribe(pulsar.ConsumerOptions{
+		Topic:            topic,
+		SubscriptionName: topic + "-verify",
+		Type:             pulsar.Shared,
+	})
+	is.NoErr(err)
+	defer consumer.Close()
+
+	con := NewDestination()
+	err = con.Configure(ctx, map[string]string{
+		DestinationConfigUrl:   test.PulsarURL,
+		DestinationConfigTopic: topic,
+	})
+	is.NoErr(err)
+	err = con.Open(ctx)
+	is.NoErr(err)
+	defer func() {
+		err := con.Teardown(ctx)
+		is.NoErr(err)
+	}()
+
+	delay := 3 * time.Second
+	sentAt := time.Now()
+	_, err = con.Write(ctx, []opencdc.Record{sdk.Util.Source.NewRecordCreate(
+		[]byte(uuid.NewString()),
+		opencdc.Metadata{metadataDeliverAfter: delay.String()},
+		opencdc.RawData("delayed"),
+		opencdc.RawData(exampleMessage),
+	)})
+	is.NoErr(err)
+
+	receiveCtx, cancel := context.WithTimeout(ctx, delay+10*time.Second)
+	defer cancel()
+	msg, err := consumer.Receive(receiveCtx)
+	is.NoErr(err)
+	is.Equal(msg.Key(), "delayed")
+	is.True(time.Since(sentAt) >= delay)
+}
@@ -16,13 +16,22 @@ package pulsar
 
 import (
 	"context"
+	"encoding/hex"
+	"errors"
 	"fmt"
+	"strconv"
+	"text/template"
+	"time"
 
 	"github.com/apache/pulsar-client-go/pulsar"
+	"github.com/apache/pulsar-client-go/pulsar/crypto"
 	"github.com/apache/pulsar-client-go/pulsar/log"
+	"github.com/apache/pulsar-client-go/pulsaradmin"
+	"github.com/apache/pulsar-client-go/pulsaradmin/pkg/rest"
 	"github.com/conduitio/conduit-commons/config"
 	"github.com/conduitio/conduit-commons/opencdc"
 	sdk "github.com/conduitio/conduit-connector-sdk"
+	"github.com/rs/zerolog"
 )
 
 type Destination struct {
@@ -31,12 +40,80 @@ type Destination struct {
 	client   pulsar.Client
 	producer pulsar.Producer
 	config   DestinationConfig
+
+	// topicTemplate is set if the topic is a template, in which case a
+	// producer is created for each resolved topic.
+	topicTemplate *template.Template
+	producers     map[string]pulsar.Producer
+	// keyPath is set when the message key is extracted from the payload.
+	keyPath *jsonPath
+	// partitionPath is set when messages are routed by a payload field.
+	partitionPath *jsonPath
+	// largeProducer produces messages exceeding the large message threshold
+	// to the large message topic.
+	largeProducer pulsar.Producer
+	// fallbackProducer produces messages to the fallback topic while fallback
+	// is open.
+	fallbackProducer pulsar.Producer
+	// fallback is set when a fallback topic is configured, it opens after
+	// consecutive failed sends to the topic.
+	fallback *circuitBreaker
+
+	nullValueMarker []byte
+	resultSampler   zerolog.Sampler
+	// auditInstanceID identifies this connector in the audit properties.
+	auditInstanceID string
+	// idempotency is set when records are deduplicated by idempotency key.
+	idempotency *idempotencyWindow
+	// messageCrypto is set when produced messages are encrypted.
+	messageCrypto *rotatingMessageCrypto
+	// stopKeyRotation stops the periodic rotation of the encryption keys.
+	stopKeyRotation func()
+	// buffer is set when messages are sent asynchronously and flushed at
+	// configured thresholds.
+	buffer *writeBuffer
+	// compressor is set when payloads are compressed with a dictionary.
+	compressor *dictCompressor
+	// breaker is set when sends stop after consecutive failures.
+	breaker *circuitBreaker
+	// sequenceStore is set when messages are produced with consecutive
+	// sequence IDs that survive restarts.
+	sequenceStore SequenceStore
+	// lastSequenceID is the sequence ID of the last confirmed message.
+	lastSequenceID int64
+	// sentSequenceIDs is the highest sequence ID sent by each producer when
+	// sequence IDs are derived from SequenceIDField.
+	sentSequenceIDs map[pulsar.Producer]int64
+	// growth is set when the partitions of the topic are grown with the
+	// produce throughput.
+	growth *partitionGrowth
+	// admin is set when the destination performs admin operations while
+	// producing.
+	admin pulsaradmin.Client
+	// adminREST is set when admin endpoints that are not covered by admin
+	// are called while producing.
+	adminREST *rest.Client
+	// partitionCounts is set when deleted topics are recreated. It contains
+	// the number of partitions of each topic a producer was created for.
+	partitionCounts map[string]int
+	// schemas is set when records are validated before they are sent. It
+	// contains a validator for each topic with an Avro or JSON schema.
+	schemas map[string]*recordValidator
 }
 
+var errBacklogQuotaExceeded = errors.New("backlog quota of the topic exceeded")
+
 func NewDestination() sdk.Destination {
 	return sdk.DestinationWithMiddleware(&Destination{}, sdk.DefaultDestinationMiddleware()...)
 }
 
+// NewDestinationWithSequenceStore creates a destination that keeps the
+// sequence ID of the last confirmed message in the given store instead of the
+// file configured with SequenceStorePath.
+func NewDestinationWithSequenceStore(store SequenceStore) sdk.Destination {
+	return sdk.DestinationWithMiddleware(&Destination{sequenceStore: store}, sdk.DefaultDestinationMiddleware()...)
+}
+
 func (d *Destination) Parameters() config.Parameters {
 	return d.config.Parameters()
 }
@@ -46,6 +123,60 @@ func (d *Destination) Configure(ctx context.Context, cfg config.Config) error {
 		return fmt.Errorf("failed to parse config: %w", err)
 	}
 
+	if err := d.config.Validate(); err != nil {
+		return fmt.Errorf("invalid config: %w", err)
+	}
+	if d.sequenceStore != nil {
+		if err := d.config.validateSequenceTracking(); err != nil {
+			return fmt.Errorf("invalid config: %w", err)
+		}
+	}
+
+	// the marker, topic template and JSONPaths were already validated,
+	// parsing can't fail
+	d.nullValueMarker, _ = hex.DecodeString(d.config.NullValueMarker)
+	if isTopicTemplate(d.config.Topic) {
+		d.topicTemplate, _ = parseTopicTemplate(d.config.Topic)
+	}
+	if d.config.KeyJSONPath != "" {
+		keyPath, _ := parseJSONPath(d.config.KeyJSONPath)
+		d.keyPath = &keyPath
+	}
+	if d.config.PartitionByField != "" {
+		partitionPath, _ := parseJSONPath(d.config.PartitionByField)
+		d.partitionPath = &partitionPath
+	}
+
+	if d.config.IdempotencyKeyField != "" {
+		d.idempotency = newIdempotencyWindow(d.config.IdempotencyWindow)
+	}
+	if d.config.writeBufferEnabled() {
+		d.buffer = newWriteBuffer(d.config)
+	}
+	if d.config.SequenceStorePath != "" && d.sequenceStore == nil {
+		d.sequenceStore = newFileSequenceStore(d.config.SequenceStorePath)
+	}
+	if d.config.AutoGrowPartitions {
+		d.growth = newPartitionGrowth(d.config.AutoGrowPartitionsThreshold, d.config.AutoGrowPartitionsWindow)
+	}
+	if d.config.FallbackTopic != "" {
+		d.fallback = newCircuitBreaker(d.config.FallbackThreshold, d.config.FallbackCooldown)
+	}
+	if d.config.CircuitBreakerThreshold > 0 {
+		d.breaker = newCircuitBreaker(d.config.CircuitBreakerThreshold, d.config.CircuitBreakerCooldown)
+	}
+	if d.config.CompressionDictionary != "" {
+		var err error
+		d.compressor, err = newDictCompressor(d.config.CompressionDictionary)
+		if err != nil {
+			return fmt.Errorf("invalid config: %w", err)
+		}
+	}
+
+	if d.config.LogProduceResultsSampleRate > 1 {
+		d.resultSampler = &zerolog.BasicSampler{N: uint32(d.config.LogProduceResultsSampleRate)}
+	}
+
 	return nil
 }
 
@@ -55,10 +186,17 @@ func (d *Destination) Open(ctx context.Context) (err error) {
 		logger = log.DefaultNopLogger()
 	}
 
+	authentication, err := newAuthentication(d.config.Config)
+	if err != nil {
+		return err
+	}
+
 	d.client, err = pulsar.NewClient(pulsar.ClientOptions{
 		URL:                        d.config.URL,
+		ListenerName:               d.config.ListenerName,
 		ConnectionTimeout:          d.config.ConnectionTimeout,
 		OperationTimeout:           d.config.OperationTimeout,
+		KeepAliveInterval:          d.config.KeepAliveInterval,
 		MaxConnectionsPerBroker:    d.config.MaxConnectionsPerBroker,
 		MemoryLimitBytes:           d.config.MemoryLimitBytes,
 		EnableTransaction:          d.config.EnableTransaction,
@@ -67,6 +205,7 @@ func (d *Destination) Open(ctx context.Context) (err error) {
 		TLSTrustCertsFilePath:      d.config.TLSTrustCertsFilePath,
 		TLSAllowInsecureConnection: d.config.TLSAllowInsecureConnection,
 		TLSValidateHostname:        d.config.TLSValidateHostname,
+		Authentication:             authentication,
 
 		Logger: logger,
 	})
@@ -75,47 +214,630 @@ func (d *Destination) Open(ctx context.Context) (err error) {
 	}
 	sdk.Logger(ctx).Info().Msg("created destination client")
 
-	d.producer, err = d.client.CreateProducer(pulsar.ProducerOptions{
-		Topic: d.config.Topic,
+	if d.config.AuditMetadata {
+		d.auditInstanceID = auditInstanceID(ctx)
+	}
 
-		// SendTimeout set to -1 disables the timeout to prevent acceptance
-		// tests to detect leaking goroutines.
-		// TODO: it might be better for this to be configurable (issue #9)
-		SendTimeout: -1,
+	if len(d.config.EncryptionKeys) > 0 {
+		keyReader := crypto.NewFileKeyReader(d.config.EncryptionPublicKeyPath, "")
+		d.messageCrypto, err = newRotatingMessageCrypto(d.config.EncryptionKeys, keyReader)
+		if err != nil {
+			return err
+		}
+		if d.config.EncryptionKeyRotationInterval > 0 {
+			d.stopKeyRotation = d.messageCrypto.rotateEvery(ctx, d.config.EncryptionKeyRotationInterval)
+		}
+	}
+
+	if d.growth != nil || d.config.ValidateBeforeSend || d.config.EnableTopicDeduplication ||
+		d.config.TopicNotFoundPolicy == TopicNotFoundPolicyRecreate {
+		d.admin, err = newAdminClient(d.config.Config)
+		if err != nil {
+			return err
+		}
+	}
+	if d.config.EnableTopicDeduplication && d.config.DedupSnapshotInterval > 0 {
+		d.adminREST, err = newAdminRESTClient(d.config.Config)
+		if err != nil {
+			return err
+		}
+	}
+	if d.config.ValidateBeforeSend {
+		d.schemas = make(map[string]*recordValidator)
+	}
+	if d.config.TopicNotFoundPolicy == TopicNotFoundPolicyRecreate {
+		d.partitionCounts = make(map[string]int)
+	}
+
+	if d.config.LargeMessageTopic != "" {
+		d.largeProducer, err = d.createProducer(ctx, d.config.LargeMessageTopic)
+		if err != nil {
+			return err
+		}
+	}
+
+	if d.config.FallbackTopic != "" {
+		d.fallbackProducer, err = d.createProducer(ctx, d.config.FallbackTopic)
+		if err != nil {
+			return err
+		}
+	}
+
+	if d.topicTemplate != nil {
+		// producers are created when the first record for a topic is written
+		d.producers = make(map[string]pulsar.Producer)
+		return nil
+	}
+
+	d.producer, err = d.createProducer(ctx, d.config.Topic)
+	if err != nil {
+		return err
+	}
+
+	if d.sequenceStore != nil {
+		return d.loadSequenceID(ctx)
+	}
+	return nil
+}
+
+// loadSequenceID restores the sequence ID of the last confirmed message. The
+// stored sequence ID takes precedence over the one reported by the broker, so
+// messages confirmed before a crash but not stored are resent with the same
+// sequence ID and deduplicated.
+func (d *Destination) loadSequenceID(ctx context.Context) error {
+	sequenceID, err := d.sequenceStore.Load(ctx, d.config.ProducerName)
+	if err != nil {
+		return fmt.Errorf("failed to load sequence ID: %w", err)
+	}
+	if sequenceID < 0 {
+		sequenceID = d.producer.LastSequenceID()
+	}
+
+	d.lastSequenceID = sequenceID
+	sdk.Logger(ctx).Info().
+		Str("producerName", d.config.ProducerName).
+		Int64("sequenceID", sequenceID).
+		Msg("restored last sequence ID")
+	return nil
+}
+
+// createProducer creates a producer for the topic and prepares the topic
+// according to the configuration.
+func (d *Destination) createProducer(ctx context.Context, topic string) (pulsar.Producer, error) {
+	if d.config.LookupTimeout > 0 {
+		if err := lookupTopic(d.client, topic, d.config.LookupTimeout); err != nil {
+			return nil, err
+		}
+	}
+
+	// the client uses its default timeout for 0 and disables the timeout for
+	// negative values, a disabled timeout also prevents acceptance tests from
+	// detecting leaking goroutines
+	sendTimeout := d.config.SendTimeout
+	if sendTimeout == 0 {
+		sendTimeout = -1
+	}
+
+	producerOpts := pulsar.ProducerOptions{
+		Topic:       topic,
+		Name:        d.config.ProducerName,
+		SendTimeout: sendTimeout,
+
+		ProducerAccessMode: toProducerAccessMode(d.config.ProducerAccessMode),
+		BackOffPolicyFunc:  newReconnectBackoff(d.config.MaxBackoff),
+		CompressionType:    toCompressionType(d.config.CompressionType),
+		CompressionLevel:   toCompressionLevel(d.config.CompressionLevel),
+		MaxPendingMessages: d.config.MaxPendingMessages,
+		// report backpressure instead of blocking, so sends can be throttled
+		DisableBlockIfQueueFull: d.config.AdaptiveThrottling || !d.config.BlockIfQueueFull,
+
+		DisableBatching:         d.config.DisableBatching,
+		BatchingMaxMessages:     uint(d.config.BatchingMaxMessages),
+		BatchingMaxPublishDelay: d.config.BatchingMaxPublishDelay,
+		BatchingMaxSize:         uint(d.config.BatchingMaxSize),
+	}
+	if d.messageCrypto != nil {
+		producerOpts.Encryption = d.messageCrypto.encryptionInfo()
+	}
+	applyOrderingGuarantee(d.config.OrderingGuarantee, &producerOpts)
+	if d.config.ForceSinglePartition {
+		if err := checkPartition(d.client, topic, d.config.ForceSinglePartitionTarget); err != nil {
+			return nil, err
+		}
+		sdk.Logger(ctx).Warn().
+			Int("partition", d.config.ForceSinglePartitionTarget).
+			Msg("all records are routed to a single partition, throughput is limited to a single partition")
+		producerOpts.MessageRouter = newSinglePartitionRouter(d.config.ForceSinglePartitionTarget)
+	}
+	if d.partitionPath != nil {
+		producerOpts.MessageRouter = routeByPartitionKey
+	}
+
+	var waitTimeout time.Duration
+	if d.config.ProducerAccessMode == ProducerAccessModeWaitForExclusive {
+		waitTimeout = d.config.ExclusiveWaitTimeout
+	}
+
+	var producer pulsar.Producer
+	err := retryWithBackoff(ctx, d.config.SchemaRegistryMaxRetries, d.config.SchemaRegistryRetryBackoff, func() (err error) {
+		producer, err = createProducerWithTimeout(func() (pulsar.Producer, error) {
+			return d.client.CreateProducer(producerOpts)
+		}, waitTimeout)
+		return err
 	})
 	if err != nil {
-		return fmt.Errorf("failed to create producer: %w", err)
+		return nil, fmt.Errorf("failed to create producer: %w", err)
+	}
+	sdk.Logger(ctx).Info().Str("topic", topic).Msg("created destination producer")
+
+	if d.schemas != nil {
+		if err := d.loadSchema(ctx, topic); err != nil {
+			producer.Close()
+			return nil, err
+		}
+	}
+
+	if d.partitionCounts != nil {
+		// the partitions can't be fetched anymore once the topic is deleted
+		partitions, err := topicPartitions(d.admin, topic)
+		if err != nil {
+			producer.Close()
+			return nil, err
+		}
+		d.partitionCounts[topic] = partitions
+	}
+
+	if d.config.EnableTopicDeduplication {
+		if err := enableTopicDeduplication(d.admin, topic); err != nil {
+			producer.Close()
+			return nil, err
+		}
+		sdk.Logger(ctx).Info().Str("topic", topic).Msg("enabled topic deduplication")
+
+		if d.adminREST != nil {
+			if err := setDedupSnapshotInterval(d.adminREST, topic, d.config.DedupSnapshotInterval); err != nil {
+				producer.Close()
+				return nil, err
+			}
+			sdk.Logger(ctx).Info().
+				Str("topic", topic).
+				Dur("interval", d.config.DedupSnapshotInterval).
+				Msg("set deduplication snapshot interval")
+		}
+	}
+
+	return producer, nil
+}
+
+// loadSchema fetches the schema of the topic that records produced to it are
+// validated against. Topics without an Avro or JSON schema are not validated.
+func (d *Destination) loadSchema(ctx context.Context, topic string) error {
+	info, err := getTopicSchema(d.admin, topic)
+	if err != nil {
+		return err
+	}
+	if info == nil || (info.Type != pulsarSchemaTypeAvro && info.Type != pulsarSchemaTypeJSON) {
+		sdk.Logger(ctx).Warn().Str("topic", topic).Msg("topic has no Avro or JSON schema, records are not validated")
+		return nil
 	}
-	sdk.Logger(ctx).Info().Msg("created destination producer")
 
+	validator, err := newRecordValidator(info.Schema)
+	if err != nil {
+		return fmt.Errorf("invalid schema of topic %q: %w", topic, err)
+	}
+	d.schemas[topic] = validator
 	return nil
 }
 
-func (d *Destination) Write(ctx context.Context, records []opencdc.Record) (int, error) {
-	var written int
-	for _, record := range records {
-		key := string(record.Key.Bytes())
-		_, err := d.producer.Send(ctx, &pulsar.ProducerMessage{
-			Payload: record.Bytes(),
-			Key:     key,
-		})
+// producerFor returns the producer for the topic the record should be written
+// to, creating it if needed. Messages exceeding the large message threshold
+// are routed to the large message topic.
+func (d *Destination) producerFor(ctx context.Context, record opencdc.Record, msg *pulsar.ProducerMessage) (pulsar.Producer, string, error) {
+	if d.largeProducer != nil && len(msg.Payload) > d.config.LargeMessageThreshold {
+		return d.largeProducer, d.config.LargeMessageTopic, nil
+	}
+	if d.topicTemplate == nil {
+		return d.producer, d.config.Topic, nil
+	}
+
+	topic, err := resolveTopic(d.topicTemplate, record)
+	if err != nil {
+		return nil, "", err
+	}
+
+	producer, ok := d.producers[topic]
+	if !ok {
+		producer, err = d.createProducer(ctx, topic)
 		if err != nil {
-			return written, fmt.Errorf("failed to send message: %w", err)
+			return nil, "", err
+		}
+		d.producers[topic] = producer
+	}
+	return producer, topic, nil
+}
+
+func (d *Destination) Write(ctx context.Context, records []opencdc.Record) (n int, err error) {
+	written := make([]bool, len(records))
+	if d.buffer != nil {
+		// messages sent before a failure are still confirmed by the broker,
+		// they need to be reported as written
+		defer func() {
+			if flushErr := d.flushBuffer(ctx, written); err == nil {
+				err = flushErr
+			}
+			n = writtenPrefix(written)
+		}()
+	}
+
+	var txn pulsar.Transaction
+	if d.config.TransactionalWrites {
+		txn, err = d.client.NewTransaction(d.config.TransactionTimeout)
+		if err != nil {
+			return 0, fmt.Errorf("failed to create transaction: %w", err)
+		}
+		defer func() {
+			n, err = d.endTransaction(ctx, txn, n, err)
+		}()
+	}
+
+	for _, i := range writeOrder(records, d.config.PriorityMetadataKey) {
+		var idempotencyKey string
+		if d.idempotency != nil {
+			var err error
+			idempotencyKey, err = resolveField(records[i], d.config.IdempotencyKeyField)
+			if err != nil {
+				return writtenPrefix(written), fmt.Errorf("failed to resolve idempotency key: %w", err)
+			}
+			if idempotencyKey != "" && d.idempotency.isDuplicate(idempotencyKey) {
+				sdk.Logger(ctx).Debug().Str("idempotencyKey", idempotencyKey).Msg("skipped duplicate record")
+				written[i] = true
+				continue
+			}
+		}
+
+		msg, err := d.newMessage(records[i])
+		if err != nil {
+			return writtenPrefix(written), err
+		}
+		if d.config.AuditMetadata {
+			addAuditProperties(msg, d.auditInstanceID, time.Now())
+		}
+		msg.Transaction = txn
+
+		producer, topic, err := d.producerFor(ctx, records[i], msg)
+		if err != nil {
+			return writtenPrefix(written), err
+		}
+		if validator := d.schemas[topic]; validator != nil {
+			if err := validator.validate(msg.Payload); err != nil {
+				return writtenPrefix(written), fmt.Errorf("failed to validate record for topic %q: %w", topic, err)
+			}
+		}
+		if d.config.SequenceIDField != "" {
+			d.checkSequenceID(ctx, producer, topic, *msg.SequenceID)
+		}
+
+		if d.buffer != nil {
+			d.buffer.add(ctx, &bufferedWrite{index: i, idempotencyKey: idempotencyKey, producer: producer, msg: msg})
+			if d.buffer.shouldFlush() {
+				if err := d.flushBuffer(ctx, written); err != nil {
+					return writtenPrefix(written), err
+				}
+			}
+			continue
+		}
+
+		if d.sequenceStore != nil {
+			sequenceID := d.lastSequenceID + 1
+			msg.SequenceID = &sequenceID
+		}
+
+		if d.fallback != nil && producer == d.producer {
+			topic, err = d.sendWithFallback(ctx, msg)
+		} else {
+			err = d.sendRecovering(ctx, producer, topic, msg)
+		}
+		if err != nil {
+			return writtenPrefix(written), fmt.Errorf("failed to send message: %w", err)
 		}
 
 		sdk.Logger(ctx).Trace().
-			Str("topic", d.config.Topic).
-			Str("key", key).Msg("sent message")
-		written++
+			Str("topic", topic).
+			Str("key", msg.Key).Msg("sent message")
+		written[i] = true
+		if idempotencyKey != "" {
+			d.idempotency.add(idempotencyKey)
+		}
+		if d.sequenceStore != nil {
+			d.lastSequenceID = *msg.SequenceID
+			if err := d.sequenceStore.Save(ctx, d.config.ProducerName, d.lastSequenceID); err != nil {
+				return writtenPrefix(written), fmt.Errorf("failed to store sequence ID: %w", err)
+			}
+		}
+	}
+
+	if d.growth != nil {
+		d.growPartitionsIfNeeded(ctx, len(records))
+	}
+
+	sdk.Logger(ctx).Trace().Int("total", len(records)).Msg("wrote messages to destination")
+	return len(records), nil
+}
+
+// checkSequenceID warns if the sequence ID doesn't increase compared to the
+// highest one sent by the producer. The broker discards such a message as a
+// duplicate if deduplication is enabled, which is expected when records are
+// replayed, but loses data if the field doesn't increase with every record.
+func (d *Destination) checkSequenceID(ctx context.Context, producer pulsar.Producer, topic string, sequenceID int64) {
+	if d.sentSequenceIDs == nil {
+		d.sentSequenceIDs = make(map[pulsar.Producer]int64)
+	}
+	if last, ok := d.sentSequenceIDs[producer]; ok && sequenceID <= last {
+		sdk.Logger(ctx).Warn().
+			Str("topic", topic).
+			Int64("sequenceID", sequenceID).
+			Int64("lastSequenceID", last).
+			Msgf("sequence ID of field %q doesn't increase, the message is discarded if deduplication is enabled", d.config.SequenceIDField)
+		return
+	}
+	d.sentSequenceIDs[producer] = sequenceID
+}
+
+// flushBuffer waits for the broker to confirm the buffered messages and marks
+// their records as written.
+func (d *Destination) flushBuffer(ctx context.Context, written []bool) error {
+	writes, err := d.buffer.flush(ctx)
+	for _, w := range writes {
+		if !w.finished() {
+			// the flush failed before the broker answered
+			continue
+		}
+		if d.config.LogProduceResults {
+			d.logProduceResult(ctx, w.msg, w.msgID, w.err)
+		}
+		if w.err != nil {
+			if err == nil {
+				err = fmt.Errorf("failed to send message: %w", w.err)
+			}
+			continue
+		}
+		written[w.index] = true
+		if w.idempotencyKey != "" {
+			d.idempotency.add(w.idempotencyKey)
+		}
+	}
+	if len(writes) > 0 {
+		sdk.Logger(ctx).Trace().Int("count", len(writes)).Msg("flushed write buffer")
+	}
+	return err
+}
+
+// sendRecovering sends the message and recovers the producer according to
+// TopicNotFoundPolicy if the topic doesn't exist.
+func (d *Destination) sendRecovering(ctx context.Context, producer pulsar.Producer, topic string, msg *pulsar.ProducerMessage) error {
+	err := d.send(ctx, producer, msg)
+	if !isTopicNotFound(err) {
+		return err
+	}
+
+	err = fmt.Errorf("%w: %w", errTopicNotFound, err)
+	if d.config.TopicNotFoundPolicy != TopicNotFoundPolicyError {
+		producer, err = d.recoverProducer(ctx, topic)
+		if err == nil {
+			err = d.send(ctx, producer, msg)
+		}
+	}
+	return err
+}
+
+// send sends the message, retrying it if the backlog quota of the topic is
+// exceeded or if it fails with a retryable error. Sends fail right away while
+// the circuit breaker is open.
+func (d *Destination) send(ctx context.Context, producer pulsar.Producer, msg *pulsar.ProducerMessage) error {
+	if d.breaker != nil {
+		if err := d.breaker.allow(); err != nil {
+			return err
+		}
+	}
+
+	isRetryable := func(err error) bool {
+		return isRetryableProduceError(d.config.ProduceRetryableErrors, err)
+	}
+
+	var msgID pulsar.MessageID
+	err := retryWithBackoffIf(ctx, d.config.ProduceMaxRetries, d.config.ProduceRetryBackoff, isRetryable, func() error {
+		return retryWithBackoffIf(ctx, d.config.BacklogQuotaMaxRetries, d.config.BacklogQuotaRetryBackoff, isBacklogQuotaExceeded, func() (err error) {
+			msgID, err = d.sendOnce(ctx, producer, msg)
+			return err
+		})
+	})
+	if d.config.LogProduceResults {
+		d.logProduceResult(ctx, msg, msgID, err)
+	}
+	if d.breaker != nil && d.breaker.record(err) {
+		sdk.Logger(ctx).Warn().Err(err).
+			Int("failures", d.breaker.failures).
+			Dur("cooldown", d.breaker.cooldown).
+			Msg("circuit breaker opened after consecutive send failures")
+	}
+	if isBacklogQuotaExceeded(err) {
+		return fmt.Errorf("%w: %w", errBacklogQuotaExceeded, err)
+	}
+	return err
+}
+
+// sendOnce sends the message and waits for the broker to confirm it, bounded
+// by the configured produce ack timeout.
+func (d *Destination) sendOnce(ctx context.Context, producer pulsar.Producer, msg *pulsar.ProducerMessage) (pulsar.MessageID, error) {
+	if d.config.ProduceAckTimeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, d.config.ProduceAckTimeout)
+		defer cancel()
+	}
+
+	msgID, err := producer.Send(ctx, msg)
+	if errors.Is(err, pulsar.ErrSendTimeout) {
+		// the producer fails timed out messages and keeps running, so the
+		// send can be retried
+		return nil, fmt.Errorf("sending message with key %q to topic %q timed out after %v: %w", msg.Key, producer.Topic(), d.config.SendTimeout, err)
+	}
+	return msgID, err
+}
+
+// logProduceResult logs whether the broker confirmed the message. Confirmed
+// messages are sampled to avoid flooding the logs, failures are always
+// logged.
+func (d *Destination) logProduceResult(ctx context.Context, msg *pulsar.ProducerMessage, msgID pulsar.MessageID, err error) {
+	logger := sdk.Logger(ctx)
+	if err != nil {
+		logger.Warn().Err(err).Str("key", msg.Key).Msg("broker failed to confirm message")
+		return
+	}
+
+	sampled := logger.Sample(d.resultSampler)
+	sampled.Info().
+		Str("key", msg.Key).
+		Str("messageID", msgID.String()).
+		Msg("broker confirmed message")
+}
+
+// isBacklogQuotaExceeded returns true if the broker rejected the message
+// because the backlog quota of the topic is exceeded.
+func isBacklogQuotaExceeded(err error) bool {
+	if errors.Is(err, pulsar.ErrProducerBlockedQuotaExceeded) {
+		return true
+	}
+	var pulsarErr *pulsar.Error
+	return errors.As(err, &pulsarErr) &&
+		(pulsarErr.Result() == pulsar.ProducerBlockedQuotaExceededError ||
+			pulsarErr.Result() == pulsar.ProducerBlockedQuotaExceededException)
+}
+
+// newMessage converts the record into a message that can be sent to Pulsar.
+func (d *Destination) newMessage(record opencdc.Record) (*pulsar.ProducerMessage, error) {
+	payload := record.Bytes()
+	if len(d.nullValueMarker) > 0 && isNullRecord(record) {
+		payload = d.nullValueMarker
+	} else if d.compressor != nil {
+		payload = d.compressor.compress(payload)
+	}
+
+	msg := &pulsar.ProducerMessage{
+		Payload:    payload,
+		Properties: metadataProperties(record.Metadata, d.config.ForwardAllMetadata),
+	}
+	if record.Key != nil {
+		msg.Key = string(record.Key.Bytes())
+	}
+
+	if d.config.KeyField != "" {
+		key, err := resolveField(record, d.config.KeyField)
+		if err != nil {
+			return nil, fmt.Errorf("failed to resolve key: %w", err)
+		}
+		msg.Key = key
+	}
+	if d.keyPath != nil {
+		var payload []byte
+		if record.Payload.After != nil {
+			payload = record.Payload.After.Bytes()
+		}
+		key, err := d.keyPath.extract(payload)
+		switch {
+		case err == nil:
+			msg.Key = key
+		case d.config.KeyJSONPathFallback == KeyJSONPathFallbackEmpty:
+			msg.Key = ""
+		case d.config.KeyJSONPathFallback == KeyJSONPathFallbackFail:
+			return nil, fmt.Errorf("failed to extract key: %w", err)
+		}
+	}
+	if d.partitionPath != nil {
+		var payload []byte
+		if record.Payload.After != nil {
+			payload = record.Payload.After.Bytes()
+		}
+		partitionKey, err := d.partitionPath.extract(payload)
+		if err != nil {
+			return nil, fmt.Errorf("failed to extract partition field: %w", err)
+		}
+		if msg.Properties == nil {
+			msg.Properties = make(map[string]string)
+		}
+		msg.Properties[propertyPartitionKey] = partitionKey
+	}
+	if d.config.SequenceIDField != "" {
+		sequenceID, err := resolveSequenceID(record, d.config.SequenceIDField)
+		if err != nil {
+			return nil, err
+		}
+		msg.SequenceID = &sequenceID
+	}
+	if d.config.OrderingKeyField != "" {
+		orderingKey, err := resolveField(record, d.config.OrderingKeyField)
+		if err != nil {
+			return nil, fmt.Errorf("failed to resolve ordering key: %w", err)
+		}
+		msg.OrderingKey = orderingKey
+	} else {
+		// keep the ordering key of records read by the source
+		msg.OrderingKey = record.Metadata[metadataOrderingKey]
+	}
+	if err := setDeliveryTime(msg, record.Metadata); err != nil {
+		return nil, err
+	}
+	if key := d.config.DisableReplicationMetadataKey; key != "" {
+		if flag, ok := record.Metadata[key]; ok {
+			disable, err := strconv.ParseBool(flag)
+			if err != nil {
+				return nil, fmt.Errorf("invalid value %q of metadata %q: %w", flag, key, err)
+			}
+			msg.DisableReplication = disable
+		}
 	}
 
-	sdk.Logger(ctx).Trace().Int("total", written).Msg("wrote messages to destination")
-	return written, nil
+	return msg, nil
+}
+
+// closeProducer flushes the messages buffered by the producer before closing
+// it, so no batched message is lost. The producer is closed also if the flush
+// fails.
+func closeProducer(ctx context.Context, producer pulsar.Producer) error {
+	err := producer.FlushWithCtx(ctx)
+	producer.Close()
+	if err != nil {
+		return fmt.Errorf("failed to flush producer of topic %q: %w", producer.Topic(), err)
+	}
+	return nil
+}
+
+// isNullRecord returns true if the record carries no data after the change.
+func isNullRecord(record opencdc.Record) bool {
+	return record.Payload.After == nil || len(record.Payload.After.Bytes()) == 0
 }
 
 func (d *Destination) Teardown(ctx context.Context) error {
+	if d.stopKeyRotation != nil {
+		d.stopKeyRotation()
+	}
+
+	// pending sends are flushed, failures are returned once everything is
+	// closed
+	var errs []error
 	if d.producer != nil {
-		d.producer.Close()
+		errs = append(errs, closeProducer(ctx, d.producer))
+	}
+	for _, producer := range d.producers {
+		errs = append(errs, closeProducer(ctx, producer))
+	}
+	if d.largeProducer != nil {
+		errs = append(errs, closeProducer(ctx, d.largeProducer))
+	}
+	if d.fallbackProducer != nil {
+		errs = append(errs, closeProducer(ctx, d.fallbackProducer))
+	}
+	if d.compressor != nil {
+		d.compressor.close()
 	}
 
 	if d.client != nil {
@@ -124,5 +846,5 @@ func (d *Destination) Teardown(ctx context.Context) error {
 
 	sdk.Logger(ctx).Debug().Msg("destination teardown complete")
 
-	return nil
+	return errors.Join(errs...)
 }
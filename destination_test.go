@@ -15,17 +15,25 @@
 package pulsar
 
 import (
+	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
 	"sync"
 	"testing"
+	"time"
 
 	"github.com/apache/pulsar-client-go/pulsar"
+	"github.com/apache/pulsar-client-go/pulsaradmin"
+	"github.com/apache/pulsar-client-go/pulsaradmin/pkg/utils"
 	"github.com/conduitio-labs/conduit-connector-pulsar/test"
 	"github.com/conduitio/conduit-commons/opencdc"
 	sdk "github.com/conduitio/conduit-connector-sdk"
 	"github.com/google/uuid"
 	"github.com/matryer/is"
+	"github.com/rs/zerolog"
 )
 
 func TestTeardown_NoOpen(t *testing.T) {
@@ -115,3 +123,1722 @@ func pulsarGoClientRead(is *is.I, topic string) {
 	receivedMsg := string(received.Payload.After.Bytes())
 	is.Equal(receivedMsg, exampleMessage)
 }
+
+func TestDestination_Configure_DeduplicationRequiresAdminURL(t *testing.T) {
+	is := is.New(t)
+	con := NewDestination()
+
+	err := con.Configure(context.Background(), map[string]string{
+		DestinationConfigUrl:                      test.PulsarURL,
+		DestinationConfigTopic:                    "test-topic",
+		DestinationConfigEnableTopicDeduplication: "true",
+	})
+	is.True(err != nil)
+}
+
+func TestDestination_Integration_EnableTopicDeduplication(t *testing.T) {
+	is := is.New(t)
+	ctx := context.Background()
+
+	topic := test.SetupTopicName(t, is)
+
+	con := NewDestination()
+	err := con.Configure(ctx, map[string]string{
+		DestinationConfigUrl:                      test.PulsarURL,
+		DestinationConfigTopic:                    topic,
+		DestinationConfigAdminURL:                 test.PulsarAdminURL,
+		DestinationConfigEnableTopicDeduplication: "true",
+	})
+	is.NoErr(err)
+
+	err = con.Open(ctx)
+	is.NoErr(err)
+	defer func() {
+		err := con.Teardown(ctx)
+		is.NoErr(err)
+	}()
+
+	admin, err := pulsaradmin.NewClient(&pulsaradmin.Config{WebServiceURL: test.PulsarAdminURL})
+	is.NoErr(err)
+
+	topicName, err := utils.GetTopicName(topic)
+	is.NoErr(err)
+
+	// topic policies are applied asynchronously by the broker
+	var enabled bool
+	for i := 0; i < 50 && !enabled; i++ {
+		enabled, err = admin.Topics().GetDeduplicationStatus(*topicName)
+		is.NoErr(err)
+		time.Sleep(100 * time.Millisecond)
+	}
+	is.True(enabled)
+}
+
+func TestDestination_Configure_DedupSnapshotIntervalRequiresDeduplication(t *testing.T) {
+	is := is.New(t)
+	con := NewDestination()
+
+	err := con.Configure(context.Background(), map[string]string{
+		DestinationConfigUrl:                   test.PulsarURL,
+		DestinationConfigTopic:                 "test-topic",
+		DestinationConfigAdminURL:              test.PulsarAdminURL,
+		DestinationConfigDedupSnapshotInterval: "1m",
+	})
+	is.True(err != nil)
+}
+
+func TestDestination_Integration_DedupSnapshotInterval(t *testing.T) {
+	is := is.New(t)
+	ctx := context.Background()
+
+	topic := test.SetupTopicName(t, is)
+
+	con := NewDestination()
+	err := con.Configure(ctx, map[string]string{
+		DestinationConfigUrl:                      test.PulsarURL,
+		DestinationConfigTopic:                    topic,
+		DestinationConfigAdminURL:                 test.PulsarAdminURL,
+		DestinationConfigEnableTopicDeduplication: "true",
+		DestinationConfigDedupSnapshotInterval:    "2m",
+	})
+	is.NoErr(err)
+
+	err = con.Open(ctx)
+	is.NoErr(err)
+	defer func() {
+		err := con.Teardown(ctx)
+		is.NoErr(err)
+	}()
+
+	restClient, err := newAdminRESTClient(Config{AdminURL: test.PulsarAdminURL})
+	is.NoErr(err)
+
+	topicName, err := utils.GetTopicName(topic)
+	is.NoErr(err)
+
+	// topic policies are applied asynchronously by the broker
+	var interval int
+	for i := 0; i < 50 && interval == 0; i++ {
+		err = restClient.Get(dedupSnapshotIntervalEndpoint(*topicName), &interval)
+		is.NoErr(err)
+		time.Sleep(100 * time.Millisecond)
+	}
+	is.Equal(interval, 120)
+}
+
+func TestDestination_Integration_NullValueMarker(t *testing.T) {
+	is := is.New(t)
+	ctx := context.Background()
+
+	topic := test.SetupTopicName(t, is)
+
+	con := NewDestination()
+	err := con.Configure(ctx, map[string]string{
+		DestinationConfigUrl:             test.PulsarURL,
+		DestinationConfigTopic:           topic,
+		DestinationConfigNullValueMarker: "deadbeef",
+	})
+	is.NoErr(err)
+
+	err = con.Open(ctx)
+	is.NoErr(err)
+	defer func() {
+		err := con.Teardown(ctx)
+		is.NoErr(err)
+	}()
+
+	rec := sdk.Util.Source.NewRecordDelete(
+		[]byte(uuid.NewString()),
+		opencdc.Metadata{},
+		opencdc.RawData("test-key"),
+		nil,
+	)
+
+	written, err := con.Write(ctx, []opencdc.Record{rec})
+	is.NoErr(err)
+	is.Equal(written, 1)
+
+	msgs := consumePulsarMsgs(is, topic, 1)
+	is.Equal(msgs[0].Payload(), []byte{0xde, 0xad, 0xbe, 0xef})
+	is.Equal(msgs[0].Key(), "test-key")
+}
+
+// consumePulsarMsgs reads n messages from the beginning of the topic.
+func consumePulsarMsgs(is *is.I, topic string, n int) []pulsar.Message {
+	client, err := pulsar.NewClient(pulsar.ClientOptions{
+		URL: test.PulsarURL,
+	})
+	is.NoErr(err)
+	defer client.Close()
+
+	consumer, err := client.Subscribe(pulsar.ConsumerOptions{
+		Topic:                       topic,
+		SubscriptionName:            topic + "-verify",
+		SubscriptionInitialPosition: pulsar.SubscriptionPositionEarliest,
+	})
+	is.NoErr(err)
+	defer consumer.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	msgs := make([]pulsar.Message, 0, n)
+	for len(msgs) < n {
+		msg, err := consumer.Receive(ctx)
+		is.NoErr(err)
+		msgs = append(msgs, msg)
+	}
+	return msgs
+}
+
+// stalledProducer never receives a send confirmation from the broker.
+type stalledProducer struct {
+	pulsar.Producer
+}
+
+func (stalledProducer) Send(ctx context.Context, _ *pulsar.ProducerMessage) (pulsar.MessageID, error) {
+	<-ctx.Done()
+	return nil, ctx.Err()
+}
+
+func TestDestination_Write_ProduceAckTimeout(t *testing.T) {
+	is := is.New(t)
+
+	con := &Destination{
+		producer: stalledProducer{},
+		config: DestinationConfig{
+			ProduceAckTimeout: 50 * time.Millisecond,
+		},
+	}
+
+	rec := sdk.Util.Source.NewRecordCreate(
+		[]byte(uuid.NewString()),
+		opencdc.Metadata{},
+		opencdc.RawData("test-key"),
+		opencdc.RawData(exampleMessage),
+	)
+
+	written, err := con.Write(context.Background(), []opencdc.Record{rec})
+	is.True(errors.Is(err, context.DeadlineExceeded))
+	is.Equal(written, 0)
+}
+
+// recordingProducer records sent messages and fails once it sent failAfter
+// messages, simulating a backpressured broker.
+type recordingProducer struct {
+	pulsar.Producer
+
+	failAfter int
+	sent      []*pulsar.ProducerMessage
+}
+
+func (p *recordingProducer) Send(_ context.Context, msg *pulsar.ProducerMessage) (pulsar.MessageID, error) {
+	if p.failAfter > 0 && len(p.sent) == p.failAfter {
+		return nil, pulsar.ErrProducerBlockedQuotaExceeded
+	}
+	p.sent = append(p.sent, msg)
+	return pulsar.EarliestMessageID(), nil
+}
+
+func TestDestination_Write_Priority(t *testing.T) {
+	is := is.New(t)
+
+	producer := &recordingProducer{failAfter: 3}
+	con := &Destination{
+		producer: producer,
+		config: DestinationConfig{
+			PriorityMetadataKey: "priority",
+		},
+	}
+
+	var records []opencdc.Record
+	for i, priority := range []string{"1", "", "5", "1", "10"} {
+		records = append(records, sdk.Util.Source.NewRecordCreate(
+			[]byte(uuid.NewString()),
+			opencdc.Metadata{"priority": priority},
+			opencdc.RawData(fmt.Sprintf("key-%d", i)),
+			opencdc.RawData(exampleMessage),
+		))
+	}
+
+	written, err := con.Write(context.Background(), records)
+	is.True(errors.Is(err, pulsar.ErrProducerBlockedQuotaExceeded))
+
+	var sentKeys []string
+	for _, msg := range producer.sent {
+		sentKeys = append(sentKeys, msg.Key)
+	}
+	is.Equal(sentKeys, []string{"key-4", "key-2", "key-0"})
+
+	// key-1 was not sent, so only the first record counts as written
+	is.Equal(written, 1)
+}
+
+func TestDestination_Write_PriorityKeepsKeyOrder(t *testing.T) {
+	is := is.New(t)
+
+	producer := &recordingProducer{}
+	con := &Destination{
+		producer: producer,
+		config: DestinationConfig{
+			PriorityMetadataKey: "priority",
+		},
+	}
+
+	var records []opencdc.Record
+	for i, r := range []struct{ key, priority string }{
+		{"a", "1"}, {"b", "2"}, {"a", "5"}, {"c", "3"}, {"b", "1"},
+	} {
+		records = append(records, sdk.Util.Source.NewRecordCreate(
+			[]byte(uuid.NewString()),
+			opencdc.Metadata{"priority": r.priority},
+			opencdc.RawData(r.key),
+			opencdc.RawData(fmt.Sprintf("record-%d", i)),
+		))
+	}
+
+	written, err := con.Write(context.Background(), records)
+	is.NoErr(err)
+	is.Equal(written, len(records))
+
+	var sent []string
+	for _, msg := range producer.sent {
+		var rec opencdc.Record
+		is.NoErr(json.Unmarshal(msg.Payload, &rec))
+		sent = append(sent, string(rec.Payload.After.Bytes()))
+	}
+	// record-2 has the highest priority but can't overtake record-0 with the
+	// same key, record-4 can't overtake record-1
+	is.Equal(sent, []string{"record-0", "record-3", "record-1", "record-2", "record-4"})
+}
+
+func TestDestination_Configure_PriorityMetadataKey(t *testing.T) {
+	testCases := []struct {
+		name    string
+		cfg     map[string]string
+		wantErr bool
+	}{
+		{name: "key ordering", cfg: map[string]string{}, wantErr: true},
+		{name: "partition ordering", cfg: map[string]string{
+			DestinationConfigOrderingGuarantee: OrderingGuaranteePartition,
+		}},
+		{name: "with sequence ID field", cfg: map[string]string{
+			DestinationConfigOrderingGuarantee: OrderingGuaranteeNone,
+			DestinationConfigProducerName:      "test-producer",
+			DestinationConfigSequenceIDField:   ".Metadata.lsn",
+		}, wantErr: true},
+		{name: "with sequence store", cfg: map[string]string{
+			DestinationConfigOrderingGuarantee: OrderingGuaranteeNone,
+			DestinationConfigProducerName:      "test-producer",
+			DestinationConfigSequenceStorePath: t.TempDir(),
+		}, wantErr: true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			is := is.New(t)
+
+			tc.cfg[DestinationConfigUrl] = test.PulsarURL
+			tc.cfg[DestinationConfigTopic] = "test-topic"
+			tc.cfg[DestinationConfigPriorityMetadataKey] = "priority"
+			err := NewDestination().Configure(context.Background(), tc.cfg)
+			is.Equal(err != nil, tc.wantErr)
+		})
+	}
+}
+
+func TestDestination_Write_DisableReplicationMetadataKey(t *testing.T) {
+	is := is.New(t)
+
+	producer := &recordingProducer{}
+	con := &Destination{
+		producer: producer,
+		config: DestinationConfig{
+			DisableReplicationMetadataKey: "replication.disabled",
+		},
+	}
+
+	var records []opencdc.Record
+	for i, md := range []opencdc.Metadata{
+		{"replication.disabled": "true"},
+		{},
+		{"replication.disabled": "false"},
+		{"replication.disabled": "true"},
+	} {
+		records = append(records, sdk.Util.Source.NewRecordCreate(
+			[]byte(uuid.NewString()),
+			md,
+			opencdc.RawData(fmt.Sprintf("key-%d", i)),
+			opencdc.RawData(exampleMessage),
+		))
+	}
+
+	written, err := con.Write(context.Background(), records)
+	is.NoErr(err)
+	is.Equal(written, len(records))
+
+	var disabled []bool
+	for _, msg := range producer.sent {
+		disabled = append(disabled, msg.DisableReplication)
+	}
+	is.Equal(disabled, []bool{true, false, false, true})
+}
+
+func TestDestination_Write_InvalidDisableReplicationFlag(t *testing.T) {
+	is := is.New(t)
+
+	producer := &recordingProducer{}
+	con := &Destination{
+		producer: producer,
+		config: DestinationConfig{
+			DisableReplicationMetadataKey: "replication.disabled",
+		},
+	}
+
+	rec := sdk.Util.Source.NewRecordCreate(
+		[]byte(uuid.NewString()),
+		opencdc.Metadata{"replication.disabled": "maybe"},
+		opencdc.RawData("test-key"),
+		opencdc.RawData(exampleMessage),
+	)
+
+	written, err := con.Write(context.Background(), []opencdc.Record{rec})
+	is.True(err != nil)
+	is.Equal(written, 0)
+	is.Equal(len(producer.sent), 0)
+}
+
+// memorySequenceStore keeps sequence IDs in memory.
+type memorySequenceStore map[string]int64
+
+func (m memorySequenceStore) Save(_ context.Context, producerName string, sequenceID int64) error {
+	m[producerName] = sequenceID
+	return nil
+}
+
+func (m memorySequenceStore) Load(_ context.Context, producerName string) (int64, error) {
+	if sequenceID, ok := m[producerName]; ok {
+		return sequenceID, nil
+	}
+	return -1, nil
+}
+
+// sequencedProducer is a recordingProducer that reports the last sequence ID
+// known to the broker.
+type sequencedProducer struct {
+	recordingProducer
+	lastSequenceID int64
+}
+
+func (p *sequencedProducer) LastSequenceID() int64 {
+	return p.lastSequenceID
+}
+
+func TestDestination_Write_SequenceStore(t *testing.T) {
+	is := is.New(t)
+	ctx := context.Background()
+
+	store := memorySequenceStore{}
+	newDestination := func(producer *sequencedProducer) *Destination {
+		con := &Destination{
+			producer:      producer,
+			sequenceStore: store,
+			config:        DestinationConfig{ProducerName: "test-producer"},
+		}
+		err := con.loadSequenceID(ctx)
+		is.NoErr(err)
+		return con
+	}
+	write := func(con *Destination, keys ...string) {
+		var records []opencdc.Record
+		for _, key := range keys {
+			records = append(records, sdk.Util.Source.NewRecordCreate(
+				[]byte(uuid.NewString()),
+				nil,
+				opencdc.RawData(key),
+				opencdc.RawData(exampleMessage),
+			))
+		}
+		written, err := con.Write(ctx, records)
+		is.NoErr(err)
+		is.Equal(written, len(records))
+	}
+	sequenceIDs := func(producer *sequencedProducer) []int64 {
+		var ids []int64
+		for _, msg := range producer.sent {
+			ids = append(ids, *msg.SequenceID)
+		}
+		return ids
+	}
+
+	// without a stored sequence ID the sequence continues from the broker
+	first := &sequencedProducer{lastSequenceID: 9}
+	write(newDestination(first), "key-1", "key-2")
+	is.Equal(sequenceIDs(first), []int64{10, 11})
+	is.Equal(store["test-producer"], int64(11))
+
+	// after a restart the stored sequence ID takes precedence
+	second := &sequencedProducer{lastSequenceID: 12}
+	write(newDestination(second), "key-3")
+	is.Equal(sequenceIDs(second), []int64{12})
+	is.Equal(store["test-producer"], int64(12))
+}
+
+func TestDestination_Configure_SequenceStoreRequiresProducerName(t *testing.T) {
+	is := is.New(t)
+	con := NewDestination()
+
+	err := con.Configure(context.Background(), map[string]string{
+		DestinationConfigUrl:               test.PulsarURL,
+		DestinationConfigTopic:             "test-topic",
+		DestinationConfigSequenceStorePath: t.TempDir(),
+	})
+	is.True(err != nil)
+}
+
+func TestDestination_Integration_SequenceStore(t *testing.T) {
+	is := is.New(t)
+	ctx := context.Background()
+
+	topic := test.SetupTopicName(t, is)
+	cfgMap := map[string]string{
+		DestinationConfigUrl:                      test.PulsarURL,
+		DestinationConfigTopic:                    topic,
+		DestinationConfigAdminURL:                 test.PulsarAdminURL,
+		DestinationConfigEnableTopicDeduplication: "true",
+		DestinationConfigProducerName:             "producer-" + topic,
+		DestinationConfigSequenceStorePath:        t.TempDir(),
+	}
+	store := newFileSequenceStore(cfgMap[DestinationConfigSequenceStorePath])
+
+	records := make([]opencdc.Record, 3)
+	for i := range records {
+		records[i] = sdk.Util.Source.NewRecordCreate(
+			[]byte(uuid.NewString()),
+			nil,
+			opencdc.RawData(fmt.Sprintf("key-%d", i)),
+			opencdc.RawData(exampleMessage),
+		)
+	}
+	write := func(records ...opencdc.Record) {
+		con := NewDestination()
+		err := con.Configure(ctx, cfgMap)
+		is.NoErr(err)
+		err = con.Open(ctx)
+		is.NoErr(err)
+		defer func() {
+			err := con.Teardown(ctx)
+			is.NoErr(err)
+		}()
+
+		written, err := con.Write(ctx, records)
+		is.NoErr(err)
+		is.Equal(written, len(records))
+	}
+
+	write(records[0], records[1])
+	sequenceID, err := store.Load(ctx, cfgMap[DestinationConfigProducerName])
+	is.NoErr(err)
+
+	// simulate a crash after the second record was confirmed but before its
+	// sequence ID was stored, the record is written again after the restart
+	err = store.Save(ctx, cfgMap[DestinationConfigProducerName], sequenceID-1)
+	is.NoErr(err)
+	write(records[1], records[2])
+
+	sequenceID, err = store.Load(ctx, cfgMap[DestinationConfigProducerName])
+	is.NoErr(err)
+	is.Equal(sequenceID, int64(2))
+
+	client, err := pulsar.NewClient(pulsar.ClientOptions{URL: test.PulsarURL})
+	is.NoErr(err)
+	defer client.Close()
+
+	reader, err := client.CreateReader(pulsar.ReaderOptions{
+		Topic:          topic,
+		StartMessageID: pulsar.EarliestMessageID(),
+	})
+	is.NoErr(err)
+	defer reader.Close()
+
+	var keys []string
+	for reader.HasNext() {
+		msg, err := reader.Next(ctx)
+		is.NoErr(err)
+		keys = append(keys, msg.Key())
+	}
+	is.Equal(keys, []string{"key-0", "key-1", "key-2"})
+}
+
+// deduplicatingProducer drops messages with a sequence ID that is not higher
+// than the last persisted one, like the broker with deduplication enabled.
+type deduplicatingProducer struct {
+	recordingProducer
+	lastSequenceID int64
+}
+
+func (p *deduplicatingProducer) Send(ctx context.Context, msg *pulsar.ProducerMessage) (pulsar.MessageID, error) {
+	if msg.SequenceID != nil && *msg.SequenceID <= p.lastSequenceID {
+		return pulsar.EarliestMessageID(), nil
+	}
+	if msg.SequenceID != nil {
+		p.lastSequenceID = *msg.SequenceID
+	}
+	return p.recordingProducer.Send(ctx, msg)
+}
+
+func TestDestination_Write_SequenceIDField(t *testing.T) {
+	is := is.New(t)
+	var logs bytes.Buffer
+	ctx := zerolog.New(&logs).Level(zerolog.WarnLevel).WithContext(context.Background())
+
+	con := &Destination{}
+	err := con.Configure(ctx, map[string]string{
+		DestinationConfigUrl:             test.PulsarURL,
+		DestinationConfigTopic:           "test-topic",
+		DestinationConfigProducerName:    "test-producer",
+		DestinationConfigSequenceIDField: ".Metadata.lsn",
+	})
+	is.NoErr(err)
+	producer := &deduplicatingProducer{lastSequenceID: -1}
+	con.producer = producer
+
+	records := func(lsns ...string) []opencdc.Record {
+		var records []opencdc.Record
+		for _, lsn := range lsns {
+			records = append(records, sdk.Util.Source.NewRecordCreate(
+				[]byte(uuid.NewString()),
+				opencdc.Metadata{"lsn": lsn},
+				opencdc.RawData("key-"+lsn),
+				opencdc.RawData(exampleMessage),
+			))
+		}
+		return records
+	}
+
+	written, err := con.Write(ctx, records("10", "11", "12"))
+	is.NoErr(err)
+	is.Equal(written, 3)
+	is.Equal(logs.Len(), 0)
+
+	// replaying the same source data produces the same sequence IDs, which
+	// the broker deduplicates, the regressions are logged
+	written, err = con.Write(ctx, records("11", "12", "13"))
+	is.NoErr(err)
+	is.Equal(written, 3)
+	is.Equal(strings.Count(logs.String(), "doesn't increase"), 2)
+	is.True(strings.Contains(logs.String(), `"sequenceID":11,"lastSequenceID":12`))
+
+	var sequenceIDs []int64
+	for _, msg := range producer.sent {
+		sequenceIDs = append(sequenceIDs, *msg.SequenceID)
+	}
+	is.Equal(sequenceIDs, []int64{10, 11, 12, 13})
+
+	// records without a sequence ID can't be written
+	written, err = con.Write(ctx, records("not-a-number"))
+	is.True(err != nil)
+	is.Equal(written, 0)
+}
+
+func TestDestination_Configure_SequenceIDField(t *testing.T) {
+	testCases := []struct {
+		name string
+		cfg  map[string]string
+	}{
+		{name: "invalid field", cfg: map[string]string{
+			DestinationConfigProducerName:    "test-producer",
+			DestinationConfigSequenceIDField: "lsn",
+		}},
+		{name: "without producer name", cfg: map[string]string{
+			DestinationConfigSequenceIDField: ".Metadata.lsn",
+		}},
+		{name: "with sequence store", cfg: map[string]string{
+			DestinationConfigProducerName:      "test-producer",
+			DestinationConfigSequenceIDField:   ".Metadata.lsn",
+			DestinationConfigSequenceStorePath: t.TempDir(),
+		}},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			is := is.New(t)
+
+			tc.cfg[DestinationConfigUrl] = test.PulsarURL
+			tc.cfg[DestinationConfigTopic] = "test-topic"
+			is.True(NewDestination().Configure(context.Background(), tc.cfg) != nil)
+		})
+	}
+}
+
+func TestDestination_Integration_SequenceIDField(t *testing.T) {
+	is := is.New(t)
+	ctx := context.Background()
+
+	topic := test.SetupTopicName(t, is)
+	cfgMap := map[string]string{
+		DestinationConfigUrl:                      test.PulsarURL,
+		DestinationConfigTopic:                    topic,
+		DestinationConfigAdminURL:                 test.PulsarAdminURL,
+		DestinationConfigEnableTopicDeduplication: "true",
+		DestinationConfigProducerName:             "producer-" + topic,
+		DestinationConfigSequenceIDField:          ".Metadata.lsn",
+	}
+
+	records := make([]opencdc.Record, 3)
+	for i := range records {
+		records[i] = sdk.Util.Source.NewRecordCreate(
+			[]byte(uuid.NewString()),
+			opencdc.Metadata{"lsn": fmt.Sprint(i)},
+			opencdc.RawData(fmt.Sprintf("key-%d", i)),
+			opencdc.RawData(exampleMessage),
+		)
+	}
+	write := func(records ...opencdc.Record) {
+		con := NewDestination()
+		err := con.Configure(ctx, cfgMap)
+		is.NoErr(err)
+		err = con.Open(ctx)
+		is.NoErr(err)
+		defer func() {
+			err := con.Teardown(ctx)
+			is.NoErr(err)
+		}()
+
+		written, err := con.Write(ctx, records)
+		is.NoErr(err)
+		is.Equal(written, len(records))
+	}
+
+	// the replayed records are deduplicated by the broker
+	write(records[0], records[1])
+	write(records...)
+
+	client, err := pulsar.NewClient(pulsar.ClientOptions{URL: test.PulsarURL})
+	is.NoErr(err)
+	defer client.Close()
+
+	reader, err := client.CreateReader(pulsar.ReaderOptions{
+		Topic:          topic,
+		StartMessageID: pulsar.EarliestMessageID(),
+	})
+	is.NoErr(err)
+	defer reader.Close()
+
+	var keys []string
+	for reader.HasNext() {
+		msg, err := reader.Next(ctx)
+		is.NoErr(err)
+		keys = append(keys, msg.Key())
+	}
+	is.Equal(keys, []string{"key-0", "key-1", "key-2"})
+}
+
+func TestDestination_Integration_ForceSinglePartition(t *testing.T) {
+	is := is.New(t)
+	ctx := context.Background()
+
+	topic := test.SetupPartitionedTopic(t, is, 3)
+
+	con := NewDestination()
+	err := con.Configure(ctx, map[string]string{
+		DestinationConfigUrl:                        test.PulsarURL,
+		DestinationConfigTopic:                      topic,
+		DestinationConfigForceSinglePartition:       "true",
+		DestinationConfigForceSinglePartitionTarget: "2",
+	})
+	is.NoErr(err)
+
+	err = con.Open(ctx)
+	is.NoErr(err)
+	defer func() {
+		err := con.Teardown(ctx)
+		is.NoErr(err)
+	}()
+
+	var records []opencdc.Record
+	for i := 0; i < 5; i++ {
+		records = append(records, sdk.Util.Source.NewRecordCreate(
+			[]byte(uuid.NewString()),
+			opencdc.Metadata{},
+			opencdc.RawData(fmt.Sprintf("key-%d", i)),
+			opencdc.RawData(exampleMessage),
+		))
+	}
+
+	written, err := con.Write(ctx, records)
+	is.NoErr(err)
+	is.Equal(written, len(records))
+
+	msgs := consumePulsarMsgs(is, topic+"-partition-2", len(records))
+	for i, msg := range msgs {
+		is.Equal(msg.Key(), fmt.Sprintf("key-%d", i))
+	}
+}
+
+func TestDestination_Integration_ForceSinglePartition_InvalidTarget(t *testing.T) {
+	is := is.New(t)
+	ctx := context.Background()
+
+	topic := test.SetupPartitionedTopic(t, is, 3)
+
+	con := NewDestination()
+	err := con.Configure(ctx, map[string]string{
+		DestinationConfigUrl:                        test.PulsarURL,
+		DestinationConfigTopic:                      topic,
+		DestinationConfigForceSinglePartition:       "true",
+		DestinationConfigForceSinglePartitionTarget: "3",
+	})
+	is.NoErr(err)
+
+	err = con.Open(ctx)
+	is.True(err != nil)
+
+	err = con.Teardown(ctx)
+	is.NoErr(err)
+}
+
+// quotaProducer rejects the first messages because the backlog quota of the
+// topic is exceeded.
+type quotaProducer struct {
+	pulsar.Producer
+
+	rejections int
+	attempts   int
+}
+
+func (p *quotaProducer) Send(context.Context, *pulsar.ProducerMessage) (pulsar.MessageID, error) {
+	p.attempts++
+	if p.attempts <= p.rejections {
+		return nil, pulsar.ErrProducerBlockedQuotaExceeded
+	}
+	return pulsar.EarliestMessageID(), nil
+}
+
+func TestDestination_Write_BacklogQuotaExceeded(t *testing.T) {
+	testCases := []struct {
+		name        string
+		maxRetries  int
+		wantWritten int
+		wantErr     bool
+	}{
+		{name: "no retries", maxRetries: 0, wantWritten: 0, wantErr: true},
+		{name: "recovers", maxRetries: 2, wantWritten: 1, wantErr: false},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			is := is.New(t)
+
+			producer := &quotaProducer{rejections: 2}
+			con := &Destination{
+				producer: producer,
+				config: DestinationConfig{
+					BacklogQuotaMaxRetries:   tc.maxRetries,
+					BacklogQuotaRetryBackoff: time.Millisecond,
+				},
+			}
+
+			rec := sdk.Util.Source.NewRecordCreate(
+				[]byte(uuid.NewString()),
+				opencdc.Metadata{},
+				opencdc.RawData("test-key"),
+				opencdc.RawData(exampleMessage),
+			)
+
+			written, err := con.Write(context.Background(), []opencdc.Record{rec})
+			is.Equal(written, tc.wantWritten)
+			is.Equal(err != nil, tc.wantErr)
+			is.Equal(errors.Is(err, errBacklogQuotaExceeded), tc.wantErr)
+			is.Equal(producer.attempts, tc.maxRetries+1)
+		})
+	}
+}
+
+func TestDestination_Write_ReKeyed(t *testing.T) {
+	is := is.New(t)
+
+	producer := &recordingProducer{}
+	con := &Destination{
+		producer: producer,
+		config: DestinationConfig{
+			KeyField:         ".Payload.After.customerID",
+			OrderingKeyField: ".Key",
+		},
+	}
+
+	rec := sdk.Util.Source.NewRecordCreate(
+		[]byte(uuid.NewString()),
+		opencdc.Metadata{},
+		opencdc.RawData("order-1"),
+		opencdc.StructuredData{"customerID": 42},
+	)
+
+	written, err := con.Write(context.Background(), []opencdc.Record{rec})
+	is.NoErr(err)
+	is.Equal(written, 1)
+
+	is.Equal(len(producer.sent), 1)
+	is.Equal(producer.sent[0].Key, "42")
+	is.Equal(producer.sent[0].OrderingKey, "order-1")
+}
+
+func TestDestination_Write_KeyJSONPath(t *testing.T) {
+	testCases := []struct {
+		fallback string
+		wantKeys []string
+		wantErr  bool
+	}{
+		{fallback: KeyJSONPathFallbackKey, wantKeys: []string{"42", "record-key"}},
+		{fallback: KeyJSONPathFallbackEmpty, wantKeys: []string{"42", ""}},
+		{fallback: KeyJSONPathFallbackFail, wantKeys: []string{"42"}, wantErr: true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.fallback, func(t *testing.T) {
+			is := is.New(t)
+			ctx := context.Background()
+
+			con := &Destination{}
+			err := con.Configure(ctx, map[string]string{
+				DestinationConfigUrl:                 test.PulsarURL,
+				DestinationConfigTopic:               "test-topic",
+				DestinationConfigKeyJSONPath:         "$.customer.id",
+				DestinationConfigKeyJSONPathFallback: tc.fallback,
+			})
+			is.NoErr(err)
+			producer := &recordingProducer{}
+			con.producer = producer
+
+			records := []opencdc.Record{
+				sdk.Util.Source.NewRecordCreate(
+					[]byte(uuid.NewString()),
+					opencdc.Metadata{},
+					opencdc.RawData("record-key"),
+					opencdc.RawData(`{"customer": {"id": 42}}`),
+				),
+				sdk.Util.Source.NewRecordCreate(
+					[]byte(uuid.NewString()),
+					opencdc.Metadata{},
+					opencdc.RawData("record-key"),
+					opencdc.StructuredData{"order": 1},
+				),
+			}
+
+			written, err := con.Write(ctx, records)
+			is.Equal(err != nil, tc.wantErr)
+			is.Equal(written, len(tc.wantKeys))
+
+			var keys []string
+			for _, msg := range producer.sent {
+				keys = append(keys, msg.Key)
+			}
+			is.Equal(keys, tc.wantKeys)
+		})
+	}
+}
+
+func TestDestination_Configure_InvalidKeyJSONPath(t *testing.T) {
+	is := is.New(t)
+	con := NewDestination()
+
+	err := con.Configure(context.Background(), map[string]string{
+		DestinationConfigUrl:         test.PulsarURL,
+		DestinationConfigTopic:       "test-topic",
+		DestinationConfigKeyJSONPath: "$.orders[*].id",
+	})
+	is.True(err != nil)
+}
+
+func TestDestination_Write_PartitionByField(t *testing.T) {
+	is := is.New(t)
+	ctx := context.Background()
+
+	con := &Destination{}
+	err := con.Configure(ctx, map[string]string{
+		DestinationConfigUrl:              test.PulsarURL,
+		DestinationConfigTopic:            "test-topic",
+		DestinationConfigPartitionByField: "$.customer.id",
+	})
+	is.NoErr(err)
+	producer := &recordingProducer{}
+	con.producer = producer
+
+	var records []opencdc.Record
+	for i, customer := range []string{"42", "7", "42", "7", "42"} {
+		records = append(records, sdk.Util.Source.NewRecordCreate(
+			[]byte(uuid.NewString()),
+			opencdc.Metadata{},
+			opencdc.RawData(fmt.Sprintf("order-%d", i)),
+			opencdc.RawData(fmt.Sprintf(`{"customer": {"id": %s}}`, customer)),
+		))
+	}
+
+	written, err := con.Write(ctx, records)
+	is.NoErr(err)
+	is.Equal(written, len(records))
+
+	// records with the same field value land on the same partition,
+	// independent of their keys
+	partitions := make(map[string]int)
+	for _, msg := range producer.sent {
+		customer := msg.Properties[propertyPartitionKey]
+		partition := routeByPartitionKey(msg, topicMetadata(16))
+		if want, ok := partitions[customer]; ok {
+			is.Equal(partition, want)
+		}
+		partitions[customer] = partition
+	}
+	is.Equal(len(partitions), 2)
+
+	// records without the field can't be routed
+	written, err = con.Write(ctx, []opencdc.Record{
+		sdk.Util.Source.NewRecordCreate([]byte(uuid.NewString()), opencdc.Metadata{}, opencdc.RawData("order"), opencdc.RawData(`{}`)),
+	})
+	is.True(err != nil)
+	is.Equal(written, 0)
+}
+
+func TestDestination_Configure_PartitionByField(t *testing.T) {
+	testCases := []struct {
+		name    string
+		cfg     map[string]string
+		wantErr bool
+	}{
+		{name: "valid", cfg: map[string]string{DestinationConfigPartitionByField: "$.customer.id"}},
+		{name: "invalid expression", cfg: map[string]string{DestinationConfigPartitionByField: "$.orders[*].id"}, wantErr: true},
+		{name: "single partition", cfg: map[string]string{
+			DestinationConfigPartitionByField:     "$.customer.id",
+			DestinationConfigForceSinglePartition: "true",
+		}, wantErr: true},
+		{name: "ordering guarantee", cfg: map[string]string{
+			DestinationConfigPartitionByField:  "$.customer.id",
+			DestinationConfigOrderingGuarantee: OrderingGuaranteeNone,
+		}, wantErr: true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			is := is.New(t)
+
+			tc.cfg[DestinationConfigUrl] = test.PulsarURL
+			tc.cfg[DestinationConfigTopic] = "test-topic"
+			err := NewDestination().Configure(context.Background(), tc.cfg)
+			is.Equal(err != nil, tc.wantErr)
+		})
+	}
+}
+
+func TestDestination_Configure_InvalidKeyField(t *testing.T) {
+	is := is.New(t)
+	con := NewDestination()
+
+	err := con.Configure(context.Background(), map[string]string{
+		DestinationConfigUrl:      test.PulsarURL,
+		DestinationConfigTopic:    "test-topic",
+		DestinationConfigKeyField: "customerID",
+	})
+	is.True(err != nil)
+}
+
+func TestDestination_Write_LogProduceResults(t *testing.T) {
+	is := is.New(t)
+
+	var buf bytes.Buffer
+	ctx := zerolog.New(&buf).WithContext(context.Background())
+
+	con := &Destination{}
+	err := con.Configure(ctx, map[string]string{
+		DestinationConfigUrl:                         test.PulsarURL,
+		DestinationConfigTopic:                       "test-topic",
+		DestinationConfigLogProduceResults:           "true",
+		DestinationConfigLogProduceResultsSampleRate: "2",
+	})
+	is.NoErr(err)
+	con.producer = &recordingProducer{}
+
+	var records []opencdc.Record
+	for i := 0; i < 4; i++ {
+		records = append(records, sdk.Util.Source.NewRecordCreate(
+			[]byte(uuid.NewString()),
+			opencdc.Metadata{},
+			opencdc.RawData(fmt.Sprintf("key-%d", i)),
+			opencdc.RawData(exampleMessage),
+		))
+	}
+
+	buf.Reset()
+	written, err := con.Write(ctx, records)
+	is.NoErr(err)
+	is.Equal(written, len(records))
+
+	var logged []string
+	dec := json.NewDecoder(&buf)
+	for dec.More() {
+		var entry struct {
+			Message   string `json:"message"`
+			Key       string `json:"key"`
+			MessageID string `json:"messageID"`
+		}
+		is.NoErr(dec.Decode(&entry))
+		if entry.Message != "broker confirmed message" {
+			continue
+		}
+		is.Equal(entry.MessageID, pulsar.EarliestMessageID().String())
+		logged = append(logged, entry.Key)
+	}
+	// every second confirmed message is logged
+	is.Equal(logged, []string{"key-0", "key-2"})
+}
+
+func TestDestination_Integration_TopicTemplate(t *testing.T) {
+	is := is.New(t)
+	ctx := context.Background()
+
+	topicPrefix := test.SetupTopicName(t, is)
+	topicA := topicPrefix + "-a"
+	topicB := topicPrefix + "-b"
+	test.DeletePulsarTopic(is, topicA)
+	test.DeletePulsarTopic(is, topicB)
+
+	con := NewDestination()
+	err := con.Configure(ctx, map[string]string{
+		DestinationConfigUrl:   test.PulsarURL,
+		DestinationConfigTopic: topicPrefix + `-{{index .Metadata "tenant"}}`,
+	})
+	is.NoErr(err)
+
+	err = con.Open(ctx)
+	is.NoErr(err)
+	defer func() {
+		err := con.Teardown(ctx)
+		is.NoErr(err)
+	}()
+
+	var records []opencdc.Record
+	for i, tenant := range []string{"a", "b", "a"} {
+		records = append(records, sdk.Util.Source.NewRecordCreate(
+			[]byte(uuid.NewString()),
+			opencdc.Metadata{"tenant": tenant},
+			opencdc.RawData(fmt.Sprintf("key-%d", i)),
+			opencdc.RawData(exampleMessage),
+		))
+	}
+
+	written, err := con.Write(ctx, records)
+	is.NoErr(err)
+	is.Equal(written, len(records))
+
+	msgsA := consumePulsarMsgs(is, topicA, 2)
+	is.Equal(msgsA[0].Key(), "key-0")
+	is.Equal(msgsA[1].Key(), "key-2")
+
+	msgsB := consumePulsarMsgs(is, topicB, 1)
+	is.Equal(msgsB[0].Key(), "key-1")
+}
+
+func TestDestination_Integration_Key(t *testing.T) {
+	is := is.New(t)
+	ctx := context.Background()
+
+	topic := test.SetupTopicName(t, is)
+
+	con := NewDestination()
+	err := con.Configure(ctx, map[string]string{
+		DestinationConfigUrl:   test.PulsarURL,
+		DestinationConfigTopic: topic,
+	})
+	is.NoErr(err)
+
+	err = con.Open(ctx)
+	is.NoErr(err)
+	defer func() {
+		err := con.Teardown(ctx)
+		is.NoErr(err)
+	}()
+
+	written, err := con.Write(ctx, []opencdc.Record{sdk.Util.Source.NewRecordCreate(
+		[]byte(uuid.NewString()),
+		opencdc.Metadata{metadataOrderingKey: "customer-42"},
+		opencdc.RawData("order-1"),
+		opencdc.RawData(exampleMessage),
+	)})
+	is.NoErr(err)
+	is.Equal(written, 1)
+
+	msgs := consumePulsarMsgs(is, topic, 1)
+	is.Equal(msgs[0].Key(), "order-1")
+	is.Equal(msgs[0].OrderingKey(), "customer-42")
+}
+
+func TestDestination_Write_WithoutKey(t *testing.T) {
+	is := is.New(t)
+
+	producer := &recordingProducer{}
+	underTest := &Destination{producer: producer}
+
+	written, err := underTest.Write(context.Background(), []opencdc.Record{
+		sdk.Util.Source.NewRecordCreate(nil, nil, nil, opencdc.RawData(exampleMessage)),
+	})
+	is.NoErr(err)
+	is.Equal(written, 1)
+	is.Equal(producer.sent[0].Key, "")
+}
+
+func TestDestination_Configure_InvalidTopicTemplate(t *testing.T) {
+	is := is.New(t)
+	con := NewDestination()
+
+	err := con.Configure(context.Background(), map[string]string{
+		DestinationConfigUrl:   test.PulsarURL,
+		DestinationConfigTopic: "events-{{.Metadata}",
+	})
+	is.True(err != nil)
+}
+
+func TestDestination_Integration_ExclusiveWaitTimeout(t *testing.T) {
+	is := is.New(t)
+	ctx := context.Background()
+
+	topic := test.SetupTopicName(t, is)
+
+	first := NewDestination()
+	err := first.Configure(ctx, map[string]string{
+		DestinationConfigUrl:                test.PulsarURL,
+		DestinationConfigTopic:              topic,
+		DestinationConfigProducerAccessMode: ProducerAccessModeExclusive,
+	})
+	is.NoErr(err)
+	err = first.Open(ctx)
+	is.NoErr(err)
+	defer func() {
+		err := first.Teardown(ctx)
+		is.NoErr(err)
+	}()
+
+	second := NewDestination()
+	err = second.Configure(ctx, map[string]string{
+		DestinationConfigUrl:                  test.PulsarURL,
+		DestinationConfigTopic:                topic,
+		DestinationConfigProducerAccessMode:   ProducerAccessModeWaitForExclusive,
+		DestinationConfigExclusiveWaitTimeout: "500ms",
+	})
+	is.NoErr(err)
+
+	start := time.Now()
+	err = second.Open(ctx)
+	is.True(errors.Is(err, errExclusiveWaitTimeout))
+	is.True(time.Since(start) >= 500*time.Millisecond)
+
+	err = second.Teardown(ctx)
+	is.NoErr(err)
+}
+
+func TestDestination_Configure_NegativeExclusiveWaitTimeout(t *testing.T) {
+	is := is.New(t)
+	con := NewDestination()
+
+	err := con.Configure(context.Background(), map[string]string{
+		DestinationConfigUrl:                  test.PulsarURL,
+		DestinationConfigTopic:                "topic",
+		DestinationConfigExclusiveWaitTimeout: "-1s",
+	})
+	is.True(err != nil)
+	is.True(strings.Contains(err.Error(), fmt.Sprintf("%q must not be negative", DestinationConfigExclusiveWaitTimeout)))
+
+	// waiting indefinitely is allowed
+	err = con.Configure(context.Background(), map[string]string{
+		DestinationConfigUrl:                  test.PulsarURL,
+		DestinationConfigTopic:                "topic",
+		DestinationConfigExclusiveWaitTimeout: "0s",
+	})
+	is.NoErr(err)
+}
+
+func TestDestination_Integration_LookupTimeout(t *testing.T) {
+	is := is.New(t)
+	ctx := context.Background()
+
+	topic := test.SetupTopicName(t, is)
+
+	con := NewDestination()
+	err := con.Configure(ctx, map[string]string{
+		DestinationConfigUrl:              test.PulsarURL,
+		DestinationConfigTopic:            topic,
+		DestinationConfigOperationTimeout: "10s",
+		DestinationConfigLookupTimeout:    "1ns",
+	})
+	is.NoErr(err)
+
+	err = con.Open(ctx)
+	is.True(errors.Is(err, errOperationTimeout))
+
+	err = con.Teardown(ctx)
+	is.NoErr(err)
+}
+
+// deletedTopicProducer fails all messages because its topic was deleted.
+type deletedTopicProducer struct {
+	pulsar.Producer
+}
+
+func (deletedTopicProducer) Send(context.Context, *pulsar.ProducerMessage) (pulsar.MessageID, error) {
+	return nil, pulsar.ErrTopicNotfound
+}
+
+func TestDestination_Write_TopicNotFoundError(t *testing.T) {
+	is := is.New(t)
+
+	con := &Destination{
+		producer: deletedTopicProducer{},
+		config: DestinationConfig{
+			TopicNotFoundPolicy: TopicNotFoundPolicyError,
+		},
+	}
+
+	rec := sdk.Util.Source.NewRecordCreate(
+		[]byte(uuid.NewString()),
+		opencdc.Metadata{},
+		opencdc.RawData("test-key"),
+		opencdc.RawData(exampleMessage),
+	)
+
+	written, err := con.Write(context.Background(), []opencdc.Record{rec})
+	is.Equal(written, 0)
+	is.True(errors.Is(err, errTopicNotFound))
+}
+
+func TestIsTopicNotFound(t *testing.T) {
+	testCases := []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{name: "topic not found", err: pulsar.ErrTopicNotfound, want: true},
+		{name: "wrapped", err: fmt.Errorf("failed to send: %w", errors.Join(pulsar.ErrTopicNotfound, errors.New("disconnected"))), want: true},
+		{name: "producer closed", err: pulsar.ErrProducerClosed},
+		{name: "text only", err: errors.New("TopicNotFound: topic does not exist")},
+		{name: "nil", err: nil},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			is := is.New(t)
+			is.Equal(isTopicNotFound(tc.err), tc.want)
+		})
+	}
+}
+
+func TestDestination_Integration_TopicNotFoundRecreatePartitioned(t *testing.T) {
+	is := is.New(t)
+	ctx := context.Background()
+
+	topic := test.SetupTopicName(t, is)
+	admin, err := pulsaradmin.NewClient(&pulsaradmin.Config{WebServiceURL: test.PulsarAdminURL})
+	is.NoErr(err)
+	topicName, err := utils.GetTopicName(topic)
+	is.NoErr(err)
+	is.NoErr(admin.Topics().Create(*topicName, 3))
+
+	con := NewDestination()
+	err = con.Configure(ctx, map[string]string{
+		DestinationConfigUrl:                 test.PulsarURL,
+		DestinationConfigTopic:               topic,
+		DestinationConfigAdminURL:            test.PulsarAdminURL,
+		DestinationConfigTopicNotFoundPolicy: TopicNotFoundPolicyRecreate,
+	})
+	is.NoErr(err)
+	is.NoErr(con.Open(ctx))
+	defer func() {
+		err := con.Teardown(ctx)
+		is.NoErr(err)
+	}()
+
+	is.NoErr(admin.Topics().Delete(*topicName, true, true))
+
+	written, err := con.Write(ctx, []opencdc.Record{sdk.Util.Source.NewRecordCreate(
+		[]byte(uuid.NewString()),
+		opencdc.Metadata{},
+		opencdc.RawData("key-1"),
+		opencdc.RawData(exampleMessage),
+	)})
+	is.NoErr(err)
+	is.Equal(written, 1)
+
+	// the topic is recreated with its partitions
+	metadata, err := admin.Topics().GetMetadata(*topicName)
+	is.NoErr(err)
+	is.Equal(metadata.Partitions, 3)
+}
+
+func TestDestination_Integration_TopicNotFoundRecreate(t *testing.T) {
+	is := is.New(t)
+	ctx := context.Background()
+
+	topic := test.SetupTopicName(t, is)
+
+	con := NewDestination()
+	err := con.Configure(ctx, map[string]string{
+		DestinationConfigUrl:                 test.PulsarURL,
+		DestinationConfigTopic:               topic,
+		DestinationConfigAdminURL:            test.PulsarAdminURL,
+		DestinationConfigTopicNotFoundPolicy: TopicNotFoundPolicyRecreate,
+	})
+	is.NoErr(err)
+
+	err = con.Open(ctx)
+	is.NoErr(err)
+	defer func() {
+		err := con.Teardown(ctx)
+		is.NoErr(err)
+	}()
+
+	newRecord := func(key string) opencdc.Record {
+		return sdk.Util.Source.NewRecordCreate(
+			[]byte(uuid.NewString()),
+			opencdc.Metadata{},
+			opencdc.RawData(key),
+			opencdc.RawData(exampleMessage),
+		)
+	}
+
+	written, err := con.Write(ctx, []opencdc.Record{newRecord("key-1")})
+	is.NoErr(err)
+	is.Equal(written, 1)
+
+	// delete the topic mid-stream, this disconnects the producer
+	admin, err := pulsaradmin.NewClient(&pulsaradmin.Config{WebServiceURL: test.PulsarAdminURL})
+	is.NoErr(err)
+	topicName, err := utils.GetTopicName(topic)
+	is.NoErr(err)
+	err = admin.Topics().Delete(*topicName, true, true)
+	is.NoErr(err)
+
+	written, err = con.Write(ctx, []opencdc.Record{newRecord("key-2")})
+	is.NoErr(err)
+	is.Equal(written, 1)
+
+	// only the message produced after recreating the topic is left
+	msgs := consumePulsarMsgs(is, topic, 1)
+	is.Equal(msgs[0].Key(), "key-2")
+}
+
+func TestDestination_Configure_TopicNotFoundRecreateRequiresAdminURL(t *testing.T) {
+	is := is.New(t)
+	con := NewDestination()
+
+	err := con.Configure(context.Background(), map[string]string{
+		DestinationConfigUrl:                 test.PulsarURL,
+		DestinationConfigTopic:               "topic",
+		DestinationConfigTopicNotFoundPolicy: TopicNotFoundPolicyRecreate,
+	})
+	is.True(err != nil)
+}
+
+func TestDestination_Write_AuditMetadata(t *testing.T) {
+	is := is.New(t)
+
+	producer := &recordingProducer{}
+	con := &Destination{
+		producer: producer,
+		config: DestinationConfig{
+			AuditMetadata: true,
+		},
+		auditInstanceID: "pipeline:destination",
+	}
+
+	rec := sdk.Util.Source.NewRecordCreate(
+		[]byte(uuid.NewString()),
+		opencdc.Metadata{},
+		opencdc.RawData("test-key"),
+		opencdc.RawData(exampleMessage),
+	)
+
+	before := time.Now()
+	written, err := con.Write(context.Background(), []opencdc.Record{rec})
+	is.NoErr(err)
+	is.Equal(written, 1)
+
+	props := producer.sent[0].Properties
+	is.Equal(props[auditPropertyConnector], "pulsar")
+	is.Equal(props[auditPropertyInstanceID], "pipeline:destination")
+
+	producedAt, err := time.Parse(auditProducedAtLayout, props[auditPropertyProducedAt])
+	is.NoErr(err)
+	is.True(!producedAt.Before(before))
+}
+
+func TestDestination_Write_IdempotencyKey(t *testing.T) {
+	is := is.New(t)
+
+	producer := &recordingProducer{}
+	con := &Destination{
+		producer: producer,
+		config: DestinationConfig{
+			IdempotencyKeyField: ".Metadata.idempotencyKey",
+		},
+		idempotency: newIdempotencyWindow(time.Minute),
+	}
+
+	var records []opencdc.Record
+	for i, idempotencyKey := range []string{"a", "b", "a", "", ""} {
+		records = append(records, sdk.Util.Source.NewRecordCreate(
+			[]byte(uuid.NewString()),
+			opencdc.Metadata{"idempotencyKey": idempotencyKey},
+			opencdc.RawData(fmt.Sprintf("key-%d", i)),
+			opencdc.RawData(exampleMessage),
+		))
+	}
+
+	written, err := con.Write(context.Background(), records)
+	is.NoErr(err)
+	is.Equal(written, len(records))
+
+	// a duplicate in a later batch is skipped as well
+	written, err = con.Write(context.Background(), records[1:2])
+	is.NoErr(err)
+	is.Equal(written, 1)
+
+	var sentKeys []string
+	for _, msg := range producer.sent {
+		sentKeys = append(sentKeys, msg.Key)
+	}
+	is.Equal(sentKeys, []string{"key-0", "key-1", "key-3", "key-4"})
+}
+
+// backpressureProducer rejects messages while its queue is full, like the
+// client does before SendAsync returns.
+type backpressureProducer struct {
+	asyncProducer
+
+	fullFor  int
+	attempts int
+}
+
+func (p *backpressureProducer) SendAsync(ctx context.Context, msg *pulsar.ProducerMessage, callback func(pulsar.MessageID, *pulsar.ProducerMessage, error)) {
+	p.attempts++
+	if p.attempts <= p.fullFor {
+		callback(nil, msg, pulsar.ErrSendQueueIsFull)
+		return
+	}
+	p.asyncProducer.SendAsync(ctx, msg, callback)
+}
+
+func TestDestination_Write_AdaptiveThrottling(t *testing.T) {
+	is := is.New(t)
+
+	cfg := DestinationConfig{
+		AdaptiveThrottling:         true,
+		AdaptiveThrottlingMaxDelay: time.Second,
+		WriteBufferMaxRecords:      10,
+	}
+	producer := &backpressureProducer{fullFor: 3}
+	con := &Destination{producer: producer, config: cfg, buffer: newWriteBuffer(cfg)}
+
+	records := newBufferTestRecords(3)
+
+	// backpressure slows down the first record instead of failing it
+	written, err := con.Write(context.Background(), records[:1])
+	is.NoErr(err)
+	is.Equal(written, 1)
+	is.Equal(producer.attempts, 4)
+	is.Equal(con.buffer.throttle.delay, 2*time.Millisecond)
+
+	// the following records speed up as the pressure eased
+	written, err = con.Write(context.Background(), records[1:])
+	is.NoErr(err)
+	is.Equal(written, 2)
+	is.Equal(con.buffer.throttle.delay, time.Duration(0))
+	is.Equal(producer.flushes, []int{1, 2})
+}
+
+func TestDestination_Configure_AdaptiveThrottling(t *testing.T) {
+	testCases := []struct {
+		name    string
+		cfg     map[string]string
+		wantErr bool
+	}{{
+		name: "with write buffer",
+		cfg: map[string]string{
+			DestinationConfigAdaptiveThrottling:    "true",
+			DestinationConfigWriteBufferMaxRecords: "100",
+		},
+	}, {
+		name:    "without write buffer",
+		cfg:     map[string]string{DestinationConfigAdaptiveThrottling: "true"},
+		wantErr: true,
+	}}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			is := is.New(t)
+
+			cfgMap := map[string]string{
+				DestinationConfigUrl:   test.PulsarURL,
+				DestinationConfigTopic: "test-topic",
+			}
+			for key, val := range tc.cfg {
+				cfgMap[key] = val
+			}
+
+			err := NewDestination().Configure(context.Background(), cfgMap)
+			is.Equal(err != nil, tc.wantErr)
+		})
+	}
+}
+
+func TestDestination_Write_LargeMessageTopic(t *testing.T) {
+	is := is.New(t)
+
+	producer := &recordingProducer{}
+	largeProducer := &recordingProducer{}
+	con := &Destination{
+		producer:      producer,
+		largeProducer: largeProducer,
+		config: DestinationConfig{
+			LargeMessageTopic:     "large",
+			LargeMessageThreshold: 1024,
+		},
+	}
+
+	var records []opencdc.Record
+	for i, payload := range []string{"small", strings.Repeat("x", 2048), "small"} {
+		records = append(records, sdk.Util.Source.NewRecordCreate(
+			[]byte(uuid.NewString()),
+			opencdc.Metadata{},
+			opencdc.RawData(fmt.Sprintf("key-%d", i)),
+			opencdc.RawData(payload),
+		))
+	}
+
+	written, err := con.Write(context.Background(), records)
+	is.NoErr(err)
+	is.Equal(written, len(records))
+
+	is.Equal(len(producer.sent), 2)
+	is.Equal(producer.sent[0].Key, "key-0")
+	is.Equal(producer.sent[1].Key, "key-2")
+	is.Equal(len(largeProducer.sent), 1)
+	is.Equal(largeProducer.sent[0].Key, "key-1")
+}
+
+func TestDestination_Configure_LargeMessageTopicRequiresThreshold(t *testing.T) {
+	is := is.New(t)
+	con := NewDestination()
+
+	err := con.Configure(context.Background(), map[string]string{
+		DestinationConfigUrl:               test.PulsarURL,
+		DestinationConfigTopic:             "topic",
+		DestinationConfigLargeMessageTopic: "large",
+	})
+	is.True(err != nil)
+}
+
+// failingProducer fails the first messages with err.
+type failingProducer struct {
+	pulsar.Producer
+
+	err      error
+	failures int
+	attempts int
+}
+
+func (p *failingProducer) Topic() string { return "orders" }
+
+func (p *failingProducer) Send(context.Context, *pulsar.ProducerMessage) (pulsar.MessageID, error) {
+	p.attempts++
+	if p.attempts <= p.failures {
+		return nil, p.err
+	}
+	return pulsar.EarliestMessageID(), nil
+}
+
+func TestDestination_Write_ProduceRetryableErrors(t *testing.T) {
+	testCases := []struct {
+		name         string
+		err          error
+		wantAttempts int
+		wantErr      bool
+	}{
+		{name: "timeout", err: pulsar.ErrSendTimeout, wantAttempts: 3, wantErr: false},
+		{name: "producer busy", err: errors.New("server error: ProducerBusy: producer with name 'foo' is already connected"), wantAttempts: 3, wantErr: false},
+		{name: "topic terminated", err: pulsar.ErrTopicTerminated, wantAttempts: 1, wantErr: true},
+		{name: "schema incompatible", err: errors.New("server error: IncompatibleSchema: schema is incompatible"), wantAttempts: 1, wantErr: true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			is := is.New(t)
+
+			producer := &failingProducer{err: tc.err, failures: 2}
+			con := &Destination{
+				producer: producer,
+				config: DestinationConfig{
+					ProduceRetryableErrors: []string{ProduceErrorTimeout, ProduceErrorProducerBusy},
+					ProduceMaxRetries:      2,
+					ProduceRetryBackoff:    time.Millisecond,
+				},
+			}
+
+			rec := sdk.Util.Source.NewRecordCreate(
+				[]byte(uuid.NewString()),
+				opencdc.Metadata{},
+				opencdc.RawData("test-key"),
+				opencdc.RawData(exampleMessage),
+			)
+
+			_, err := con.Write(context.Background(), []opencdc.Record{rec})
+			is.Equal(err != nil, tc.wantErr)
+			is.Equal(producer.attempts, tc.wantAttempts)
+		})
+	}
+}
+
+func TestDestination_Write_SendTimeout(t *testing.T) {
+	is := is.New(t)
+
+	producer := &failingProducer{err: pulsar.ErrSendTimeout, failures: 1}
+	con := &Destination{
+		producer: producer,
+		config:   DestinationConfig{SendTimeout: time.Second},
+	}
+	rec := sdk.Util.Source.NewRecordCreate(
+		[]byte(uuid.NewString()),
+		opencdc.Metadata{},
+		opencdc.RawData("test-key"),
+		opencdc.RawData(exampleMessage),
+	)
+
+	_, err := con.Write(context.Background(), []opencdc.Record{rec})
+	is.True(errors.Is(err, pulsar.ErrSendTimeout))
+	is.True(strings.Contains(err.Error(), `key "test-key" to topic "orders" timed out after 1s`))
+
+	// the producer can still be used after the timeout
+	written, err := con.Write(context.Background(), []opencdc.Record{rec})
+	is.NoErr(err)
+	is.Equal(written, 1)
+}
+
+func TestDestination_Configure_NegativeSendTimeout(t *testing.T) {
+	is := is.New(t)
+	con := NewDestination()
+
+	err := con.Configure(context.Background(), map[string]string{
+		DestinationConfigUrl:         test.PulsarURL,
+		DestinationConfigTopic:       "topic",
+		DestinationConfigSendTimeout: "-1s",
+	})
+	is.True(err != nil)
+}
+
+func TestDestination_Configure_UnknownProduceRetryableError(t *testing.T) {
+	is := is.New(t)
+	con := NewDestination()
+
+	err := con.Configure(context.Background(), map[string]string{
+		DestinationConfigUrl:                    test.PulsarURL,
+		DestinationConfigTopic:                  "topic",
+		DestinationConfigProduceRetryableErrors: "timeout,unknown",
+	})
+	is.True(err != nil)
+}
@@ -0,0 +1,175 @@
+// Copyright © 2024 Meroxa, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pulsar
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/apache/pulsar-client-go/pulsar"
+	"github.com/apache/pulsar-client-go/pulsaradmin/pkg/utils"
+)
+
+// Supported values of SourceConfig.DLQFailurePolicy.
+const (
+	// DLQFailurePolicyBlock keeps retrying to route messages to the dead
+	// letter topic, this is the behavior of the Pulsar client.
+	DLQFailurePolicyBlock = "block"
+	// DLQFailurePolicyDrop acknowledges and logs messages that exceeded the
+	// maximum number of deliveries if the dead letter topic is unavailable.
+	DLQFailurePolicyDrop = "drop"
+	// DLQFailurePolicyFail fails to open the source if the dead letter topic
+	// is unavailable.
+	DLQFailurePolicyFail = "fail"
+)
+
+// Properties added to messages routed to the dead letter topic when
+// DLQDiagnosticProperties is enabled.
+const (
+	dlqPropertyOriginalTopic   = "DLQ_ORIGINAL_TOPIC"
+	dlqPropertyFailureReason   = "DLQ_FAILURE_REASON"
+	dlqPropertyRedeliveryCount = "DLQ_REDELIVERY_COUNT"
+
+	dlqFailureReasonMaxDeliveries = "max deliveries exceeded"
+)
+
+// newDLQPolicy returns the dead letter policy of the consumer, or nil if
+// dead letter routing is disabled.
+func newDLQPolicy(cfg SourceConfig) *pulsar.DLQPolicy {
+	if cfg.DLQMaxDeliveries == 0 && !cfg.EnableRetry {
+		return nil
+	}
+
+	// the failure topics were validated when configuring the source
+	failureTopics, _ := parseDLQFailureTopics(cfg.DLQFailureTopics)
+	deadLetterTopic, ok := failureTopics[FailureTypeProcessing]
+	if !ok {
+		deadLetterTopic = cfg.dlqTopic()
+	}
+
+	maxDeliveries := uint32(cfg.DLQMaxDeliveries)
+	if maxDeliveries == 0 {
+		// the default of the client for retried messages
+		maxDeliveries = pulsar.MaxReconsumeTimes
+	}
+
+	policy := &pulsar.DLQPolicy{
+		MaxDeliveries:   maxDeliveries,
+		DeadLetterTopic: deadLetterTopic,
+		ProducerOptions: pulsar.ProducerOptions{
+			// the client copies the ordering key of the original message,
+			// batching by key keeps messages with different keys apart, so key
+			// shared consumers of the dead letter topic receive them in order
+			BatcherBuilderType: pulsar.KeyBasedBatchBuilder,
+			BackOffPolicyFunc:  newReconnectBackoff(cfg.MaxBackoff),
+		},
+	}
+	if cfg.EnableRetry {
+		policy.RetryLetterTopic = cfg.retryLetterTopic()
+	}
+	var interceptors pulsar.ProducerInterceptors
+	if cfg.DLQDiagnosticProperties {
+		interceptors = append(interceptors, &dlqDiagnosticsInterceptor{maxDeliveries: policy.MaxDeliveries})
+	}
+	if cfg.DLQSchemaDefinition != "" {
+		// the schema definition was validated when configuring the source
+		envelope, _ := newDLQEnvelope(cfg.DLQSchemaDefinition)
+		interceptors = append(interceptors, &dlqEnvelopeInterceptor{envelope: envelope, maxDeliveries: policy.MaxDeliveries})
+	}
+	if cfg.EnableRetry {
+		// the client creates the retry letter producer with the same options
+		// as the dead letter producer, retried messages have to stay unchanged
+		for i, interceptor := range interceptors {
+			interceptors[i] = &deadLetterOnlyInterceptor{ProducerInterceptor: interceptor, topic: deadLetterTopic}
+		}
+	}
+	policy.ProducerOptions.Interceptors = interceptors
+
+	return policy
+}
+
+// deadLetterOnlyInterceptor applies the wrapped interceptor only to messages
+// produced to the dead letter topic.
+type deadLetterOnlyInterceptor struct {
+	pulsar.ProducerInterceptor
+	topic string
+}
+
+func (i *deadLetterOnlyInterceptor) BeforeSend(producer pulsar.Producer, msg *pulsar.ProducerMessage) {
+	if isSameTopic(producer.Topic(), i.topic) {
+		i.ProducerInterceptor.BeforeSend(producer, msg)
+	}
+}
+
+func (i *deadLetterOnlyInterceptor) OnSendAcknowledgement(producer pulsar.Producer, msg *pulsar.ProducerMessage, msgID pulsar.MessageID) {
+	if isSameTopic(producer.Topic(), i.topic) {
+		i.ProducerInterceptor.OnSendAcknowledgement(producer, msg, msgID)
+	}
+}
+
+// isSameTopic returns true if both names refer to the same topic, ignoring
+// the partition and whether the names are fully qualified.
+func isSameTopic(a, b string) bool {
+	return baseTopicName(a) == baseTopicName(b)
+}
+
+// baseTopicName returns the fully qualified name of the topic without the
+// partition suffix.
+func baseTopicName(topic string) string {
+	name, err := utils.GetTopicName(topic)
+	if err != nil {
+		return topic
+	}
+	base := name.String()
+	if name.GetPartitionIndex() >= 0 {
+		base, _, _ = strings.Cut(base, utils.PARTITIONEDTOPICSUFFIX)
+	}
+	return base
+}
+
+// checkDLQTopic verifies that messages can be produced to the dead letter
+// topic.
+func checkDLQTopic(client pulsar.Client, topic string) error {
+	producer, err := client.CreateProducer(pulsar.ProducerOptions{Topic: topic})
+	if err != nil {
+		return fmt.Errorf("failed to create producer for dead letter topic %q: %w", topic, err)
+	}
+	producer.Close()
+	return nil
+}
+
+// dlqDiagnosticsInterceptor is attached to the dead letter producer and adds
+// properties that let consumers of the dead letter topic correlate the message
+// with its origin. The key, ordering key and properties of the original
+// message are preserved by the Pulsar client.
+type dlqDiagnosticsInterceptor struct {
+	maxDeliveries uint32
+}
+
+func (i *dlqDiagnosticsInterceptor) BeforeSend(_ pulsar.Producer, msg *pulsar.ProducerMessage) {
+	if msg.Properties == nil {
+		msg.Properties = make(map[string]string)
+	}
+
+	msg.Properties[dlqPropertyOriginalTopic] = msg.Properties[pulsar.SysPropertyRealTopic]
+	msg.Properties[dlqPropertyFailureReason] = dlqFailureReasonMaxDeliveries
+	// messages are routed to the dead letter topic once they were
+	// redelivered the maximum number of times
+	msg.Properties[dlqPropertyRedeliveryCount] = strconv.FormatUint(uint64(i.maxDeliveries), 10)
+}
+
+func (i *dlqDiagnosticsInterceptor) OnSendAcknowledgement(pulsar.Producer, *pulsar.ProducerMessage, pulsar.MessageID) {
+}
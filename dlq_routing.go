@@ -0,0 +1,126 @@
+// Copyright © 2024 Meroxa, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pulsar
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/apache/pulsar-client-go/pulsar"
+)
+
+// Failure types used as keys of SourceConfig.DLQFailureTopics.
+const (
+	// FailureTypeDeserialization is the failure of messages whose payload
+	// can't be decoded, e.g. it is not valid JSON.
+	FailureTypeDeserialization = "deserialization"
+	// FailureTypeProcessing is the failure of messages that exceeded the
+	// maximum number of deliveries.
+	FailureTypeProcessing = "processing"
+	// FailureTypeSchema is the failure of messages that don't match the
+	// expected schema.
+	FailureTypeSchema = "schema"
+)
+
+// dlqPropertyFailureType is added to messages routed by failure type when
+// DLQDiagnosticProperties is enabled.
+const dlqPropertyFailureType = "DLQ_FAILURE_TYPE"
+
+// parseDLQFailureTopics parses entries in the format "type:topic" into a map
+// of failure types to dead letter topics.
+func parseDLQFailureTopics(entries []string) (map[string]string, error) {
+	topics := make(map[string]string, len(entries))
+	for _, entry := range entries {
+		failureType, topic, ok := strings.Cut(entry, ":")
+		failureType, topic = strings.TrimSpace(failureType), strings.TrimSpace(topic)
+		if !ok || topic == "" {
+			return nil, fmt.Errorf("entry %q must be in the format \"type:topic\"", entry)
+		}
+		switch failureType {
+		case FailureTypeDeserialization, FailureTypeProcessing, FailureTypeSchema:
+		default:
+			return nil, fmt.Errorf("unknown failure type %q", failureType)
+		}
+		if _, ok := topics[failureType]; ok {
+			return nil, fmt.Errorf("failure type %q is mapped more than once", failureType)
+		}
+		topics[failureType] = topic
+	}
+	return topics, nil
+}
+
+// openFailureProducers creates a producer for each dead letter topic that
+// messages are routed to by the source. Messages that exceeded the maximum
+// number of deliveries are routed by the consumer, so no producer is created
+// for processing failures. The producers reconnect with a backoff of up to
+// maxBackoff.
+func openFailureProducers(client pulsar.Client, topics map[string]string, maxBackoff time.Duration) (map[string]pulsar.Producer, error) {
+	producers := make(map[string]pulsar.Producer)
+	for failureType, topic := range topics {
+		if failureType == FailureTypeProcessing {
+			continue
+		}
+		producer, err := client.CreateProducer(pulsar.ProducerOptions{
+			Topic:              topic,
+			BatcherBuilderType: pulsar.KeyBasedBatchBuilder,
+			BackOffPolicyFunc:  newReconnectBackoff(maxBackoff),
+		})
+		if err != nil {
+			closeProducers(producers)
+			return nil, fmt.Errorf("failed to create producer for dead letter topic %q: %w", topic, err)
+		}
+		producers[failureType] = producer
+	}
+	return producers, nil
+}
+
+func closeProducers(producers map[string]pulsar.Producer) {
+	for _, producer := range producers {
+		producer.Close()
+	}
+}
+
+// routeFailure produces the message to the dead letter topic of the failure
+// type and acknowledges it.
+func (s *Source) routeFailure(ctx context.Context, msg pulsar.Message, failureType, reason string) error {
+	producer := s.failureProducers[failureType]
+
+	properties := make(map[string]string, len(msg.Properties())+3)
+	for key, val := range msg.Properties() {
+		properties[key] = val
+	}
+	if s.config.DLQDiagnosticProperties {
+		properties[dlqPropertyOriginalTopic] = msg.Topic()
+		properties[dlqPropertyFailureReason] = reason
+		properties[dlqPropertyFailureType] = failureType
+	}
+
+	_, err := producer.Send(ctx, &pulsar.ProducerMessage{
+		Payload:     msg.Payload(),
+		Key:         msg.Key(),
+		OrderingKey: msg.OrderingKey(),
+		Properties:  properties,
+		EventTime:   msg.EventTime(),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to route message to dead letter topic %q: %w", producer.Topic(), err)
+	}
+	if err := s.consumer.AckID(msg.ID()); err != nil {
+		return fmt.Errorf("failed to ack routed message: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,211 @@
+// Copyright © 2024 Meroxa, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pulsar
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/apache/pulsar-client-go/pulsar"
+	"github.com/matryer/is"
+)
+
+func TestParseDLQFailureTopics(t *testing.T) {
+	testCases := []struct {
+		name    string
+		entries []string
+		want    map[string]string
+		wantErr bool
+	}{{
+		name:    "empty",
+		entries: nil,
+		want:    map[string]string{},
+	}, {
+		name:    "all failure types",
+		entries: []string{"deserialization:bad-json", " schema : bad-schema", "processing:failed"},
+		want: map[string]string{
+			FailureTypeDeserialization: "bad-json",
+			FailureTypeSchema:          "bad-schema",
+			FailureTypeProcessing:      "failed",
+		},
+	}, {
+		name:    "missing topic",
+		entries: []string{"schema:"},
+		wantErr: true,
+	}, {
+		name:    "missing separator",
+		entries: []string{"bad-schema"},
+		wantErr: true,
+	}, {
+		name:    "unknown failure type",
+		entries: []string{"timeout:timed-out"},
+		wantErr: true,
+	}, {
+		name:    "duplicate failure type",
+		entries: []string{"schema:bad-schema", "schema:other"},
+		wantErr: true,
+	}}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			is := is.New(t)
+
+			got, err := parseDLQFailureTopics(tc.entries)
+			if tc.wantErr {
+				is.True(err != nil)
+				return
+			}
+			is.NoErr(err)
+			is.Equal(got, tc.want)
+		})
+	}
+}
+
+func TestSource_Configure_DLQFailureTopics(t *testing.T) {
+	testCases := []struct {
+		name    string
+		cfg     map[string]string
+		wantErr bool
+	}{{
+		name: "processing topic replaces dead letter topic",
+		cfg: map[string]string{
+			SourceConfigDlqFailureTopics: "processing:failed",
+			SourceConfigDlqMaxDeliveries: "3",
+		},
+	}, {
+		name: "processing topic and dead letter topic",
+		cfg: map[string]string{
+			SourceConfigDlqFailureTopics: "processing:failed",
+			SourceConfigDlqMaxDeliveries: "3",
+			SourceConfigDlqTopic:         "test-topic-DLQ",
+		},
+		wantErr: true,
+	}, {
+		name: "processing topic without max deliveries",
+		cfg: map[string]string{
+			SourceConfigDlqFailureTopics: "processing:failed",
+		},
+		wantErr: true,
+	}, {
+		name: "invalid mapping",
+		cfg: map[string]string{
+			SourceConfigDlqFailureTopics: "unknown:failed",
+		},
+		wantErr: true,
+	}}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			is := is.New(t)
+
+			cfgMap := newSourceCfg("topic")
+			for key, val := range tc.cfg {
+				cfgMap[key] = val
+			}
+
+			err := (&Source{}).Configure(context.Background(), cfgMap)
+			is.Equal(err != nil, tc.wantErr)
+		})
+	}
+}
+
+func TestNewDLQPolicy_ProcessingFailureTopic(t *testing.T) {
+	is := is.New(t)
+
+	policy := newDLQPolicy(SourceConfig{
+		DLQFailureTopics: []string{"schema:bad-schema", "processing:failed"},
+		DLQMaxDeliveries: 3,
+	})
+	is.Equal(policy.DeadLetterTopic, "failed")
+}
+
+func TestSource_Read_RoutesFailuresByType(t *testing.T) {
+	is := is.New(t)
+
+	notJSON := payloadMessage{readableMessage{fakeMessage{topic: "test-topic", id: pulsar.NewMessageID(1, 1, 0, 0)}}, []byte(`not json`)}
+	invalid := payloadMessage{readableMessage{fakeMessage{topic: "test-topic", id: pulsar.NewMessageID(1, 2, 0, 0)}}, []byte(`{"id": "one"}`)}
+	valid := payloadMessage{readableMessage{fakeMessage{topic: "test-topic", id: pulsar.NewMessageID(1, 3, 0, 0)}}, []byte(`{"id": 3}`)}
+
+	validator, err := newPayloadValidator(testJSONSchema)
+	is.NoErr(err)
+
+	deserialization := &recordingProducer{}
+	schema := &recordingProducer{}
+	consumer := &rejectRecordingConsumer{queueConsumer: queueConsumer{messages: []pulsar.Message{notJSON, invalid, valid}}}
+	underTest := &Source{
+		consumer: consumer,
+		config:   SourceConfig{DLQDiagnosticProperties: true},
+		payloads: validator,
+		failureProducers: map[string]pulsar.Producer{
+			FailureTypeDeserialization: deserialization,
+			FailureTypeSchema:          schema,
+		},
+	}
+
+	rec, err := underTest.Read(context.Background())
+	is.NoErr(err)
+	is.Equal(rec.Payload.After.Bytes(), valid.payload)
+
+	is.Equal(len(deserialization.sent), 1)
+	is.Equal(deserialization.sent[0].Payload, notJSON.payload)
+	is.Equal(deserialization.sent[0].Properties[dlqPropertyFailureType], FailureTypeDeserialization)
+	is.Equal(deserialization.sent[0].Properties[dlqPropertyOriginalTopic], "test-topic")
+
+	is.Equal(len(schema.sent), 1)
+	is.Equal(schema.sent[0].Payload, invalid.payload)
+	is.Equal(schema.sent[0].Properties[dlqPropertyFailureType], FailureTypeSchema)
+
+	// routed messages are acknowledged instead of being redelivered
+	is.Equal(len(consumer.nacked), 0)
+	is.Equal(len(consumer.acked), 2)
+	is.Equal(consumer.acked[0].String(), notJSON.ID().String())
+	is.Equal(consumer.acked[1].String(), invalid.ID().String())
+}
+
+type orderedMessage struct {
+	payloadMessage
+	orderingKey string
+}
+
+func (m orderedMessage) OrderingKey() string { return m.orderingKey }
+
+func TestSource_Read_RoutedFailurePreservesOrderingKey(t *testing.T) {
+	is := is.New(t)
+
+	msg := orderedMessage{
+		payloadMessage: payloadMessage{readableMessage{fakeMessage{topic: "test-topic", id: pulsar.NewMessageID(1, 1, 0, 0)}}, []byte(`not json`)},
+		orderingKey:    "customer-1",
+	}
+
+	validator, err := newPayloadValidator(testJSONSchema)
+	is.NoErr(err)
+
+	producer := &recordingProducer{}
+	underTest := &Source{
+		consumer:         &rejectRecordingConsumer{queueConsumer: queueConsumer{messages: []pulsar.Message{msg}}},
+		payloads:         validator,
+		failureProducers: map[string]pulsar.Producer{FailureTypeDeserialization: producer},
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	_, err = underTest.Read(ctx)
+	is.True(errors.Is(err, context.DeadlineExceeded))
+
+	is.Equal(len(producer.sent), 1)
+	is.Equal(producer.sent[0].OrderingKey, "customer-1")
+}
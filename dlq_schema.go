@@ -0,0 +1,137 @@
+// Copyright © 2024 Meroxa, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pulsar
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/apache/pulsar-client-go/pulsar"
+	"github.com/hamba/avro/v2"
+)
+
+// Fields of the dead letter envelope that are populated by the connector.
+// The envelope schema must contain dlqEnvelopeFieldPayload, the other fields
+// are optional.
+const (
+	dlqEnvelopeFieldPayload         = "payload"
+	dlqEnvelopeFieldKey             = "key"
+	dlqEnvelopeFieldOrderingKey     = "orderingKey"
+	dlqEnvelopeFieldOriginalTopic   = "originalTopic"
+	dlqEnvelopeFieldFailureReason   = "failureReason"
+	dlqEnvelopeFieldRedeliveryCount = "redeliveryCount"
+	dlqEnvelopeFieldProperties      = "properties"
+)
+
+// dlqEnvelope wraps messages routed to the dead letter topic in an Avro
+// record together with their failure metadata.
+type dlqEnvelope struct {
+	schema *pulsar.AvroSchema
+	fields []string
+}
+
+// newDLQEnvelope parses the Avro schema of the envelope and checks that a
+// message can be wrapped in it.
+func newDLQEnvelope(definition string) (*dlqEnvelope, error) {
+	schema, err := pulsar.NewAvroSchemaWithValidation(definition, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse schema: %w", err)
+	}
+
+	record, ok := schema.Codec.(*avro.RecordSchema)
+	if !ok {
+		return nil, errors.New("schema must be a record")
+	}
+
+	e := &dlqEnvelope{schema: schema}
+	for _, f := range record.Fields() {
+		e.fields = append(e.fields, f.Name())
+	}
+
+	if _, err := e.wrap(&pulsar.ProducerMessage{}, 1); err != nil {
+		return nil, fmt.Errorf("schema can't wrap messages, it must contain a %q field of type bytes and all other fields must be populated by the connector or have a default: %w", dlqEnvelopeFieldPayload, err)
+	}
+	return e, nil
+}
+
+// wrap encodes the message and its failure metadata as an envelope record.
+func (e *dlqEnvelope) wrap(msg *pulsar.ProducerMessage, maxDeliveries uint32) ([]byte, error) {
+	properties := msg.Properties
+	if properties == nil {
+		properties = map[string]string{}
+	}
+	payload := msg.Payload
+	if payload == nil {
+		payload = []byte{}
+	}
+
+	values := map[string]any{
+		dlqEnvelopeFieldPayload:         payload,
+		dlqEnvelopeFieldKey:             msg.Key,
+		dlqEnvelopeFieldOrderingKey:     msg.OrderingKey,
+		dlqEnvelopeFieldOriginalTopic:   properties[pulsar.SysPropertyRealTopic],
+		dlqEnvelopeFieldFailureReason:   dlqFailureReasonMaxDeliveries,
+		dlqEnvelopeFieldRedeliveryCount: int(maxDeliveries),
+		dlqEnvelopeFieldProperties:      properties,
+	}
+	record := make(map[string]any, len(e.fields))
+	hasPayload := false
+	for _, f := range e.fields {
+		if v, ok := values[f]; ok {
+			record[f] = v
+			hasPayload = hasPayload || f == dlqEnvelopeFieldPayload
+		}
+	}
+	if !hasPayload {
+		return nil, fmt.Errorf("schema has no %q field", dlqEnvelopeFieldPayload)
+	}
+
+	return e.schema.Encode(record)
+}
+
+// dlqEnvelopeInterceptor is attached to the dead letter producer and replaces
+// the payload of messages with the envelope record.
+type dlqEnvelopeInterceptor struct {
+	envelope      *dlqEnvelope
+	maxDeliveries uint32
+}
+
+func (i *dlqEnvelopeInterceptor) BeforeSend(_ pulsar.Producer, msg *pulsar.ProducerMessage) {
+	payload, err := i.envelope.wrap(msg, i.maxDeliveries)
+	if err != nil {
+		// the schema was checked when configuring the source, this can only
+		// fail for unexpected property values, keep the original payload
+		return
+	}
+	msg.Payload = payload
+}
+
+func (i *dlqEnvelopeInterceptor) OnSendAcknowledgement(pulsar.Producer, *pulsar.ProducerMessage, pulsar.MessageID) {
+}
+
+// registerDLQSchema registers the envelope schema on the dead letter topic.
+// The client creates the dead letter producer with the schema of the
+// consumer, so the schema is registered by a separate producer.
+func registerDLQSchema(client pulsar.Client, topic string, envelope *dlqEnvelope) error {
+	producer, err := client.CreateProducer(pulsar.ProducerOptions{
+		Topic:  topic,
+		Schema: envelope.schema,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to register schema on dead letter topic %q: %w", topic, err)
+	}
+	producer.Close()
+	return nil
+}
@@ -0,0 +1,120 @@
+// Copyright © 2024 Meroxa, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pulsar
+
+import (
+	"testing"
+
+	"github.com/apache/pulsar-client-go/pulsar"
+	"github.com/hamba/avro/v2"
+	"github.com/matryer/is"
+)
+
+const testDLQSchema = `{
+  "type": "record",
+  "name": "DeadLetter",
+  "fields": [
+    {"name": "payload", "type": "bytes"},
+    {"name": "originalTopic", "type": "string"},
+    {"name": "redeliveryCount", "type": "int"},
+    {"name": "note", "type": "string", "default": ""}
+  ]
+}`
+
+func TestDLQEnvelopeInterceptor(t *testing.T) {
+	is := is.New(t)
+
+	policy := newDLQPolicy(SourceConfig{
+		DLQTopic:            "test-topic-DLQ",
+		DLQMaxDeliveries:    3,
+		DLQSchemaDefinition: testDLQSchema,
+	})
+	is.Equal(len(policy.ProducerOptions.Interceptors), 1)
+
+	msg := &pulsar.ProducerMessage{
+		Key:     "test-key",
+		Payload: []byte("test-payload"),
+		Properties: map[string]string{
+			pulsar.SysPropertyRealTopic: "persistent://public/default/test-topic",
+		},
+	}
+	policy.ProducerOptions.Interceptors.BeforeSend(nil, msg)
+
+	// the payload conforms to the envelope schema
+	var got struct {
+		Payload         []byte `avro:"payload"`
+		OriginalTopic   string `avro:"originalTopic"`
+		RedeliveryCount int    `avro:"redeliveryCount"`
+		Note            string `avro:"note"`
+	}
+	err := avro.Unmarshal(avro.MustParse(testDLQSchema), msg.Payload, &got)
+	is.NoErr(err)
+	is.Equal(got.Payload, []byte("test-payload"))
+	is.Equal(got.OriginalTopic, "persistent://public/default/test-topic")
+	is.Equal(got.RedeliveryCount, 3)
+	is.Equal(got.Note, "")
+}
+
+func TestNewDLQEnvelope_Invalid(t *testing.T) {
+	testCases := []struct {
+		name       string
+		definition string
+	}{
+		{name: "not a schema", definition: `{"type":`},
+		{name: "not a record", definition: `"bytes"`},
+		{name: "no payload", definition: `{"type":"record","name":"DeadLetter","fields":[{"name":"key","type":"string"}]}`},
+		{name: "unknown field without default", definition: `{"type":"record","name":"DeadLetter","fields":[{"name":"payload","type":"bytes"},{"name":"note","type":"string"}]}`},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			is := is.New(t)
+			_, err := newDLQEnvelope(tc.definition)
+			is.True(err != nil)
+		})
+	}
+}
+
+func TestDLQEnvelopeInterceptor_OrderingKey(t *testing.T) {
+	is := is.New(t)
+
+	definition := `{
+  "type": "record",
+  "name": "DeadLetter",
+  "fields": [
+    {"name": "payload", "type": "bytes"},
+    {"name": "orderingKey", "type": "string"}
+  ]
+}`
+	policy := newDLQPolicy(SourceConfig{
+		DLQTopic:            "test-topic-DLQ",
+		DLQMaxDeliveries:    3,
+		DLQSchemaDefinition: definition,
+	})
+
+	msg := &pulsar.ProducerMessage{
+		OrderingKey: "customer-1",
+		Payload:     []byte("test-payload"),
+	}
+	policy.ProducerOptions.Interceptors.BeforeSend(nil, msg)
+	is.Equal(msg.OrderingKey, "customer-1")
+
+	var got struct {
+		OrderingKey string `avro:"orderingKey"`
+	}
+	err := avro.Unmarshal(avro.MustParse(definition), msg.Payload, &got)
+	is.NoErr(err)
+	is.Equal(got.OrderingKey, "customer-1")
+}
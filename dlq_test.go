@@ -0,0 +1,122 @@
+// Copyright © 2024 Meroxa, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pulsar
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/apache/pulsar-client-go/pulsar"
+	"github.com/matryer/is"
+)
+
+func TestNewDLQPolicy_Disabled(t *testing.T) {
+	is := is.New(t)
+	is.Equal(newDLQPolicy(SourceConfig{}), nil)
+}
+
+func TestDLQDiagnosticsInterceptor(t *testing.T) {
+	is := is.New(t)
+
+	policy := newDLQPolicy(SourceConfig{
+		DLQTopic:                "test-topic-DLQ",
+		DLQMaxDeliveries:        3,
+		DLQDiagnosticProperties: true,
+	})
+	is.Equal(policy.DeadLetterTopic, "test-topic-DLQ")
+	is.Equal(policy.MaxDeliveries, uint32(3))
+	is.Equal(len(policy.ProducerOptions.Interceptors), 1)
+
+	// the Pulsar client copies the key and properties of the original message
+	msg := &pulsar.ProducerMessage{
+		Key: "test-key",
+		Properties: map[string]string{
+			"foo":                       "bar",
+			pulsar.SysPropertyRealTopic: "persistent://public/default/test-topic",
+		},
+	}
+	policy.ProducerOptions.Interceptors.BeforeSend(nil, msg)
+
+	is.Equal(msg.Key, "test-key")
+	is.Equal(msg.Properties, map[string]string{
+		"foo":                       "bar",
+		pulsar.SysPropertyRealTopic: "persistent://public/default/test-topic",
+		dlqPropertyOriginalTopic:    "persistent://public/default/test-topic",
+		dlqPropertyFailureReason:    dlqFailureReasonMaxDeliveries,
+		dlqPropertyRedeliveryCount:  "3",
+	})
+}
+
+func TestNewDLQPolicy_DefaultTopic(t *testing.T) {
+	is := is.New(t)
+
+	policy := newDLQPolicy(SourceConfig{
+		Config:           Config{Topic: "test-topic"},
+		SubscriptionName: "test-subscription",
+		DLQMaxDeliveries: 3,
+	})
+	is.Equal(policy.DeadLetterTopic, "test-topic-test-subscription-DLQ")
+}
+
+func TestNewDLQPolicy_MaxBackoff(t *testing.T) {
+	is := is.New(t)
+
+	policy := newDLQPolicy(SourceConfig{
+		Config:           Config{MaxBackoff: time.Second},
+		DLQTopic:         "test-topic-DLQ",
+		DLQMaxDeliveries: 3,
+	})
+
+	// the dead letter producer reconnects with the backoff of the source
+	backoff := policy.ProducerOptions.BackOffPolicyFunc()
+	for i := 0; i < 10; i++ {
+		is.True(backoff.Next() <= time.Second+time.Duration(float64(time.Second)*reconnectJitter))
+	}
+	is.True(backoff.IsMaxBackoffReached())
+}
+
+func TestSource_Configure_DLQTopicRequiredForMultipleTopics(t *testing.T) {
+	is := is.New(t)
+
+	cfgMap := newSourceCfg("")
+	delete(cfgMap, SourceConfigTopic)
+	cfgMap[SourceConfigTopics] = "topic-a,topic-b"
+	cfgMap[SourceConfigDlqMaxDeliveries] = "3"
+
+	err := (&Source{}).Configure(context.Background(), cfgMap)
+	is.True(err != nil)
+
+	cfgMap[SourceConfigDlqTopic] = "topics-DLQ"
+	err = (&Source{}).Configure(context.Background(), cfgMap)
+	is.NoErr(err)
+}
+
+func TestNewDLQPolicy_PreservesOrderingKey(t *testing.T) {
+	is := is.New(t)
+
+	policy := newDLQPolicy(SourceConfig{
+		DLQTopic:                "test-topic-DLQ",
+		DLQMaxDeliveries:        3,
+		DLQDiagnosticProperties: true,
+	})
+	// messages with different ordering keys are not batched together
+	is.Equal(policy.ProducerOptions.BatcherBuilderType, pulsar.KeyBasedBatchBuilder)
+
+	// the Pulsar client copies the ordering key of the original message
+	msg := &pulsar.ProducerMessage{OrderingKey: "customer-1"}
+	policy.ProducerOptions.Interceptors.BeforeSend(nil, msg)
+	is.Equal(msg.OrderingKey, "customer-1")
+}
@@ -0,0 +1,176 @@
+// Copyright © 2024 Meroxa, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pulsar
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/apache/pulsar-client-go/pulsar"
+	"github.com/apache/pulsar-client-go/pulsar/crypto"
+	"github.com/apache/pulsar-client-go/pulsar/log"
+	"github.com/conduitio/conduit-commons/opencdc"
+	sdk "github.com/conduitio/conduit-connector-sdk"
+)
+
+// Metadata keys containing the encryption context of a message that was
+// passed through without being decrypted.
+const (
+	metadataEncryptionKeys             = "pulsar.encryption.keys"
+	metadataEncryptionParam            = "pulsar.encryption.param"
+	metadataEncryptionAlgorithm        = "pulsar.encryption.algorithm"
+	metadataEncryptionCompressionType  = "pulsar.encryption.compressionType"
+	metadataEncryptionUncompressedSize = "pulsar.encryption.uncompressedSize"
+	metadataEncryptionBatchSize        = "pulsar.encryption.batchSize"
+)
+
+// passThroughDecryption configures the consumer to deliver encrypted messages
+// as is. No key reader is configured, so decryption always fails and the
+// message is consumed with its encryption context.
+var passThroughDecryption = &pulsar.MessageDecryptionInfo{
+	ConsumerCryptoFailureAction: crypto.ConsumerCryptoFailureActionConsume,
+}
+
+// setEncryptionContext stores the encryption context of the message in the
+// metadata. Messages that are not encrypted are left untouched.
+func setEncryptionContext(metadata opencdc.Metadata, msg pulsar.Message) error {
+	encCtx := msg.GetEncryptionContext()
+	if encCtx == nil || len(encCtx.Keys) == 0 {
+		return nil
+	}
+
+	keys, err := json.Marshal(encCtx.Keys)
+	if err != nil {
+		return fmt.Errorf("failed to marshal encryption keys: %w", err)
+	}
+
+	metadata[metadataEncryptionKeys] = string(keys)
+	metadata[metadataEncryptionParam] = base64.StdEncoding.EncodeToString(encCtx.Param)
+	metadata[metadataEncryptionAlgorithm] = encCtx.Algorithm
+	metadata[metadataEncryptionCompressionType] = strconv.Itoa(int(encCtx.CompressionType))
+	metadata[metadataEncryptionUncompressedSize] = strconv.Itoa(encCtx.UncompressedSize)
+	metadata[metadataEncryptionBatchSize] = strconv.Itoa(encCtx.BatchSize)
+
+	return nil
+}
+
+// rotatingMessageCrypto encrypts produced messages and supports rotating the
+// data key while producers are using it. The default message crypto of the
+// client replaces the data key without holding the lock used for encrypting,
+// so both operations are serialized here.
+type rotatingMessageCrypto struct {
+	crypto.MessageCrypto
+
+	m         sync.Mutex
+	keys      []string
+	keyReader crypto.KeyReader
+}
+
+func newRotatingMessageCrypto(keys []string, keyReader crypto.KeyReader) (*rotatingMessageCrypto, error) {
+	messageCrypto, err := crypto.NewDefaultMessageCrypto("conduit-connector-pulsar", true, log.DefaultNopLogger())
+	if err != nil {
+		return nil, fmt.Errorf("failed to create message crypto: %w", err)
+	}
+	c := &rotatingMessageCrypto{
+		MessageCrypto: messageCrypto,
+		keys:          keys,
+		keyReader:     keyReader,
+	}
+	if err := c.rotate(); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+// encryptionInfo returns the encryption options of producers using this
+// message crypto.
+func (c *rotatingMessageCrypto) encryptionInfo() *pulsar.ProducerEncryptionInfo {
+	return &pulsar.ProducerEncryptionInfo{
+		KeyReader:     c.keyReader,
+		MessageCrypto: c,
+		Keys:          c.keys,
+	}
+}
+
+// rotate generates a new data key and encrypts it with the public keys
+// currently returned by the key reader. Messages encrypted afterwards use the
+// new keys.
+func (c *rotatingMessageCrypto) rotate() error {
+	c.m.Lock()
+	defer c.m.Unlock()
+	if err := c.MessageCrypto.AddPublicKeyCipher(c.keys, c.keyReader); err != nil {
+		// the data key was already replaced, drop the keys encrypting the
+		// previous data key so they are reloaded on the next encryption
+		for _, key := range c.keys {
+			c.MessageCrypto.RemoveKeyCipher(key)
+		}
+		return fmt.Errorf("failed to load encryption keys: %w", err)
+	}
+	return nil
+}
+
+func (c *rotatingMessageCrypto) AddPublicKeyCipher(keyNames []string, keyReader crypto.KeyReader) error {
+	c.m.Lock()
+	defer c.m.Unlock()
+	return c.MessageCrypto.AddPublicKeyCipher(keyNames, keyReader)
+}
+
+func (c *rotatingMessageCrypto) Encrypt(
+	encKeys []string,
+	keyReader crypto.KeyReader,
+	msgMetadata crypto.MessageMetadataSupplier,
+	payload []byte,
+) ([]byte, error) {
+	c.m.Lock()
+	defer c.m.Unlock()
+	return c.MessageCrypto.Encrypt(encKeys, keyReader, msgMetadata, payload)
+}
+
+// rotateEvery rotates the keys at the given interval until the returned
+// function is called. Failed rotations are logged and retried on the next
+// tick.
+func (c *rotatingMessageCrypto) rotateEvery(ctx context.Context, interval time.Duration) (stop func()) {
+	logger := sdk.Logger(ctx)
+	ticker := time.NewTicker(interval)
+	done := make(chan struct{})
+	stopped := make(chan struct{})
+
+	go func() {
+		defer close(stopped)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-done:
+				return
+			case <-ticker.C:
+				if err := c.rotate(); err != nil {
+					logger.Warn().Err(err).Msg("failed to rotate encryption keys")
+					continue
+				}
+				logger.Debug().Msg("rotated encryption keys")
+			}
+		}
+	}()
+
+	return func() {
+		close(done)
+		<-stopped
+	}
+}
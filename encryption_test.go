@@ -0,0 +1,169 @@
+// Copyright © 2024 Meroxa, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pulsar
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
+	"sync"
+	"testing"
+
+	"github.com/apache/pulsar-client-go/pulsar/crypto"
+	"github.com/apache/pulsar-client-go/pulsar/log"
+	"github.com/matryer/is"
+)
+
+func TestRotatingMessageCrypto_Rotate(t *testing.T) {
+	is := is.New(t)
+
+	oldKey := newTestRSAKey(t)
+	newKey := newTestRSAKey(t)
+	keyReader := &swappableKeyReader{key: oldKey}
+	keys := []string{"test-key"}
+
+	messageCrypto, err := newRotatingMessageCrypto(keys, keyReader)
+	is.NoErr(err)
+
+	payload := []byte("hello world")
+	before := &testMessageMetadata{}
+	encrypted, err := messageCrypto.Encrypt(keys, keyReader, before, payload)
+	is.NoErr(err)
+	is.Equal(decryptWith(t, oldKey, before, encrypted), payload)
+
+	// rotate the key mid-stream
+	keyReader.swap(newKey)
+	is.NoErr(messageCrypto.rotate())
+
+	after := &testMessageMetadata{}
+	encrypted, err = messageCrypto.Encrypt(keys, keyReader, after, payload)
+	is.NoErr(err)
+	is.Equal(decryptWith(t, newKey, after, encrypted), payload)
+
+	_, err = newTestDecryptor(t).Decrypt(after, encrypted, &swappableKeyReader{key: oldKey})
+	is.True(err != nil) // messages can't be decrypted with the old key anymore
+}
+
+func TestRotatingMessageCrypto_RotateFailed(t *testing.T) {
+	is := is.New(t)
+
+	key := newTestRSAKey(t)
+	keyReader := &swappableKeyReader{key: key}
+	keys := []string{"test-key"}
+
+	messageCrypto, err := newRotatingMessageCrypto(keys, keyReader)
+	is.NoErr(err)
+
+	keyReader.swap(nil)
+	is.True(messageCrypto.rotate() != nil)
+
+	// the key is reloaded once it's available again
+	keyReader.swap(key)
+	payload := []byte("hello world")
+	md := &testMessageMetadata{}
+	encrypted, err := messageCrypto.Encrypt(keys, keyReader, md, payload)
+	is.NoErr(err)
+	is.Equal(decryptWith(t, key, md, encrypted), payload)
+}
+
+func newTestRSAKey(t *testing.T) *rsa.PrivateKey {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return key
+}
+
+func newTestDecryptor(t *testing.T) crypto.MessageCrypto {
+	decryptor, err := crypto.NewDefaultMessageCrypto("test", false, log.DefaultNopLogger())
+	if err != nil {
+		t.Fatal(err)
+	}
+	return decryptor
+}
+
+func decryptWith(t *testing.T, key *rsa.PrivateKey, md crypto.MessageMetadataSupplier, payload []byte) []byte {
+	decrypted, err := newTestDecryptor(t).Decrypt(md, payload, &swappableKeyReader{key: key})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return decrypted
+}
+
+// swappableKeyReader returns PEM encoded keys of the current RSA key, which
+// can be swapped to simulate a key rotation.
+type swappableKeyReader struct {
+	m   sync.Mutex
+	key *rsa.PrivateKey
+}
+
+func (r *swappableKeyReader) swap(key *rsa.PrivateKey) {
+	r.m.Lock()
+	defer r.m.Unlock()
+	r.key = key
+}
+
+func (r *swappableKeyReader) PublicKey(keyName string, metadata map[string]string) (*crypto.EncryptionKeyInfo, error) {
+	r.m.Lock()
+	defer r.m.Unlock()
+	if r.key == nil {
+		return nil, errors.New("key not available")
+	}
+	der, err := x509.MarshalPKIXPublicKey(&r.key.PublicKey)
+	if err != nil {
+		return nil, err
+	}
+	return crypto.NewEncryptionKeyInfo(keyName, pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der}), metadata), nil
+}
+
+func (r *swappableKeyReader) PrivateKey(keyName string, metadata map[string]string) (*crypto.EncryptionKeyInfo, error) {
+	r.m.Lock()
+	defer r.m.Unlock()
+	if r.key == nil {
+		return nil, errors.New("key not available")
+	}
+	der := x509.MarshalPKCS1PrivateKey(r.key)
+	return crypto.NewEncryptionKeyInfo(keyName, pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: der}), metadata), nil
+}
+
+// testMessageMetadata stores the encryption metadata of a single message.
+type testMessageMetadata struct {
+	keys  []crypto.EncryptionKeyInfo
+	param []byte
+}
+
+func (md *testMessageMetadata) EncryptionKeys() []crypto.EncryptionKeyInfo {
+	return md.keys
+}
+
+func (md *testMessageMetadata) UpsertEncryptionKey(key crypto.EncryptionKeyInfo) {
+	for i, k := range md.keys {
+		if k.Name() == key.Name() {
+			md.keys[i] = key
+			return
+		}
+	}
+	md.keys = append(md.keys, key)
+}
+
+func (md *testMessageMetadata) EncryptionParam() []byte {
+	return md.param
+}
+
+func (md *testMessageMetadata) SetEncryptionParam(param []byte) {
+	md.param = param
+}
@@ -0,0 +1,71 @@
+// Copyright © 2024 Meroxa, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pulsar
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/apache/pulsar-client-go/pulsar"
+)
+
+// eventTimeRange bounds the event time of consumed messages. Zero bounds are
+// open.
+type eventTimeRange struct {
+	from time.Time
+	to   time.Time
+}
+
+// parseEventTimeRange parses the RFC 3339 bounds of the range, empty bounds
+// are left open.
+func parseEventTimeRange(from, to string) (eventTimeRange, error) {
+	var r eventTimeRange
+	var err error
+	if from != "" {
+		if r.from, err = time.Parse(time.RFC3339, from); err != nil {
+			return eventTimeRange{}, fmt.Errorf("invalid %q: %w", SourceConfigEventTimeFrom, err)
+		}
+	}
+	if to != "" {
+		if r.to, err = time.Parse(time.RFC3339, to); err != nil {
+			return eventTimeRange{}, fmt.Errorf("invalid %q: %w", SourceConfigEventTimeTo, err)
+		}
+	}
+	if !r.from.IsZero() && !r.to.IsZero() && r.from.After(r.to) {
+		return eventTimeRange{}, fmt.Errorf("%q must not be after %q", SourceConfigEventTimeFrom, SourceConfigEventTimeTo)
+	}
+	return r, nil
+}
+
+func (r eventTimeRange) isOpen() bool {
+	return r.from.IsZero() && r.to.IsZero()
+}
+
+// contains returns true if the event time of the message is within the range,
+// both bounds are inclusive. The publish time is used if the message has no
+// event time.
+func (r eventTimeRange) contains(msg pulsar.Message) bool {
+	t := msg.EventTime()
+	if t.IsZero() {
+		t = msg.PublishTime()
+	}
+	if !r.from.IsZero() && t.Before(r.from) {
+		return false
+	}
+	if !r.to.IsZero() && t.After(r.to) {
+		return false
+	}
+	return true
+}
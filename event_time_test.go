@@ -0,0 +1,61 @@
+// Copyright © 2024 Meroxa, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pulsar
+
+import (
+	"testing"
+	"time"
+
+	"github.com/matryer/is"
+)
+
+// eventTimeMessage is a message with an event time, which fakeMessage lacks.
+type eventTimeMessage struct {
+	fakeMessage
+	eventTime time.Time
+}
+
+func (m eventTimeMessage) EventTime() time.Time { return m.eventTime }
+
+func TestEventTimeRange_Contains(t *testing.T) {
+	is := is.New(t)
+
+	r, err := parseEventTimeRange("2024-01-01T01:00:00Z", "2024-01-01T03:00:00Z")
+	is.NoErr(err)
+
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	for hour, want := range []bool{false, true, true, true, false} {
+		msg := eventTimeMessage{eventTime: start.Add(time.Duration(hour) * time.Hour)}
+		is.Equal(r.contains(msg), want) // unexpected result for hour
+	}
+
+	// the publish time is used if the message has no event time
+	is.True(r.contains(eventTimeMessage{fakeMessage: fakeMessage{publishTime: start.Add(2 * time.Hour)}}))
+	is.True(!r.contains(eventTimeMessage{fakeMessage: fakeMessage{publishTime: start}}))
+}
+
+func TestParseEventTimeRange(t *testing.T) {
+	is := is.New(t)
+
+	r, err := parseEventTimeRange("", "")
+	is.NoErr(err)
+	is.True(r.isOpen())
+
+	_, err = parseEventTimeRange("yesterday", "")
+	is.True(err != nil)
+
+	_, err = parseEventTimeRange("2024-01-02T00:00:00Z", "2024-01-01T00:00:00Z")
+	is.True(err != nil)
+}
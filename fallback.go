@@ -0,0 +1,50 @@
+// Copyright © 2024 Meroxa, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pulsar
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/apache/pulsar-client-go/pulsar"
+	sdk "github.com/conduitio/conduit-connector-sdk"
+)
+
+// sendWithFallback sends the message to the topic, resending it until
+// FallbackThreshold consecutive sends failed. The message is then sent to the
+// fallback topic, as are all messages until FallbackCooldown elapsed. It
+// returns the topic the message was sent to.
+func (d *Destination) sendWithFallback(ctx context.Context, msg *pulsar.ProducerMessage) (string, error) {
+	for d.fallback.allow() == nil {
+		err := d.sendRecovering(ctx, d.producer, d.config.Topic, msg)
+		if err == nil {
+			d.fallback.record(nil)
+			return d.config.Topic, nil
+		}
+		if d.fallback.record(err) {
+			sdk.Logger(ctx).Warn().Err(err).
+				Str("topic", d.config.Topic).
+				Str("fallbackTopic", d.config.FallbackTopic).
+				Int("failures", d.fallback.failures).
+				Dur("cooldown", d.fallback.cooldown).
+				Msg("sends to the topic failed repeatedly, producing to the fallback topic")
+		}
+	}
+
+	if err := d.send(ctx, d.fallbackProducer, msg); err != nil {
+		return d.config.FallbackTopic, fmt.Errorf("failed to send message to fallback topic: %w", err)
+	}
+	return d.config.FallbackTopic, nil
+}
@@ -0,0 +1,88 @@
+// Copyright © 2024 Meroxa, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pulsar
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/apache/pulsar-client-go/pulsar"
+	"github.com/conduitio-labs/conduit-connector-pulsar/test"
+	"github.com/conduitio/conduit-commons/opencdc"
+	sdk "github.com/conduitio/conduit-connector-sdk"
+	"github.com/matryer/is"
+)
+
+func TestDestination_Write_FallbackTopic(t *testing.T) {
+	is := is.New(t)
+
+	primary := &failingProducer{err: pulsar.ErrSendTimeout, failures: 4}
+	fallback := &recordingProducer{}
+	breaker := newCircuitBreaker(3, time.Minute)
+	clock := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	breaker.now = func() time.Time { return clock }
+
+	con := &Destination{
+		producer:         primary,
+		fallbackProducer: fallback,
+		fallback:         breaker,
+		config: DestinationConfig{
+			Config:        Config{Topic: "test-topic"},
+			FallbackTopic: "test-topic-fallback",
+		},
+	}
+
+	newRecord := func(key string) opencdc.Record {
+		return sdk.Util.Source.NewRecordCreate(nil, nil, opencdc.RawData(key), opencdc.RawData(exampleMessage))
+	}
+
+	// the first record is sent to the topic 3 times before it falls back, the
+	// second one is sent to the fallback topic right away
+	written, err := con.Write(context.Background(), []opencdc.Record{newRecord("1"), newRecord("2")})
+	is.NoErr(err)
+	is.Equal(written, 2)
+	is.Equal(primary.attempts, 3)
+	is.Equal(len(fallback.sent), 2)
+	is.Equal(fallback.sent[0].Key, "1")
+	is.Equal(fallback.sent[1].Key, "2")
+
+	// after the cooldown the topic is tried again, it fails once more and
+	// the record falls back right away
+	clock = clock.Add(time.Minute)
+	_, err = con.Write(context.Background(), []opencdc.Record{newRecord("3")})
+	is.NoErr(err)
+	is.Equal(primary.attempts, 4)
+	is.Equal(len(fallback.sent), 3)
+
+	// once the topic recovers records are sent to it again
+	clock = clock.Add(time.Minute)
+	_, err = con.Write(context.Background(), []opencdc.Record{newRecord("4"), newRecord("5")})
+	is.NoErr(err)
+	is.Equal(primary.attempts, 6)
+	is.Equal(len(fallback.sent), 3)
+}
+
+func TestDestination_Configure_FallbackTopicTemplate(t *testing.T) {
+	is := is.New(t)
+	con := NewDestination()
+
+	err := con.Configure(context.Background(), map[string]string{
+		DestinationConfigUrl:           test.PulsarURL,
+		DestinationConfigTopic:         `events-{{index .Metadata "tenant"}}`,
+		DestinationConfigFallbackTopic: "events-fallback",
+	})
+	is.True(err != nil)
+}
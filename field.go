@@ -0,0 +1,93 @@
+// Copyright © 2024 Meroxa, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pulsar
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/conduitio/conduit-commons/opencdc"
+)
+
+// Prefixes of references to record fields.
+const (
+	fieldKey          = ".Key"
+	fieldMetadata     = ".Metadata."
+	fieldPayloadAfter = ".Payload.After."
+)
+
+// validateField checks that field references a part of the record that can
+// be resolved by resolveField.
+func validateField(field string) error {
+	switch {
+	case field == fieldKey:
+		return nil
+	case strings.HasPrefix(field, fieldMetadata) && len(field) > len(fieldMetadata):
+		return nil
+	case strings.HasPrefix(field, fieldPayloadAfter) && len(field) > len(fieldPayloadAfter):
+		return nil
+	default:
+		return fmt.Errorf("invalid field reference %q, expected %q, %q or %q", field, fieldKey, fieldMetadata+"<key>", fieldPayloadAfter+"<field>")
+	}
+}
+
+// resolveSequenceID returns the value of the referenced record field as a
+// sequence ID.
+func resolveSequenceID(record opencdc.Record, field string) (int64, error) {
+	val, err := resolveField(record, field)
+	if err != nil {
+		return 0, fmt.Errorf("failed to resolve sequence ID: %w", err)
+	}
+	sequenceID, err := strconv.ParseInt(val, 10, 64)
+	if err != nil {
+		// numbers in structured payloads decoded from JSON are floats
+		f, ferr := strconv.ParseFloat(val, 64)
+		if ferr == nil && f == math.Trunc(f) && f <= math.MaxInt64 {
+			sequenceID, err = int64(f), nil
+		}
+	}
+	if err != nil || sequenceID < 0 {
+		return 0, fmt.Errorf("sequence ID %q of field %q is not a non-negative integer", val, field)
+	}
+	return sequenceID, nil
+}
+
+// resolveField returns the value of the referenced record field as a string.
+// Fields in the payload can only be resolved if the payload is structured.
+func resolveField(record opencdc.Record, field string) (string, error) {
+	switch {
+	case field == fieldKey:
+		if record.Key == nil {
+			return "", nil
+		}
+		return string(record.Key.Bytes()), nil
+	case strings.HasPrefix(field, fieldMetadata):
+		return record.Metadata[strings.TrimPrefix(field, fieldMetadata)], nil
+	case strings.HasPrefix(field, fieldPayloadAfter):
+		structured, ok := record.Payload.After.(opencdc.StructuredData)
+		if !ok {
+			return "", fmt.Errorf("can't resolve %q, payload is not structured", field)
+		}
+		val, ok := structured[strings.TrimPrefix(field, fieldPayloadAfter)]
+		if !ok || val == nil {
+			return "", nil
+		}
+		return fmt.Sprint(val), nil
+	default:
+		return "", fmt.Errorf("invalid field reference %q", field)
+	}
+}
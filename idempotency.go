@@ -0,0 +1,74 @@
+// Copyright © 2024 Meroxa, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pulsar
+
+import (
+	"time"
+)
+
+// idempotencyWindow remembers the idempotency keys of records produced within
+// the window. Keys are kept in memory and expire in the order they were
+// produced, so memory is bounded by the number of records produced within the
+// window. The window is not persisted, duplicates are only detected within a
+// single run of the connector.
+type idempotencyWindow struct {
+	window time.Duration
+	now    func() time.Time
+
+	seen    map[string]time.Time
+	entries []idempotencyEntry
+}
+
+type idempotencyEntry struct {
+	key        string
+	producedAt time.Time
+}
+
+func newIdempotencyWindow(window time.Duration) *idempotencyWindow {
+	return &idempotencyWindow{
+		window: window,
+		now:    time.Now,
+		seen:   make(map[string]time.Time),
+	}
+}
+
+// isDuplicate returns true if a record with the key was produced within the
+// window.
+func (w *idempotencyWindow) isDuplicate(key string) bool {
+	w.expire()
+	_, ok := w.seen[key]
+	return ok
+}
+
+// add records that a record with the key was produced.
+func (w *idempotencyWindow) add(key string) {
+	now := w.now()
+	w.seen[key] = now
+	w.entries = append(w.entries, idempotencyEntry{key: key, producedAt: now})
+}
+
+func (w *idempotencyWindow) expire() {
+	cutoff := w.now().Add(-w.window)
+
+	n := 0
+	for ; n < len(w.entries) && !w.entries[n].producedAt.After(cutoff); n++ {
+		e := w.entries[n]
+		// the key may have been produced again after this entry
+		if w.seen[e.key].Equal(e.producedAt) {
+			delete(w.seen, e.key)
+		}
+	}
+	w.entries = w.entries[n:]
+}
@@ -0,0 +1,50 @@
+// Copyright © 2024 Meroxa, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pulsar
+
+import (
+	"testing"
+	"time"
+
+	"github.com/matryer/is"
+)
+
+func TestIdempotencyWindow(t *testing.T) {
+	is := is.New(t)
+
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	w := newIdempotencyWindow(time.Minute)
+	w.now = func() time.Time { return now }
+
+	is.True(!w.isDuplicate("a"))
+	w.add("a")
+	is.True(w.isDuplicate("a"))
+
+	now = now.Add(30 * time.Second)
+	w.add("b")
+	is.True(w.isDuplicate("a"))
+	is.True(w.isDuplicate("b"))
+
+	// a expires, b is still within the window
+	now = now.Add(30 * time.Second)
+	is.True(!w.isDuplicate("a"))
+	is.True(w.isDuplicate("b"))
+
+	// producing a key again restarts its window
+	w.add("b")
+	now = now.Add(45 * time.Second)
+	is.True(w.isDuplicate("b"))
+	is.Equal(len(w.entries), 1)
+}
@@ -0,0 +1,90 @@
+// Copyright © 2024 Meroxa, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pulsar
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/apache/pulsar-client-go/pulsar"
+	sdk "github.com/conduitio/conduit-connector-sdk"
+)
+
+// inFlightPollInterval is how often teardown checks if in-flight messages
+// were acked.
+const inFlightPollInterval = 10 * time.Millisecond
+
+// inFlightTracker keeps track of messages that were read but not acked yet.
+// Read and Ack can be called concurrently.
+type inFlightTracker struct {
+	mu  sync.Mutex
+	ids map[string]pulsar.MessageID
+}
+
+func newInFlightTracker() *inFlightTracker {
+	return &inFlightTracker{ids: make(map[string]pulsar.MessageID)}
+}
+
+func (t *inFlightTracker) add(id pulsar.MessageID) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.ids[string(id.Serialize())] = id
+}
+
+// remove stops tracking the message with the serialized ID.
+func (t *inFlightTracker) remove(serializedID []byte) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	delete(t.ids, string(serializedID))
+}
+
+// len returns the number of tracked messages.
+func (t *inFlightTracker) len() int {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return len(t.ids)
+}
+
+// drain returns all tracked messages and stops tracking them.
+func (t *inFlightTracker) drain() []pulsar.MessageID {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	ids := make([]pulsar.MessageID, 0, len(t.ids))
+	for _, id := range t.ids {
+		ids = append(ids, id)
+	}
+	t.ids = make(map[string]pulsar.MessageID)
+	return ids
+}
+
+// waitForAcks waits until all in-flight messages are acked or TeardownTimeout
+// passed. The tracker is polled, so Ack isn't blocked while waiting.
+func (s *Source) waitForAcks(ctx context.Context) {
+	ctx, cancel := context.WithTimeout(ctx, s.config.TeardownTimeout)
+	defer cancel()
+	ticker := time.NewTicker(inFlightPollInterval)
+	defer ticker.Stop()
+
+	for n := s.inFlight.len(); n > 0; n = s.inFlight.len() {
+		select {
+		case <-ctx.Done():
+			sdk.Logger(ctx).Warn().Int("count", n).Msg("in-flight messages were not acked within the teardown timeout")
+			return
+		case <-ticker.C:
+		}
+	}
+}
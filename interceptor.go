@@ -0,0 +1,56 @@
+// Copyright © 2024 Meroxa, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pulsar
+
+import (
+	"context"
+	"time"
+
+	"github.com/apache/pulsar-client-go/pulsar"
+	sdk "github.com/conduitio/conduit-connector-sdk"
+	"github.com/rs/zerolog"
+)
+
+// lagInterceptor is a consumer interceptor that measures the time between a
+// message being published and the consumer receiving it.
+type lagInterceptor struct {
+	logger *zerolog.Logger
+	now    func() time.Time
+}
+
+func newLagInterceptor(ctx context.Context) *lagInterceptor {
+	return &lagInterceptor{
+		logger: sdk.Logger(ctx),
+		now:    time.Now,
+	}
+}
+
+func (i *lagInterceptor) BeforeConsume(msg pulsar.ConsumerMessage) {
+	publishTime := msg.PublishTime()
+	if publishTime.IsZero() {
+		return
+	}
+
+	lag := i.now().Sub(publishTime)
+	i.logger.Debug().
+		Str("topic", msg.Topic()).
+		Str("messageID", msg.ID().String()).
+		Dur("lag", lag).
+		Msg("measured message lag")
+}
+
+func (i *lagInterceptor) OnAcknowledge(pulsar.Consumer, pulsar.MessageID) {}
+
+func (i *lagInterceptor) OnNegativeAcksSend(pulsar.Consumer, []pulsar.MessageID) {}
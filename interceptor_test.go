@@ -0,0 +1,69 @@
+// Copyright © 2024 Meroxa, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pulsar
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/apache/pulsar-client-go/pulsar"
+	"github.com/matryer/is"
+	"github.com/rs/zerolog"
+)
+
+// fakeMessage implements pulsar.Message, only the methods used by the
+// connector are overridden.
+type fakeMessage struct {
+	pulsar.Message
+
+	topic       string
+	id          pulsar.MessageID
+	publishTime time.Time
+}
+
+func (m fakeMessage) Topic() string          { return m.topic }
+func (m fakeMessage) ID() pulsar.MessageID   { return m.id }
+func (m fakeMessage) PublishTime() time.Time { return m.publishTime }
+
+func TestLagInterceptor_BeforeConsume(t *testing.T) {
+	is := is.New(t)
+
+	var buf bytes.Buffer
+	logger := zerolog.New(&buf)
+	ctx := logger.WithContext(context.Background())
+
+	publishTime := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	interceptor := newLagInterceptor(ctx)
+	interceptor.now = func() time.Time { return publishTime.Add(1500 * time.Millisecond) }
+
+	interceptor.BeforeConsume(pulsar.ConsumerMessage{
+		Message: fakeMessage{
+			topic:       "test-topic",
+			id:          pulsar.EarliestMessageID(),
+			publishTime: publishTime,
+		},
+	})
+
+	var entry struct {
+		Topic string  `json:"topic"`
+		Lag   float64 `json:"lag"`
+	}
+	is.NoErr(json.Unmarshal(buf.Bytes(), &entry))
+	is.Equal(entry.Topic, "test-topic")
+	is.Equal(entry.Lag, float64(1500)) // lag is logged in milliseconds
+}
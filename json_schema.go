@@ -0,0 +1,75 @@
+// Copyright © 2024 Meroxa, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pulsar
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/santhosh-tekuri/jsonschema/v6"
+)
+
+// jsonSchemaResource is the URL the configured JSON schema is registered
+// under, it is only used to reference the schema in the compiler.
+const jsonSchemaResource = "connector-schema.json"
+
+// errPayloadNotJSON is returned when a payload can't be validated because it
+// is not a JSON document.
+var errPayloadNotJSON = errors.New("payload is not valid JSON")
+
+// payloadValidator validates message payloads against a JSON schema.
+type payloadValidator struct {
+	schema *jsonschema.Schema
+}
+
+// newPayloadValidator compiles the JSON schema, which is either a JSON
+// document or the path of a file containing it.
+func newPayloadValidator(schema string) (*payloadValidator, error) {
+	doc := []byte(schema)
+	if !strings.HasPrefix(strings.TrimSpace(schema), "{") {
+		var err error
+		doc, err = os.ReadFile(schema)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read JSON schema: %w", err)
+		}
+	}
+
+	parsed, err := jsonschema.UnmarshalJSON(bytes.NewReader(doc))
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse JSON schema: %w", err)
+	}
+	compiler := jsonschema.NewCompiler()
+	if err := compiler.AddResource(jsonSchemaResource, parsed); err != nil {
+		return nil, fmt.Errorf("failed to add JSON schema: %w", err)
+	}
+	compiled, err := compiler.Compile(jsonSchemaResource)
+	if err != nil {
+		return nil, fmt.Errorf("failed to compile JSON schema: %w", err)
+	}
+	return &payloadValidator{schema: compiled}, nil
+}
+
+// validate returns an error if the payload is not a JSON document matching the
+// schema.
+func (v *payloadValidator) validate(payload []byte) error {
+	doc, err := jsonschema.UnmarshalJSON(bytes.NewReader(payload))
+	if err != nil {
+		return fmt.Errorf("%w: %w", errPayloadNotJSON, err)
+	}
+	return v.schema.Validate(doc)
+}
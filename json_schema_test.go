@@ -0,0 +1,157 @@
+// Copyright © 2024 Meroxa, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pulsar
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/apache/pulsar-client-go/pulsar"
+	"github.com/matryer/is"
+)
+
+const testJSONSchema = `{
+	"type": "object",
+	"properties": {"id": {"type": "integer"}},
+	"required": ["id"]
+}`
+
+func TestPayloadValidator(t *testing.T) {
+	is := is.New(t)
+
+	validator, err := newPayloadValidator(testJSONSchema)
+	is.NoErr(err)
+
+	is.NoErr(validator.validate([]byte(`{"id": 1}`)))
+	is.True(validator.validate([]byte(`{"id": "1"}`)) != nil)
+	is.True(validator.validate([]byte(`{}`)) != nil)
+	is.True(validator.validate([]byte(`not json`)) != nil)
+}
+
+func TestPayloadValidator_SchemaFile(t *testing.T) {
+	is := is.New(t)
+
+	path := filepath.Join(t.TempDir(), "schema.json")
+	err := os.WriteFile(path, []byte(testJSONSchema), 0o600)
+	is.NoErr(err)
+
+	validator, err := newPayloadValidator(path)
+	is.NoErr(err)
+	is.NoErr(validator.validate([]byte(`{"id": 1}`)))
+}
+
+func TestPayloadValidator_InvalidSchema(t *testing.T) {
+	testCases := []struct {
+		name   string
+		schema string
+	}{
+		{name: "malformed", schema: `{"type": `},
+		{name: "invalid keyword value", schema: `{"type": "number", "minimum": "zero"}`},
+		{name: "missing file", schema: "does-not-exist.json"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			is := is.New(t)
+			_, err := newPayloadValidator(tc.schema)
+			is.True(err != nil)
+		})
+	}
+}
+
+// payloadMessage is a readableMessage with a payload.
+type payloadMessage struct {
+	readableMessage
+	payload []byte
+}
+
+func (m payloadMessage) Payload() []byte { return m.payload }
+
+func TestSource_Read_JSONSchemaValidation(t *testing.T) {
+	invalid := payloadMessage{readableMessage{fakeMessage{id: pulsar.NewMessageID(1, 1, 0, 0)}}, []byte(`{"id": "one"}`)}
+	valid := payloadMessage{readableMessage{fakeMessage{id: pulsar.NewMessageID(1, 2, 0, 0)}}, []byte(`{"id": 2}`)}
+
+	testCases := []struct {
+		name       string
+		maxDeliver int
+		wantNacked bool
+	}{
+		{name: "without dead letter topic", maxDeliver: 0},
+		{name: "with dead letter topic", maxDeliver: 3, wantNacked: true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			is := is.New(t)
+
+			validator, err := newPayloadValidator(testJSONSchema)
+			is.NoErr(err)
+
+			consumer := &rejectRecordingConsumer{queueConsumer: queueConsumer{messages: []pulsar.Message{invalid, valid}}}
+			underTest := &Source{
+				consumer: consumer,
+				config:   SourceConfig{DLQMaxDeliveries: tc.maxDeliver},
+				payloads: validator,
+			}
+
+			rec, err := underTest.Read(context.Background())
+			is.NoErr(err)
+			is.Equal(rec.Payload.After.Bytes(), valid.payload)
+
+			// invalid messages are redelivered until they are routed to the
+			// dead letter topic, without one they are dropped
+			rejected := consumer.acked
+			if tc.wantNacked {
+				rejected = consumer.nacked
+			}
+			is.Equal(len(consumer.acked)+len(consumer.nacked), 1)
+			is.Equal(len(rejected), 1)
+			is.Equal(rejected[0].String(), invalid.ID().String())
+		})
+	}
+}
+
+func TestSource_Read_JSONSchemaValidationDecompressed(t *testing.T) {
+	is := is.New(t)
+
+	validator, err := newPayloadValidator(testJSONSchema)
+	is.NoErr(err)
+
+	msg := payloadMessage{readableMessage{fakeMessage{id: pulsar.NewMessageID(1, 1, 0, 0)}}, gzipBytes(t, []byte(`{"id": 1}`))}
+	consumer := &rejectRecordingConsumer{queueConsumer: queueConsumer{messages: []pulsar.Message{msg}}}
+	underTest := &Source{
+		consumer: consumer,
+		config:   SourceConfig{AutoDecompressPayload: true},
+		payloads: validator,
+	}
+
+	// the decompressed payload is validated
+	rec, err := underTest.Read(context.Background())
+	is.NoErr(err)
+	is.Equal(rec.Payload.After.Bytes(), []byte(`{"id": 1}`))
+	is.Equal(len(consumer.acked)+len(consumer.nacked), 0)
+}
+
+func TestSource_Configure_InvalidJSONSchema(t *testing.T) {
+	is := is.New(t)
+
+	cfgMap := newSourceCfg("topic")
+	cfgMap[SourceConfigJsonSchemaValidation] = `{"type": "unknown"}`
+
+	err := (&Source{}).Configure(context.Background(), cfgMap)
+	is.True(err != nil)
+}
@@ -0,0 +1,153 @@
+// Copyright © 2024 Meroxa, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pulsar
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Supported values of DestinationConfig.KeyJSONPathFallback.
+const (
+	// KeyJSONPathFallbackKey uses the record key if the key can't be
+	// extracted from the payload.
+	KeyJSONPathFallbackKey = "key"
+	// KeyJSONPathFallbackEmpty produces the message without a key if the key
+	// can't be extracted from the payload.
+	KeyJSONPathFallbackEmpty = "empty"
+	// KeyJSONPathFallbackFail fails the write if the key can't be extracted
+	// from the payload.
+	KeyJSONPathFallbackFail = "fail"
+)
+
+var errJSONPathNotFound = errors.New("no value at JSONPath")
+
+// jsonPath is a parsed JSONPath expression that selects a single value. Only
+// the root, child names and array indices are supported, e.g.
+// `$.user.ids[0]` or `$['user']['id']`.
+type jsonPath struct {
+	expr  string
+	steps []jsonPathStep
+}
+
+// jsonPathStep selects a child by name, or an array element if isIndex is set.
+type jsonPathStep struct {
+	name    string
+	index   int
+	isIndex bool
+}
+
+func parseJSONPath(expr string) (jsonPath, error) {
+	rest, ok := strings.CutPrefix(expr, "$")
+	if !ok {
+		return jsonPath{}, fmt.Errorf("JSONPath %q must start with $", expr)
+	}
+
+	p := jsonPath{expr: expr}
+	for rest != "" {
+		var step jsonPathStep
+		switch rest[0] {
+		case '.':
+			end := strings.IndexAny(rest[1:], ".[")
+			if end == -1 {
+				end = len(rest) - 1
+			}
+			step.name, rest = rest[1:end+1], rest[end+1:]
+			if step.name == "" || step.name == "*" {
+				return jsonPath{}, fmt.Errorf("invalid JSONPath %q: expected a child name after .", expr)
+			}
+		case '[':
+			end := strings.IndexByte(rest, ']')
+			if end == -1 {
+				return jsonPath{}, fmt.Errorf("invalid JSONPath %q: missing ]", expr)
+			}
+			selector := rest[1:end]
+			rest = rest[end+1:]
+			if name, ok := unquoteJSONPathName(selector); ok {
+				step.name = name
+				break
+			}
+			index, err := strconv.Atoi(selector)
+			if err != nil || index < 0 {
+				return jsonPath{}, fmt.Errorf("invalid JSONPath %q: unsupported selector [%s]", expr, selector)
+			}
+			step.index, step.isIndex = index, true
+		default:
+			return jsonPath{}, fmt.Errorf("invalid JSONPath %q: unexpected %q", expr, rest[0])
+		}
+		p.steps = append(p.steps, step)
+	}
+	return p, nil
+}
+
+// unquoteJSONPathName returns the name in a bracket selector like ['name'].
+func unquoteJSONPathName(selector string) (string, bool) {
+	if len(selector) < 2 {
+		return "", false
+	}
+	quote := selector[0]
+	if (quote != '\'' && quote != '"') || selector[len(selector)-1] != quote {
+		return "", false
+	}
+	return selector[1 : len(selector)-1], true
+}
+
+// extract returns the selected value of the JSON document as a string.
+// Strings are returned as is, other values are encoded as JSON.
+func (p jsonPath) extract(doc []byte) (string, error) {
+	dec := json.NewDecoder(bytes.NewReader(doc))
+	dec.UseNumber()
+	var val any
+	if err := dec.Decode(&val); err != nil {
+		return "", fmt.Errorf("payload is not valid JSON: %w", err)
+	}
+
+	for _, step := range p.steps {
+		var ok bool
+		if step.isIndex {
+			var arr []any
+			if arr, ok = val.([]any); ok && step.index < len(arr) {
+				val = arr[step.index]
+			} else {
+				ok = false
+			}
+		} else {
+			var obj map[string]any
+			if obj, ok = val.(map[string]any); ok {
+				val, ok = obj[step.name]
+			}
+		}
+		if !ok {
+			return "", fmt.Errorf("%w %q", errJSONPathNotFound, p.expr)
+		}
+	}
+
+	switch val := val.(type) {
+	case nil:
+		return "", fmt.Errorf("%w %q", errJSONPathNotFound, p.expr)
+	case string:
+		return val, nil
+	default:
+		encoded, err := json.Marshal(val)
+		if err != nil {
+			return "", fmt.Errorf("failed to encode value at JSONPath %q: %w", p.expr, err)
+		}
+		return string(encoded), nil
+	}
+}
@@ -0,0 +1,90 @@
+// Copyright © 2024 Meroxa, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pulsar
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/matryer/is"
+)
+
+func TestParseJSONPath_Invalid(t *testing.T) {
+	for _, expr := range []string{
+		"",
+		"user.id",
+		"$.",
+		"$..id",
+		"$.user.*",
+		"$.ids[",
+		"$.ids[-1]",
+		"$.ids[?(@.id)]",
+		"$user",
+	} {
+		t.Run(expr, func(t *testing.T) {
+			is := is.New(t)
+			_, err := parseJSONPath(expr)
+			is.True(err != nil)
+		})
+	}
+}
+
+func TestJSONPath_Extract(t *testing.T) {
+	doc := []byte(`{"user": {"id": 42, "name": "jane", "tags": ["a", "b"], "address": {"zip": "1000"}}, "odd.key": true, "none": null}`)
+
+	testCases := []struct {
+		expr    string
+		want    string
+		wantErr error
+	}{
+		{expr: "$.user.name", want: "jane"},
+		{expr: "$.user.id", want: "42"},
+		{expr: "$.user.tags[1]", want: "b"},
+		{expr: "$['user']['address'].zip", want: "1000"},
+		{expr: `$["odd.key"]`, want: "true"},
+		{expr: "$.user.address", want: `{"zip":"1000"}`},
+		{expr: "$.user.email", wantErr: errJSONPathNotFound},
+		{expr: "$.user.tags[2]", wantErr: errJSONPathNotFound},
+		{expr: "$.user.name[0]", wantErr: errJSONPathNotFound},
+		{expr: "$.none", wantErr: errJSONPathNotFound},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.expr, func(t *testing.T) {
+			is := is.New(t)
+
+			p, err := parseJSONPath(tc.expr)
+			is.NoErr(err)
+
+			got, err := p.extract(doc)
+			if tc.wantErr != nil {
+				is.True(errors.Is(err, tc.wantErr))
+				return
+			}
+			is.NoErr(err)
+			is.Equal(got, tc.want)
+		})
+	}
+}
+
+func TestJSONPath_Extract_InvalidJSON(t *testing.T) {
+	is := is.New(t)
+
+	p, err := parseJSONPath("$.id")
+	is.NoErr(err)
+
+	_, err = p.extract([]byte("not json"))
+	is.True(err != nil)
+}
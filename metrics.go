@@ -0,0 +1,111 @@
+// Copyright © 2024 Meroxa, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pulsar
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"time"
+
+	"github.com/apache/pulsar-client-go/pulsar"
+	sdk "github.com/conduitio/conduit-connector-sdk"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+// metricsReadHeaderTimeout bounds how long the metrics server waits for the
+// request headers of a scrape.
+const metricsReadHeaderTimeout = 10 * time.Second
+
+// Supported values of SourceConfig.MetricsCardinality.
+const (
+	// MetricsCardinalityNone doesn't label the client metrics.
+	MetricsCardinalityNone = "none"
+	// MetricsCardinalityTenant labels the client metrics by tenant.
+	MetricsCardinalityTenant = "tenant"
+	// MetricsCardinalityNamespace labels the client metrics by tenant and
+	// namespace.
+	MetricsCardinalityNamespace = "namespace"
+	// MetricsCardinalityTopic labels the client metrics by topic.
+	MetricsCardinalityTopic = "topic"
+)
+
+// toMetricsCardinality maps a supported value of
+// SourceConfig.MetricsCardinality to the cardinality of the client.
+func toMetricsCardinality(cardinality string) pulsar.MetricsCardinality {
+	switch cardinality {
+	case MetricsCardinalityNone:
+		return pulsar.MetricsCardinalityNone
+	case MetricsCardinalityTenant:
+		return pulsar.MetricsCardinalityTenant
+	case MetricsCardinalityTopic:
+		return pulsar.MetricsCardinalityTopic
+	default:
+		return pulsar.MetricsCardinalityNamespace
+	}
+}
+
+// NewSourceWithMetricsRegistry creates a source that registers the metrics of
+// the Pulsar client and the source in registry instead of the default
+// Prometheus registry, e.g. to expose them together with the metrics of the
+// application embedding the connector.
+func NewSourceWithMetricsRegistry(registry *prometheus.Registry) sdk.Source {
+	return sdk.SourceWithMiddleware(&Source{positionStore: noopPositionStore{}, metrics: registry}, sdk.DefaultSourceMiddleware()...)
+}
+
+// metricsRegisterer returns the registerer of the source metrics.
+func (s *Source) metricsRegisterer() prometheus.Registerer {
+	if s.metrics != nil {
+		return s.metrics
+	}
+	return prometheus.DefaultRegisterer
+}
+
+// metricsGatherer returns the gatherer of the source metrics.
+func (s *Source) metricsGatherer() prometheus.Gatherer {
+	if s.metrics != nil {
+		return s.metrics
+	}
+	return prometheus.DefaultGatherer
+}
+
+// serveMetrics serves the gathered metrics on the "/metrics" path of address
+// until the returned server is shut down.
+func serveMetrics(ctx context.Context, address string, gatherer prometheus.Gatherer) (*http.Server, error) {
+	listener, err := net.Listen("tcp", address)
+	if err != nil {
+		return nil, fmt.Errorf("failed to listen on metrics address %q: %w", address, err)
+	}
+
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", promhttp.HandlerFor(gatherer, promhttp.HandlerOpts{}))
+	server := &http.Server{
+		Addr:              listener.Addr().String(),
+		Handler:           mux,
+		ReadHeaderTimeout: metricsReadHeaderTimeout,
+	}
+
+	go func() {
+		if err := server.Serve(listener); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			sdk.Logger(ctx).Error().Err(err).Msg("failed to serve metrics")
+		}
+	}()
+	sdk.Logger(ctx).Info().Str("address", server.Addr).Msg("serving metrics")
+
+	return server, nil
+}
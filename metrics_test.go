@@ -0,0 +1,81 @@
+// Copyright © 2024 Meroxa, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pulsar
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/apache/pulsar-client-go/pulsar"
+	"github.com/matryer/is"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+func TestToMetricsCardinality(t *testing.T) {
+	testCases := []struct {
+		cardinality string
+		want        pulsar.MetricsCardinality
+	}{
+		{cardinality: MetricsCardinalityNone, want: pulsar.MetricsCardinalityNone},
+		{cardinality: MetricsCardinalityTenant, want: pulsar.MetricsCardinalityTenant},
+		{cardinality: MetricsCardinalityNamespace, want: pulsar.MetricsCardinalityNamespace},
+		{cardinality: MetricsCardinalityTopic, want: pulsar.MetricsCardinalityTopic},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.cardinality, func(t *testing.T) {
+			is := is.New(t)
+			is.Equal(toMetricsCardinality(tc.cardinality), tc.want)
+		})
+	}
+}
+
+func TestServeMetrics(t *testing.T) {
+	is := is.New(t)
+	ctx := context.Background()
+
+	registry := prometheus.NewRegistry()
+	underTest := &Source{metrics: registry}
+	recorder, err := newAckLatencyRecorder(underTest.metricsRegisterer(), "test-topic")
+	is.NoErr(err)
+	recorder.read([]byte("id"))
+	recorder.acked([]byte("id"))
+
+	server, err := serveMetrics(ctx, "127.0.0.1:0", underTest.metricsGatherer())
+	is.NoErr(err)
+	defer func() {
+		is.NoErr(server.Shutdown(ctx))
+	}()
+
+	resp, err := http.Get("http://" + server.Addr + "/metrics")
+	is.NoErr(err)
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	is.NoErr(err)
+
+	is.Equal(resp.StatusCode, http.StatusOK)
+	is.True(strings.Contains(string(body), `conduit_pulsar_source_ack_latency_seconds_count{topic="test-topic"} 1`))
+}
+
+func TestSource_Configure_MetricsCardinality(t *testing.T) {
+	is := is.New(t)
+
+	cfgMap := newSourceCfg("topic")
+	cfgMap[SourceConfigMetricsCardinality] = "partition"
+	is.True((&Source{}).Configure(context.Background(), cfgMap) != nil)
+}
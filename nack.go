@@ -0,0 +1,90 @@
+// Copyright © 2024 Meroxa, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pulsar
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/apache/pulsar-client-go/pulsar"
+	"github.com/conduitio/conduit-commons/opencdc"
+	sdk "github.com/conduitio/conduit-connector-sdk"
+)
+
+// Nack negatively acknowledges the message of the record at the position, so
+// it is redelivered after NackRedeliveryDelay, or retried from the retry
+// letter topic after the retry delay if EnableRetry is set. It is routed to
+// the dead letter topic once DLQMaxDeliveries is exceeded. The connector SDK
+// doesn't report failed records to sources, so Conduit doesn't call Nack.
+// Records that are never acked are nacked by ProcessingDeadline or released
+// by NackInFlightOnShutdown instead.
+func (s *Source) Nack(ctx context.Context, position opencdc.Position) error {
+	if s.reader != nil {
+		// readers don't acknowledge messages
+		return nil
+	}
+
+	parsed, err := parsePosition(position)
+	if err != nil {
+		return err
+	}
+	if parsed.Watermark {
+		// watermarks don't refer to a message
+		return nil
+	}
+
+	msgID, err := pulsar.DeserializeMessageID(parsed.MessageID)
+	if err != nil {
+		return fmt.Errorf("failed to deserialize message ID: %w", err)
+	}
+
+	s.nackID(msgID)
+	sdk.Logger(ctx).Trace().Str("MessageID", msgID.String()).Msg("nacked message")
+	return nil
+}
+
+// nackID negatively acknowledges the message and stops tracking it as read,
+// it is tracked again when it is redelivered.
+func (s *Source) nackID(id pulsar.MessageID) {
+	serializedID := id.Serialize()
+	var msg pulsar.Message
+	if s.retries != nil {
+		msg = s.retries.remove(serializedID)
+	}
+	if msg != nil {
+		// retried from the retry letter topic
+		s.redeliver(msg)
+	} else {
+		s.consumerMu.RLock()
+		s.consumer.NackID(id)
+		s.consumerMu.RUnlock()
+	}
+
+	if s.inFlight != nil {
+		s.inFlight.remove(serializedID)
+	}
+	if s.deadlines != nil {
+		s.deadlines.remove(serializedID)
+	}
+	if s.acks != nil {
+		s.acks.forget(serializedID)
+	}
+	if s.ackLatency != nil {
+		s.ackLatency.forget(serializedID)
+	}
+	if s.prefetch != nil {
+		s.prefetch.forget(serializedID)
+	}
+}
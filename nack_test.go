@@ -0,0 +1,65 @@
+// Copyright © 2024 Meroxa, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pulsar
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/apache/pulsar-client-go/pulsar"
+	"github.com/matryer/is"
+)
+
+func TestSource_Nack(t *testing.T) {
+	is := is.New(t)
+	ctx := context.Background()
+
+	msg := readableMessage{fakeMessage{topic: "test-topic", id: pulsar.NewMessageID(1, 1, 0, 0)}}
+	consumer := &rejectRecordingConsumer{queueConsumer: queueConsumer{messages: []pulsar.Message{msg}}}
+	underTest := &Source{
+		consumer:  consumer,
+		config:    SourceConfig{Config: Config{Topic: "test-topic"}},
+		inFlight:  newInFlightTracker(),
+		deadlines: newProcessingDeadlines(time.Minute),
+		prefetch:  newAdaptivePrefetch(1, 1, time.Second),
+	}
+
+	rec, err := underTest.Read(ctx)
+	is.NoErr(err)
+	is.True(underTest.prefetch.full())
+
+	err = underTest.Nack(ctx, rec.Position)
+	is.NoErr(err)
+
+	is.Equal(len(consumer.acked), 0)
+	is.Equal(len(consumer.nacked), 1)
+	is.Equal(consumer.nacked[0].String(), msg.ID().String())
+
+	// the message is not tracked as read anymore
+	is.Equal(len(underTest.inFlight.drain()), 0)
+	is.Equal(len(underTest.deadlines.expiresAt), 0)
+	is.True(!underTest.prefetch.full())
+}
+
+func TestSource_Configure_NegativeNackRedeliveryDelay(t *testing.T) {
+	is := is.New(t)
+
+	cfgMap := newSourceCfg("topic")
+	cfgMap[SourceConfigNackRedeliveryDelay] = "-1s"
+
+	err := (&Source{}).Configure(context.Background(), cfgMap)
+	is.True(err != nil)
+}
@@ -0,0 +1,123 @@
+// Copyright © 2024 Meroxa, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pulsar
+
+import (
+	"container/heap"
+	"context"
+	"errors"
+	"time"
+
+	"github.com/apache/pulsar-client-go/pulsar"
+	sdk "github.com/conduitio/conduit-connector-sdk"
+)
+
+// orderingBuffer reorders received messages by their publish time. Each
+// message is held back until it was buffered for the duration of the window,
+// so messages from other partitions published earlier can overtake it.
+type orderingBuffer struct {
+	window   time.Duration
+	messages orderedMessages
+	seq      uint64
+
+	now func() time.Time
+}
+
+func newOrderingBuffer(window time.Duration) *orderingBuffer {
+	return &orderingBuffer{
+		window: window,
+		now:    time.Now,
+	}
+}
+
+// receive returns the oldest buffered message once its window elapsed,
+// buffering messages returned by next in the meantime.
+func (b *orderingBuffer) receive(ctx context.Context, next func(context.Context) (pulsar.Message, error)) (pulsar.Message, error) {
+	for {
+		if b.messages.Len() > 0 {
+			wait := b.messages[0].bufferedAt.Add(b.window).Sub(b.now())
+			if wait <= 0 {
+				return b.pop(), nil
+			}
+
+			waitCtx, cancel := context.WithTimeout(ctx, wait)
+			msg, err := next(waitCtx)
+			cancel()
+			switch {
+			case err == nil:
+				b.push(msg)
+			case errors.Is(err, sdk.ErrBackoffRetry):
+				// no more messages are coming, emit the buffered ones
+				return b.pop(), nil
+			case errors.Is(err, context.DeadlineExceeded) && ctx.Err() == nil:
+				// the window of the oldest message elapsed
+			default:
+				return nil, err
+			}
+			continue
+		}
+
+		msg, err := next(ctx)
+		if err != nil {
+			return nil, err
+		}
+		b.push(msg)
+	}
+}
+
+func (b *orderingBuffer) push(msg pulsar.Message) {
+	heap.Push(&b.messages, bufferedMessage{
+		Message:    msg,
+		bufferedAt: b.now(),
+		seq:        b.seq,
+	})
+	b.seq++
+}
+
+func (b *orderingBuffer) pop() pulsar.Message {
+	return heap.Pop(&b.messages).(bufferedMessage).Message
+}
+
+type bufferedMessage struct {
+	pulsar.Message
+	bufferedAt time.Time
+	// seq keeps the receive order of messages with the same publish time.
+	seq uint64
+}
+
+// orderedMessages is a min-heap of messages ordered by publish time.
+type orderedMessages []bufferedMessage
+
+func (m orderedMessages) Len() int { return len(m) }
+
+func (m orderedMessages) Less(i, j int) bool {
+	ti, tj := m[i].PublishTime(), m[j].PublishTime()
+	if ti.Equal(tj) {
+		return m[i].seq < m[j].seq
+	}
+	return ti.Before(tj)
+}
+
+func (m orderedMessages) Swap(i, j int) { m[i], m[j] = m[j], m[i] }
+
+func (m *orderedMessages) Push(x any) { *m = append(*m, x.(bufferedMessage)) }
+
+func (m *orderedMessages) Pop() any {
+	old := *m
+	n := len(old)
+	msg := old[n-1]
+	*m = old[:n-1]
+	return msg
+}
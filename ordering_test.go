@@ -0,0 +1,106 @@
+// Copyright © 2024 Meroxa, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pulsar
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/apache/pulsar-client-go/pulsar"
+	sdk "github.com/conduitio/conduit-connector-sdk"
+	"github.com/matryer/is"
+)
+
+// queuedMessages returns a function that returns the messages in order and
+// then blocks until the context is done, or returns err if it's set.
+func queuedMessages(err error, msgs ...pulsar.Message) func(context.Context) (pulsar.Message, error) {
+	return func(ctx context.Context) (pulsar.Message, error) {
+		if len(msgs) > 0 {
+			msg := msgs[0]
+			msgs = msgs[1:]
+			return msg, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		<-ctx.Done()
+		return nil, ctx.Err()
+	}
+}
+
+func TestOrderingBuffer_Receive(t *testing.T) {
+	is := is.New(t)
+	ctx := context.Background()
+
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	msg := func(partition string, offset time.Duration) pulsar.Message {
+		return fakeMessage{topic: "test-topic-partition-" + partition, publishTime: start.Add(offset)}
+	}
+
+	// partitions are received one after the other, their messages interleave
+	next := queuedMessages(nil,
+		msg("0", 0),
+		msg("0", 20*time.Millisecond),
+		msg("0", 40*time.Millisecond),
+		msg("1", 10*time.Millisecond),
+		msg("1", 30*time.Millisecond),
+		msg("0", 40*time.Millisecond),
+	)
+
+	buffer := newOrderingBuffer(50 * time.Millisecond)
+	want := []string{"0", "1", "0", "1", "0", "0"}
+	var prev time.Time
+	for _, partition := range want {
+		got, err := buffer.receive(ctx, next)
+		is.NoErr(err)
+		is.Equal(got.Topic(), "test-topic-partition-"+partition) // unexpected partition
+		is.True(!got.PublishTime().Before(prev))                 // messages out of order
+		prev = got.PublishTime()
+	}
+}
+
+func TestOrderingBuffer_Receive_Cancelled(t *testing.T) {
+	is := is.New(t)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+
+	buffer := newOrderingBuffer(time.Hour)
+	_, err := buffer.receive(ctx, queuedMessages(nil, fakeMessage{topic: "test-topic"}))
+	is.Equal(err, context.DeadlineExceeded)
+}
+
+func TestOrderingBuffer_Receive_NoMoreMessages(t *testing.T) {
+	is := is.New(t)
+	ctx := context.Background()
+
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	next := queuedMessages(sdk.ErrBackoffRetry,
+		fakeMessage{topic: "b", publishTime: start.Add(time.Second)},
+		fakeMessage{topic: "a", publishTime: start},
+	)
+
+	// buffered messages are emitted right away once no more messages come
+	buffer := newOrderingBuffer(time.Hour)
+	got, err := buffer.receive(ctx, next)
+	is.NoErr(err)
+	is.Equal(got.Topic(), "a")
+	got, err = buffer.receive(ctx, next)
+	is.NoErr(err)
+	is.Equal(got.Topic(), "b")
+	_, err = buffer.receive(ctx, next)
+	is.Equal(err, sdk.ErrBackoffRetry)
+}
@@ -8,59 +8,586 @@ import (
 )
 
 const (
-	DestinationConfigConnectionTimeout          = "connectionTimeout"
-	DestinationConfigDisableLogging             = "disableLogging"
-	DestinationConfigEnableTransaction          = "enableTransaction"
-	DestinationConfigMaxConnectionsPerBroker    = "maxConnectionsPerBroker"
-	DestinationConfigMemoryLimitBytes           = "memoryLimitBytes"
-	DestinationConfigOperationTimeout           = "operationTimeout"
-	DestinationConfigTlsAllowInsecureConnection = "tlsAllowInsecureConnection"
-	DestinationConfigTlsCertificateFile         = "tlsCertificateFile"
-	DestinationConfigTlsKeyFilePath             = "tlsKeyFilePath"
-	DestinationConfigTlsTrustCertsFilePath      = "tlsTrustCertsFilePath"
-	DestinationConfigTlsValidateHostname        = "tlsValidateHostname"
-	DestinationConfigTopic                      = "topic"
-	DestinationConfigUrl                        = "url"
+	DestinationConfigAdaptiveThrottling            = "adaptiveThrottling"
+	DestinationConfigAdaptiveThrottlingMaxDelay    = "adaptiveThrottlingMaxDelay"
+	DestinationConfigAdminURL                      = "adminURL"
+	DestinationConfigAuditMetadata                 = "auditMetadata"
+	DestinationConfigAutoGrowPartitions            = "autoGrowPartitions"
+	DestinationConfigAutoGrowPartitionsThreshold   = "autoGrowPartitionsThreshold"
+	DestinationConfigAutoGrowPartitionsWindow      = "autoGrowPartitionsWindow"
+	DestinationConfigBacklogQuotaMaxRetries        = "backlogQuotaMaxRetries"
+	DestinationConfigBacklogQuotaRetryBackoff      = "backlogQuotaRetryBackoff"
+	DestinationConfigBatchingMaxMessages           = "batchingMaxMessages"
+	DestinationConfigBatchingMaxPublishDelay       = "batchingMaxPublishDelay"
+	DestinationConfigBatchingMaxSize               = "batchingMaxSize"
+	DestinationConfigBlockIfQueueFull              = "blockIfQueueFull"
+	DestinationConfigCircuitBreakerCooldown        = "circuitBreakerCooldown"
+	DestinationConfigCircuitBreakerThreshold       = "circuitBreakerThreshold"
+	DestinationConfigCompressionDictionary         = "compressionDictionary"
+	DestinationConfigCompressionLevel              = "compressionLevel"
+	DestinationConfigCompressionType               = "compressionType"
+	DestinationConfigConnectionTimeout             = "connectionTimeout"
+	DestinationConfigDedupSnapshotInterval         = "dedupSnapshotInterval"
+	DestinationConfigDisableBatching               = "disableBatching"
+	DestinationConfigDisableLogging                = "disableLogging"
+	DestinationConfigDisableReplicationMetadataKey = "disableReplicationMetadataKey"
+	DestinationConfigEnableTopicDeduplication      = "enableTopicDeduplication"
+	DestinationConfigEnableTransaction             = "enableTransaction"
+	DestinationConfigEncryptionKeyRotationInterval = "encryptionKeyRotationInterval"
+	DestinationConfigEncryptionKeys                = "encryptionKeys"
+	DestinationConfigEncryptionPublicKeyPath       = "encryptionPublicKeyPath"
+	DestinationConfigExclusiveWaitTimeout          = "exclusiveWaitTimeout"
+	DestinationConfigFallbackCooldown              = "fallbackCooldown"
+	DestinationConfigFallbackThreshold             = "fallbackThreshold"
+	DestinationConfigFallbackTopic                 = "fallbackTopic"
+	DestinationConfigForceSinglePartition          = "forceSinglePartition"
+	DestinationConfigForceSinglePartitionTarget    = "forceSinglePartitionTarget"
+	DestinationConfigForwardAllMetadata            = "forwardAllMetadata"
+	DestinationConfigIdempotencyKeyField           = "idempotencyKeyField"
+	DestinationConfigIdempotencyWindow             = "idempotencyWindow"
+	DestinationConfigKeepAliveInterval             = "keepAliveInterval"
+	DestinationConfigKeyField                      = "keyField"
+	DestinationConfigKeyJSONPath                   = "keyJSONPath"
+	DestinationConfigKeyJSONPathFallback           = "keyJSONPathFallback"
+	DestinationConfigLargeMessageThreshold         = "largeMessageThreshold"
+	DestinationConfigLargeMessageTopic             = "largeMessageTopic"
+	DestinationConfigListenerName                  = "listenerName"
+	DestinationConfigLogProduceResults             = "logProduceResults"
+	DestinationConfigLogProduceResultsSampleRate   = "logProduceResultsSampleRate"
+	DestinationConfigLookupTimeout                 = "lookupTimeout"
+	DestinationConfigMaxBackoff                    = "maxBackoff"
+	DestinationConfigMaxConnectionsPerBroker       = "maxConnectionsPerBroker"
+	DestinationConfigMaxPartitions                 = "maxPartitions"
+	DestinationConfigMaxPendingMessages            = "maxPendingMessages"
+	DestinationConfigMemoryLimitBytes              = "memoryLimitBytes"
+	DestinationConfigNullValueMarker               = "nullValueMarker"
+	DestinationConfigOauth2Audience                = "oauth2Audience"
+	DestinationConfigOauth2ClientID                = "oauth2ClientID"
+	DestinationConfigOauth2ClientSecret            = "oauth2ClientSecret"
+	DestinationConfigOauth2IssuerURL               = "oauth2IssuerURL"
+	DestinationConfigOauth2PrivateKeyFile          = "oauth2PrivateKeyFile"
+	DestinationConfigOauth2Scope                   = "oauth2Scope"
+	DestinationConfigOperationTimeout              = "operationTimeout"
+	DestinationConfigOrderingGuarantee             = "orderingGuarantee"
+	DestinationConfigOrderingKeyField              = "orderingKeyField"
+	DestinationConfigPartitionByField              = "partitionByField"
+	DestinationConfigPriorityMetadataKey           = "priorityMetadataKey"
+	DestinationConfigProduceAckTimeout             = "produceAckTimeout"
+	DestinationConfigProduceMaxRetries             = "produceMaxRetries"
+	DestinationConfigProduceRetryBackoff           = "produceRetryBackoff"
+	DestinationConfigProduceRetryableErrors        = "produceRetryableErrors"
+	DestinationConfigProducerAccessMode            = "producerAccessMode"
+	DestinationConfigProducerName                  = "producerName"
+	DestinationConfigSchemaRegistryMaxRetries      = "schemaRegistryMaxRetries"
+	DestinationConfigSchemaRegistryRetryBackoff    = "schemaRegistryRetryBackoff"
+	DestinationConfigSendTimeout                   = "sendTimeout"
+	DestinationConfigSequenceIDField               = "sequenceIDField"
+	DestinationConfigSequenceStorePath             = "sequenceStorePath"
+	DestinationConfigTlsAllowInsecureConnection    = "tlsAllowInsecureConnection"
+	DestinationConfigTlsCertificateFile            = "tlsCertificateFile"
+	DestinationConfigTlsKeyFilePath                = "tlsKeyFilePath"
+	DestinationConfigTlsTrustCertsFilePath         = "tlsTrustCertsFilePath"
+	DestinationConfigTlsValidateHostname           = "tlsValidateHostname"
+	DestinationConfigToken                         = "token"
+	DestinationConfigTokenFilePath                 = "tokenFilePath"
+	DestinationConfigTopic                         = "topic"
+	DestinationConfigTopicNotFoundPolicy           = "topicNotFoundPolicy"
+	DestinationConfigTopicNotFoundRetryInterval    = "topicNotFoundRetryInterval"
+	DestinationConfigTransactionTimeout            = "transactionTimeout"
+	DestinationConfigTransactionalWrites           = "transactionalWrites"
+	DestinationConfigUrl                           = "url"
+	DestinationConfigValidateBeforeSend            = "validateBeforeSend"
+	DestinationConfigWriteBufferFlushTimeout       = "writeBufferFlushTimeout"
+	DestinationConfigWriteBufferMaxBytes           = "writeBufferMaxBytes"
+	DestinationConfigWriteBufferMaxRecords         = "writeBufferMaxRecords"
 )
 
 func (DestinationConfig) Parameters() map[string]config.Parameter {
 	return map[string]config.Parameter{
+		DestinationConfigAdaptiveThrottling: {
+			Default:     "",
+			Description: "AdaptiveThrottling slows down producing when the send queue or the\nmemory buffer of the client is full, instead of blocking until there\nis room. The delay between sends grows while the pressure persists and\nshrinks as it eases. Only asynchronous sends fill the queue, so it\nrequires the write buffer.",
+			Type:        config.ParameterTypeBool,
+			Validations: []config.Validation{},
+		},
+		DestinationConfigAdaptiveThrottlingMaxDelay: {
+			Default:     "1s",
+			Description: "AdaptiveThrottlingMaxDelay is the maximum delay between sends when\nAdaptiveThrottling is enabled.",
+			Type:        config.ParameterTypeDuration,
+			Validations: []config.Validation{},
+		},
+		DestinationConfigAdminURL: {
+			Default:     "",
+			Description: "AdminURL is the URL of the Pulsar admin (web service) API. It is only\nneeded by options that manage topic policies.",
+			Type:        config.ParameterTypeString,
+			Validations: []config.Validation{},
+		},
+		DestinationConfigAuditMetadata: {
+			Default:     "",
+			Description: "AuditMetadata adds the properties \"conduit.audit.connector\",\n\"conduit.audit.instanceID\" and \"conduit.audit.producedAt\" to each\nproduced message, to track its provenance across pipelines.",
+			Type:        config.ParameterTypeBool,
+			Validations: []config.Validation{},
+		},
+		DestinationConfigAutoGrowPartitions: {
+			Default:     "",
+			Description: "AutoGrowPartitions doubles the number of partitions of the topic, up to\nMaxPartitions, when the produce throughput exceeds\nAutoGrowPartitionsThreshold. Producers pick up new partitions within a\nminute. Requires AdminURL and a partitioned topic, and can't be\ncombined with a topic template.",
+			Type:        config.ParameterTypeBool,
+			Validations: []config.Validation{},
+		},
+		DestinationConfigAutoGrowPartitionsThreshold: {
+			Default:     "",
+			Description: "AutoGrowPartitionsThreshold is the produce throughput in messages per\nsecond above which partitions are grown.",
+			Type:        config.ParameterTypeInt,
+			Validations: []config.Validation{
+				config.ValidationGreaterThan{V: -1},
+			},
+		},
+		DestinationConfigAutoGrowPartitionsWindow: {
+			Default:     "1m",
+			Description: "AutoGrowPartitionsWindow is the period over which the produce\nthroughput is measured, so partitions are only grown under sustained\nload.",
+			Type:        config.ParameterTypeDuration,
+			Validations: []config.Validation{},
+		},
+		DestinationConfigBacklogQuotaMaxRetries: {
+			Default:     "",
+			Description: "BacklogQuotaMaxRetries is the number of times sending a message is\nretried when the broker rejects it because the backlog quota of the topic\nis exceeded. Retries are disabled by default.",
+			Type:        config.ParameterTypeInt,
+			Validations: []config.Validation{
+				config.ValidationGreaterThan{V: -1},
+			},
+		},
+		DestinationConfigBacklogQuotaRetryBackoff: {
+			Default:     "1s",
+			Description: "BacklogQuotaRetryBackoff is the delay before the first retry, it is\ndoubled after each failed attempt up to one minute.",
+			Type:        config.ParameterTypeDuration,
+			Validations: []config.Validation{},
+		},
+		DestinationConfigBatchingMaxMessages: {
+			Default:     "",
+			Description: "BatchingMaxMessages is the maximum number of messages the producer\ngroups in a batch. Batches are only filled by asynchronous sends, i.e.\nwith the write buffer enabled. Uses the default of the client (1000)\nwhen set to 0.",
+			Type:        config.ParameterTypeInt,
+			Validations: []config.Validation{
+				config.ValidationGreaterThan{V: -1},
+			},
+		},
+		DestinationConfigBatchingMaxPublishDelay: {
+			Default:     "",
+			Description: "BatchingMaxPublishDelay is the maximum time messages are batched before\nthe batch is sent. Uses the default of the client (10ms) when set to 0.",
+			Type:        config.ParameterTypeDuration,
+			Validations: []config.Validation{},
+		},
+		DestinationConfigBatchingMaxSize: {
+			Default:     "",
+			Description: "BatchingMaxSize is the maximum size of a batch in bytes. Uses the\ndefault of the client (128KB) when set to 0.",
+			Type:        config.ParameterTypeInt,
+			Validations: []config.Validation{
+				config.ValidationGreaterThan{V: -1},
+			},
+		},
+		DestinationConfigBlockIfQueueFull: {
+			Default:     "true",
+			Description: "BlockIfQueueFull makes sends wait for room when MaxPendingMessages is\nreached. If disabled, the send fails instead. Sends never block with\nAdaptiveThrottling.",
+			Type:        config.ParameterTypeBool,
+			Validations: []config.Validation{},
+		},
+		DestinationConfigCircuitBreakerCooldown: {
+			Default:     "30s",
+			Description: "CircuitBreakerCooldown is how long the circuit breaker stays open before\na single send is attempted again. The circuit closes if that send\nsucceeds.",
+			Type:        config.ParameterTypeDuration,
+			Validations: []config.Validation{},
+		},
+		DestinationConfigCircuitBreakerThreshold: {
+			Default:     "",
+			Description: "CircuitBreakerThreshold is the number of consecutive failed sends after\nwhich the circuit breaker opens. While it is open, writes fail right\naway without contacting the broker. Disabled when set to 0.",
+			Type:        config.ParameterTypeInt,
+			Validations: []config.Validation{
+				config.ValidationGreaterThan{V: -1},
+			},
+		},
+		DestinationConfigCompressionDictionary: {
+			Default:     "",
+			Description: "CompressionDictionary is the path to a zstd dictionary, as created by\n\"zstd --train\". If set, payloads are compressed with zstd using the\ndictionary before they are produced. The Pulsar client doesn't support\ncompression dictionaries, so the payload is compressed by the connector\nand consumers need the same dictionary to decompress it.",
+			Type:        config.ParameterTypeString,
+			Validations: []config.Validation{},
+		},
+		DestinationConfigCompressionLevel: {
+			Default:     "default",
+			Description: "CompressionLevel trades compression speed for ratio, it is only used\nby compression types that support levels.",
+			Type:        config.ParameterTypeString,
+			Validations: []config.Validation{
+				config.ValidationInclusion{List: []string{"default", "faster", "better"}},
+			},
+		},
+		DestinationConfigCompressionType: {
+			Default:     "none",
+			Description: "CompressionType is the codec the producer compresses batches of\nmessages with. Consumers decompress them transparently. Can't be\ncombined with CompressionDictionary.",
+			Type:        config.ParameterTypeString,
+			Validations: []config.Validation{
+				config.ValidationInclusion{List: []string{"none", "lz4", "zlib", "zstd"}},
+			},
+		},
 		DestinationConfigConnectionTimeout: {
 			Default:     "",
 			Description: "ConnectionTimeout specifies the duration for which the client will\nattempt to establish a connection before timing out.",
 			Type:        config.ParameterTypeDuration,
 			Validations: []config.Validation{},
 		},
+		DestinationConfigDedupSnapshotInterval: {
+			Default:     "",
+			Description: "DedupSnapshotInterval is how often the broker takes a snapshot of the\ndeduplication state of the topic, so it survives broker restarts. The\ninterval is rounded down to whole seconds. Requires\nEnableTopicDeduplication. Uses the broker default when set to 0.",
+			Type:        config.ParameterTypeDuration,
+			Validations: []config.Validation{},
+		},
+		DestinationConfigDisableBatching: {
+			Default:     "",
+			Description: "DisableBatching sends each message on its own. Can't be combined with\nthe other batching options.",
+			Type:        config.ParameterTypeBool,
+			Validations: []config.Validation{},
+		},
 		DestinationConfigDisableLogging: {
 			Default:     "",
 			Description: "DisableLogging disables pulsar client logs",
 			Type:        config.ParameterTypeBool,
 			Validations: []config.Validation{},
 		},
+		DestinationConfigDisableReplicationMetadataKey: {
+			Default:     "",
+			Description: "DisableReplicationMetadataKey is the metadata key of a boolean flag that\ndisables geo-replication of the produced message when set to \"true\",\neven if the topic is replicated. Records without the key are replicated\nas usual, other values than booleans fail the write.",
+			Type:        config.ParameterTypeString,
+			Validations: []config.Validation{},
+		},
+		DestinationConfigEnableTopicDeduplication: {
+			Default:     "",
+			Description: "EnableTopicDeduplication enables broker-side message deduplication on\nthe topic before producing. Requires AdminURL and admin permissions on\nthe topic.",
+			Type:        config.ParameterTypeBool,
+			Validations: []config.Validation{},
+		},
 		DestinationConfigEnableTransaction: {
 			Default:     "",
 			Description: "EnableTransaction determines if the client should support transactions.",
 			Type:        config.ParameterTypeBool,
 			Validations: []config.Validation{},
 		},
+		DestinationConfigEncryptionKeyRotationInterval: {
+			Default:     "",
+			Description: "EncryptionKeyRotationInterval is the interval at which a new data key\nis generated and the public key is reloaded, so rotated keys are picked\nup without a restart. Disabled when set to 0.",
+			Type:        config.ParameterTypeDuration,
+			Validations: []config.Validation{},
+		},
+		DestinationConfigEncryptionKeys: {
+			Default:     "",
+			Description: "EncryptionKeys is a comma separated list of key names used to encrypt\nthe produced messages. Enables end-to-end encryption when set.",
+			Type:        config.ParameterTypeString,
+			Validations: []config.Validation{},
+		},
+		DestinationConfigEncryptionPublicKeyPath: {
+			Default:     "",
+			Description: "EncryptionPublicKeyPath is the path to the PEM encoded public key used\nto encrypt the data key of the messages. The file is read again on\neach key rotation.",
+			Type:        config.ParameterTypeString,
+			Validations: []config.Validation{},
+		},
+		DestinationConfigExclusiveWaitTimeout: {
+			Default:     "",
+			Description: "ExclusiveWaitTimeout is the maximum time the destination waits for\nexclusive access to the topic when ProducerAccessMode is\n\"waitForExclusive\". Waits indefinitely when set to 0.",
+			Type:        config.ParameterTypeDuration,
+			Validations: []config.Validation{},
+		},
+		DestinationConfigFallbackCooldown: {
+			Default:     "30s",
+			Description: "FallbackCooldown is how long records are produced to FallbackTopic\nbefore sending to Topic is tried again.",
+			Type:        config.ParameterTypeDuration,
+			Validations: []config.Validation{},
+		},
+		DestinationConfigFallbackThreshold: {
+			Default:     "3",
+			Description: "FallbackThreshold is the number of consecutive failed sends to Topic,\nincluding retries of the same record, after which records are produced\nto FallbackTopic.",
+			Type:        config.ParameterTypeInt,
+			Validations: []config.Validation{},
+		},
+		DestinationConfigFallbackTopic: {
+			Default:     "",
+			Description: "FallbackTopic is the topic records are produced to when sends to Topic\nfail FallbackThreshold consecutive times, so records are not lost\nduring an outage of the topic. Can't be combined with a topic\ntemplate, the write buffer or tracking sequence IDs.",
+			Type:        config.ParameterTypeString,
+			Validations: []config.Validation{},
+		},
+		DestinationConfigForceSinglePartition: {
+			Default:     "",
+			Description: "ForceSinglePartition routes all records to ForceSinglePartitionTarget\nregardless of their key, guaranteeing a strict global order. This limits\nthe throughput to what a single partition can handle. Overrides\nOrderingGuarantee.",
+			Type:        config.ParameterTypeBool,
+			Validations: []config.Validation{},
+		},
+		DestinationConfigForceSinglePartitionTarget: {
+			Default:     "",
+			Description: "ForceSinglePartitionTarget is the index of the partition all records are\nrouted to when ForceSinglePartition is enabled.",
+			Type:        config.ParameterTypeInt,
+			Validations: []config.Validation{
+				config.ValidationGreaterThan{V: -1},
+			},
+		},
+		DestinationConfigForwardAllMetadata: {
+			Default:     "",
+			Description: "ForwardAllMetadata produces every metadata field of the record as a\nmessage property. By default only the fields under\n\"pulsar.properties.\" are produced, with the prefix stripped, so the\nproperties read by the source are preserved. A field under the prefix\ntakes precedence over a field with the same name without it.",
+			Type:        config.ParameterTypeBool,
+			Validations: []config.Validation{},
+		},
+		DestinationConfigIdempotencyKeyField: {
+			Default:     "",
+			Description: "IdempotencyKeyField references the record field containing an\nidempotency key. Records with a key that was already produced within\nIdempotencyWindow are skipped. Same format as KeyField. Records with an\nempty key are always produced.",
+			Type:        config.ParameterTypeString,
+			Validations: []config.Validation{},
+		},
+		DestinationConfigIdempotencyWindow: {
+			Default:     "5m",
+			Description: "IdempotencyWindow is how long the idempotency key of a produced record\nis remembered. Keys are kept in memory and not persisted across\nrestarts.",
+			Type:        config.ParameterTypeDuration,
+			Validations: []config.Validation{},
+		},
+		DestinationConfigKeepAliveInterval: {
+			Default:     "30s",
+			Description: "KeepAliveInterval is the interval in which the client pings the\nbrokers to keep idle connections alive.",
+			Type:        config.ParameterTypeDuration,
+			Validations: []config.Validation{},
+		},
+		DestinationConfigKeyField: {
+			Default:     "",
+			Description: "KeyField references the record field used as the message key, which\ndrives routing to partitions. Can be \".Key\", \".Metadata.<key>\" or\n\".Payload.After.<field>\". Defaults to the record key.",
+			Type:        config.ParameterTypeString,
+			Validations: []config.Validation{},
+		},
+		DestinationConfigKeyJSONPath: {
+			Default:     "",
+			Description: "KeyJSONPath is a JSONPath expression selecting the message key in the\nJSON payload of the record, e.g. `$.user.id`, for records that don't\ncarry a separate key. Only child names and array indices are\nsupported. Can't be combined with KeyField.",
+			Type:        config.ParameterTypeString,
+			Validations: []config.Validation{},
+		},
+		DestinationConfigKeyJSONPathFallback: {
+			Default:     "key",
+			Description: "KeyJSONPathFallback defines what happens if the key can't be extracted\nwith KeyJSONPath, because the payload is not JSON or has no value at\nthe path. With \"key\" the record key is used, with \"empty\" the message\nis produced without a key and with \"fail\" the write fails.",
+			Type:        config.ParameterTypeString,
+			Validations: []config.Validation{
+				config.ValidationInclusion{List: []string{"key", "empty", "fail"}},
+			},
+		},
+		DestinationConfigLargeMessageThreshold: {
+			Default:     "",
+			Description: "LargeMessageThreshold is the payload size in bytes above which messages\nare routed to LargeMessageTopic.",
+			Type:        config.ParameterTypeInt,
+			Validations: []config.Validation{
+				config.ValidationGreaterThan{V: -1},
+			},
+		},
+		DestinationConfigLargeMessageTopic: {
+			Default:     "",
+			Description: "LargeMessageTopic is the topic messages with a payload larger than\nLargeMessageThreshold are routed to, e.g. for specialized handling.\nSmaller messages are produced to Topic.",
+			Type:        config.ParameterTypeString,
+			Validations: []config.Validation{},
+		},
+		DestinationConfigListenerName: {
+			Default:     "",
+			Description: "ListenerName selects the advertised listener of the brokers the client\nconnects to, for clusters that advertise multiple listeners, e.g. for\ninternal and external networks. Uses the default listener if empty.",
+			Type:        config.ParameterTypeString,
+			Validations: []config.Validation{},
+		},
+		DestinationConfigLogProduceResults: {
+			Default:     "",
+			Description: "LogProduceResults logs the message ID assigned by the broker for each\nconfirmed message and the error for each failed message.",
+			Type:        config.ParameterTypeBool,
+			Validations: []config.Validation{},
+		},
+		DestinationConfigLogProduceResultsSampleRate: {
+			Default:     "1",
+			Description: "LogProduceResultsSampleRate logs only every n-th confirmed message when\nLogProduceResults is enabled. Failed messages are always logged.",
+			Type:        config.ParameterTypeInt,
+			Validations: []config.Validation{
+				config.ValidationGreaterThan{V: 0},
+			},
+		},
+		DestinationConfigLookupTimeout: {
+			Default:     "",
+			Description: "LookupTimeout bounds looking up the topic before subscribing to it or\nproducing to it, independently of OperationTimeout. Disabled when set\nto 0.",
+			Type:        config.ParameterTypeDuration,
+			Validations: []config.Validation{},
+		},
+		DestinationConfigMaxBackoff: {
+			Default:     "60s",
+			Description: "MaxBackoff is the upper bound of the exponential backoff between\nattempts to reconnect a consumer or producer to a broker.",
+			Type:        config.ParameterTypeDuration,
+			Validations: []config.Validation{},
+		},
 		DestinationConfigMaxConnectionsPerBroker: {
 			Default:     "",
 			Description: "MaxConnectionsPerBroker limits the number of connections to each broker.",
 			Type:        config.ParameterTypeInt,
 			Validations: []config.Validation{},
 		},
+		DestinationConfigMaxPartitions: {
+			Default:     "",
+			Description: "MaxPartitions is the number of partitions the topic is grown to at most\nwhen AutoGrowPartitions is enabled.",
+			Type:        config.ParameterTypeInt,
+			Validations: []config.Validation{
+				config.ValidationGreaterThan{V: -1},
+			},
+		},
+		DestinationConfigMaxPendingMessages: {
+			Default:     "",
+			Description: "MaxPendingMessages bounds the number of messages the producer has sent\nasynchronously and the broker has not confirmed yet. Uses the default\nof the client (1000) when set to 0.",
+			Type:        config.ParameterTypeInt,
+			Validations: []config.Validation{
+				config.ValidationGreaterThan{V: -1},
+			},
+		},
 		DestinationConfigMemoryLimitBytes: {
 			Default:     "",
 			Description: "MemoryLimitBytes sets the memory limit for the client in bytes.\nIf the limit is exceeded, the client may start to block or fail operations.",
 			Type:        config.ParameterTypeInt,
 			Validations: []config.Validation{},
 		},
+		DestinationConfigNullValueMarker: {
+			Default:     "",
+			Description: "NullValueMarker is a hex encoded byte sequence that is sent as the\nmessage payload instead of an empty payload when a record has no data\n(e.g. \"00\"). If empty, records are sent unchanged.",
+			Type:        config.ParameterTypeString,
+			Validations: []config.Validation{},
+		},
+		DestinationConfigOauth2Audience: {
+			Default:     "",
+			Description: "OAuth2Audience is the audience the access token is requested for,\nusually the URL of the Pulsar cluster.",
+			Type:        config.ParameterTypeString,
+			Validations: []config.Validation{},
+		},
+		DestinationConfigOauth2ClientID: {
+			Default:     "",
+			Description: "OAuth2ClientID is the OAuth2 client ID, required with\nOAuth2ClientSecret.",
+			Type:        config.ParameterTypeString,
+			Validations: []config.Validation{},
+		},
+		DestinationConfigOauth2ClientSecret: {
+			Default:     "",
+			Description: "OAuth2ClientSecret is the OAuth2 client secret. Can't be combined with\nOAuth2PrivateKeyFile.",
+			Type:        config.ParameterTypeString,
+			Validations: []config.Validation{},
+		},
+		DestinationConfigOauth2IssuerURL: {
+			Default:     "",
+			Description: "OAuth2IssuerURL is the URL of the OAuth2 authorization server. If set,\nthe connector authenticates with the OAuth2 client credentials flow\ninstead of a TLS certificate.",
+			Type:        config.ParameterTypeString,
+			Validations: []config.Validation{},
+		},
+		DestinationConfigOauth2PrivateKeyFile: {
+			Default:     "",
+			Description: "OAuth2PrivateKeyFile is the path to a JSON key file containing the\nclient credentials, as provided by the authorization server. Can't be\ncombined with OAuth2ClientSecret.",
+			Type:        config.ParameterTypeString,
+			Validations: []config.Validation{},
+		},
+		DestinationConfigOauth2Scope: {
+			Default:     "",
+			Description: "OAuth2Scope is the scope of the requested access token.",
+			Type:        config.ParameterTypeString,
+			Validations: []config.Validation{},
+		},
 		DestinationConfigOperationTimeout: {
 			Default:     "",
 			Description: "OperationTimeout is the duration after which an operation is considered\nto have timed out.",
 			Type:        config.ParameterTypeDuration,
 			Validations: []config.Validation{},
 		},
+		DestinationConfigOrderingGuarantee: {
+			Default:     "key",
+			Description: "OrderingGuarantee defines how messages are routed to the partitions of\nthe topic. With \"none\" messages are spread across all partitions, with\n\"partition\" all messages go to a single partition and with \"key\"\nmessages with the same key go to the same partition.",
+			Type:        config.ParameterTypeString,
+			Validations: []config.Validation{
+				config.ValidationInclusion{List: []string{"none", "partition", "key"}},
+			},
+		},
+		DestinationConfigOrderingKeyField: {
+			Default:     "",
+			Description: "OrderingKeyField references the record field used as the ordering key\nof the message, e.g. \".Key\" to keep ordering by the original key while\nrouting by KeyField. Same format as KeyField. Defaults to the\n\"pulsar.orderingKey\" metadata set by the source.",
+			Type:        config.ParameterTypeString,
+			Validations: []config.Validation{},
+		},
+		DestinationConfigPartitionByField: {
+			Default:     "",
+			Description: "PartitionByField is a JSONPath expression selecting a field in the JSON\npayload of the record, e.g. `$.customer.id`. Messages are routed to the\npartition computed from a hash of the field value instead of the key,\nso records with the same value land on the same partition. The value\nis added to the \"conduit.partitionKey\" property and the write fails if\nthe payload has no value at the path.",
+			Type:        config.ParameterTypeString,
+			Validations: []config.Validation{},
+		},
+		DestinationConfigPriorityMetadataKey: {
+			Default:     "",
+			Description: "PriorityMetadataKey is the metadata key containing the integer priority\nof a record. Records in a batch are produced in order of their priority,\nhighest first. Records with the same key keep their order. Pulsar has\nno native message priority, so records written in different batches\nare not reordered. Requires an OrderingGuarantee other than \"key\" and\ncan't be combined with tracking sequence IDs.",
+			Type:        config.ParameterTypeString,
+			Validations: []config.Validation{},
+		},
+		DestinationConfigProduceAckTimeout: {
+			Default:     "",
+			Description: "ProduceAckTimeout bounds how long the destination waits for the broker\nto confirm a sent message before treating the send as failed. It is\nindependent of the send timeout of the producer and of\nOperationTimeout. Disabled when set to 0.",
+			Type:        config.ParameterTypeDuration,
+			Validations: []config.Validation{},
+		},
+		DestinationConfigProduceMaxRetries: {
+			Default:     "",
+			Description: "ProduceMaxRetries is the number of times sending a message is retried\nwhen it fails with one of ProduceRetryableErrors. Retries are disabled\nby default.",
+			Type:        config.ParameterTypeInt,
+			Validations: []config.Validation{
+				config.ValidationGreaterThan{V: -1},
+			},
+		},
+		DestinationConfigProduceRetryBackoff: {
+			Default:     "1s",
+			Description: "ProduceRetryBackoff is the delay before the first retry, it is doubled\nafter each failed attempt up to one minute.",
+			Type:        config.ParameterTypeDuration,
+			Validations: []config.Validation{},
+		},
+		DestinationConfigProduceRetryableErrors: {
+			Default:     "timeout,producerBusy",
+			Description: "ProduceRetryableErrors is a comma separated list of error codes for\nwhich sending a message is retried, all other errors fail the write\nright away. Supported codes are \"timeout\", \"producerBusy\",\n\"producerQueueFull\", \"memoryBufferFull\", \"messageTooBig\",\n\"topicTerminated\", \"schemaIncompatible\" and \"producerFenced\".",
+			Type:        config.ParameterTypeString,
+			Validations: []config.Validation{},
+		},
+		DestinationConfigProducerAccessMode: {
+			Default:     "shared",
+			Description: "ProducerAccessMode defines whether other producers can produce to the\ntopic at the same time. With \"shared\" multiple producers are allowed,\nwith \"exclusive\" opening the destination fails if another producer is\nconnected and with \"waitForExclusive\" the destination waits until it\ncan acquire exclusive access.",
+			Type:        config.ParameterTypeString,
+			Validations: []config.Validation{
+				config.ValidationInclusion{List: []string{"shared", "exclusive", "waitForExclusive"}},
+			},
+		},
+		DestinationConfigProducerName: {
+			Default:     "",
+			Description: "ProducerName is the name of the producer. The broker deduplicates\nmessages by producer name and sequence ID when deduplication is enabled\non the topic, the name must therefore be unique for the topic and stable\nacross restarts. Generated by the broker if empty.",
+			Type:        config.ParameterTypeString,
+			Validations: []config.Validation{},
+		},
+		DestinationConfigSchemaRegistryMaxRetries: {
+			Default:     "",
+			Description: "SchemaRegistryMaxRetries is the number of times creating the consumer or\nproducer is retried when it fails, e.g. because the schema registry is\ntemporarily unavailable. Permanent errors like an invalid configuration\nor a closed producer aren't retried. Retries are disabled by default.",
+			Type:        config.ParameterTypeInt,
+			Validations: []config.Validation{
+				config.ValidationGreaterThan{V: -1},
+			},
+		},
+		DestinationConfigSchemaRegistryRetryBackoff: {
+			Default:     "1s",
+			Description: "SchemaRegistryRetryBackoff is the delay before the first retry, it is\ndoubled after each failed attempt up to one minute.",
+			Type:        config.ParameterTypeDuration,
+			Validations: []config.Validation{},
+		},
+		DestinationConfigSendTimeout: {
+			Default:     "",
+			Description: "SendTimeout is the time after which the producer fails a message the\nbroker didn't confirm. The producer stays usable after a timeout, the\nsend can be retried by listing \"timeout\" in ProduceRetryableErrors.\nDisabled when set to 0.",
+			Type:        config.ParameterTypeDuration,
+			Validations: []config.Validation{},
+		},
+		DestinationConfigSequenceIDField: {
+			Default:     "",
+			Description: "SequenceIDField references the record field the sequence ID of the\nmessage is derived from, e.g. the log sequence number of a change data\ncapture source. Its value must be an integer that increases with every\nrecord. Replaying the same records produces the same sequence IDs, so\nthey are deduplicated by the broker if EnableTopicDeduplication is set.\nSame format as KeyField. Requires ProducerName and can't be combined\nwith SequenceStorePath. A warning is logged when a sequence ID doesn't\nincrease compared to the previous message of the producer.",
+			Type:        config.ParameterTypeString,
+			Validations: []config.Validation{},
+		},
+		DestinationConfigSequenceStorePath: {
+			Default:     "",
+			Description: "SequenceStorePath is the directory the sequence ID of the last message\nconfirmed by the broker is stored in. Messages are produced with\nconsecutive sequence IDs continuing from the stored one, so records\nwritten again after a restart are deduplicated by the broker if\nEnableTopicDeduplication is set. Requires ProducerName and can't be\ncombined with a topic template, LargeMessageTopic or the write buffer.",
+			Type:        config.ParameterTypeString,
+			Validations: []config.Validation{},
+		},
 		DestinationConfigTlsAllowInsecureConnection: {
 			Default:     "",
 			Description: "TLSAllowInsecureConnection configures whether the internal Pulsar client accepts untrusted TLS certificate from broker (default: false)",
@@ -91,14 +618,50 @@ func (DestinationConfig) Parameters() map[string]config.Parameter {
 			Type:        config.ParameterTypeBool,
 			Validations: []config.Validation{},
 		},
+		DestinationConfigToken: {
+			Default:     "",
+			Description: "Token is a JWT token the connector authenticates with. Can't be\ncombined with TokenFilePath.",
+			Type:        config.ParameterTypeString,
+			Validations: []config.Validation{},
+		},
+		DestinationConfigTokenFilePath: {
+			Default:     "",
+			Description: "TokenFilePath is the path to a file containing a JWT token the connector\nauthenticates with. The file is read again whenever a token is needed,\nso it can be rotated. Can't be combined with Token.",
+			Type:        config.ParameterTypeString,
+			Validations: []config.Validation{},
+		},
 		DestinationConfigTopic: {
 			Default:     "",
-			Description: "Topic specifies the Pulsar topic used by the connector.",
+			Description: "Topic specifies the Pulsar topic used by the connector. In the\ndestination it can contain a Go template that is executed with the\nrecord to determine the topic, e.g.\n`events-{{index .Metadata \"tenant\"}}`. A producer is created for each\nresolved topic when the first record is written to it. Required in the\ndestination, the source can be configured with Topics instead.",
+			Type:        config.ParameterTypeString,
+			Validations: []config.Validation{},
+		},
+		DestinationConfigTopicNotFoundPolicy: {
+			Default:     "error",
+			Description: "TopicNotFoundPolicy defines what happens when the topic is deleted while\nproducing to it. With \"error\" the write fails, with \"recreate\" the topic\nis created using the admin API with the partitions it had and with\n\"buffer\" the destination waits until the topic exists again. The\nmessage is resent in both cases.",
 			Type:        config.ParameterTypeString,
 			Validations: []config.Validation{
-				config.ValidationRequired{},
+				config.ValidationInclusion{List: []string{"error", "recreate", "buffer"}},
 			},
 		},
+		DestinationConfigTopicNotFoundRetryInterval: {
+			Default:     "1s",
+			Description: "TopicNotFoundRetryInterval is the delay between attempts to reconnect\nto the topic when TopicNotFoundPolicy is \"buffer\".",
+			Type:        config.ParameterTypeDuration,
+			Validations: []config.Validation{},
+		},
+		DestinationConfigTransactionTimeout: {
+			Default:     "1m",
+			Description: "TransactionTimeout is the time after which the broker aborts a\ntransaction that was not committed.",
+			Type:        config.ParameterTypeDuration,
+			Validations: []config.Validation{},
+		},
+		DestinationConfigTransactionalWrites: {
+			Default:     "",
+			Description: "TransactionalWrites produces the records of each batch in a single\ntransaction, which is committed once all messages are sent, or aborted\nif any of them fails. The transaction spans all topics the batch is\nproduced to, including resolved topic templates and LargeMessageTopic.\nRequires EnableTransaction and can't be combined with the write\nbuffer, IdempotencyKeyField or tracking sequence IDs.",
+			Type:        config.ParameterTypeBool,
+			Validations: []config.Validation{},
+		},
 		DestinationConfigUrl: {
 			Default:     "",
 			Description: "URL of the Pulsar instance to connect to.",
@@ -107,5 +670,33 @@ func (DestinationConfig) Parameters() map[string]config.Parameter {
 				config.ValidationRequired{},
 			},
 		},
+		DestinationConfigValidateBeforeSend: {
+			Default:     "",
+			Description: "ValidateBeforeSend validates the serialized records against the Avro or\nJSON schema of the topic before producing them, so records that\nconsumers can't read with the schema are rejected locally. Requires\nAdminURL and can't be combined with CompressionDictionary.",
+			Type:        config.ParameterTypeBool,
+			Validations: []config.Validation{},
+		},
+		DestinationConfigWriteBufferFlushTimeout: {
+			Default:     "",
+			Description: "WriteBufferFlushTimeout is the maximum time records are sent\nasynchronously before the destination flushes the producer. Disabled\nwhen set to 0. The buffer is always flushed at the end of a write, so\nrecords are never held back between writes. Buffered records are not\nretried, so the write buffer can't be combined with ProduceMaxRetries,\nBacklogQuotaMaxRetries or CircuitBreakerThreshold.",
+			Type:        config.ParameterTypeDuration,
+			Validations: []config.Validation{},
+		},
+		DestinationConfigWriteBufferMaxBytes: {
+			Default:     "",
+			Description: "WriteBufferMaxBytes is the total payload size in bytes of the records\nsent asynchronously before the destination flushes the producer.\nDisabled when set to 0.",
+			Type:        config.ParameterTypeInt,
+			Validations: []config.Validation{
+				config.ValidationGreaterThan{V: -1},
+			},
+		},
+		DestinationConfigWriteBufferMaxRecords: {
+			Default:     "",
+			Description: "WriteBufferMaxRecords is the number of records sent asynchronously\nbefore the destination flushes the producer and waits for the broker to\nconfirm them. This is independent of the batching of the producer.\nDisabled when set to 0.",
+			Type:        config.ParameterTypeInt,
+			Validations: []config.Validation{
+				config.ValidationGreaterThan{V: -1},
+			},
+		},
 	}
 }
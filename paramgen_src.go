@@ -8,66 +8,642 @@ import (
 )
 
 const (
-	SourceConfigConnectionTimeout          = "connectionTimeout"
-	SourceConfigDisableLogging             = "disableLogging"
-	SourceConfigEnableTransaction          = "enableTransaction"
-	SourceConfigMaxConnectionsPerBroker    = "maxConnectionsPerBroker"
-	SourceConfigMemoryLimitBytes           = "memoryLimitBytes"
-	SourceConfigOperationTimeout           = "operationTimeout"
-	SourceConfigSubscriptionName           = "subscriptionName"
-	SourceConfigTlsAllowInsecureConnection = "tlsAllowInsecureConnection"
-	SourceConfigTlsCertificateFile         = "tlsCertificateFile"
-	SourceConfigTlsKeyFilePath             = "tlsKeyFilePath"
-	SourceConfigTlsTrustCertsFilePath      = "tlsTrustCertsFilePath"
-	SourceConfigTlsValidateHostname        = "tlsValidateHostname"
-	SourceConfigTopic                      = "topic"
-	SourceConfigUrl                        = "url"
+	SourceConfigAckFlushBytes                             = "ackFlushBytes"
+	SourceConfigAckFlushCount                             = "ackFlushCount"
+	SourceConfigAckFlushInterval                          = "ackFlushInterval"
+	SourceConfigAckLatencyMetrics                         = "ackLatencyMetrics"
+	SourceConfigAckMode                                   = "ackMode"
+	SourceConfigAckTimeout                                = "ackTimeout"
+	SourceConfigAckTimeoutTickDuration                    = "ackTimeoutTickDuration"
+	SourceConfigAdaptivePrefetch                          = "adaptivePrefetch"
+	SourceConfigAdaptivePrefetchMax                       = "adaptivePrefetchMax"
+	SourceConfigAdaptivePrefetchMin                       = "adaptivePrefetchMin"
+	SourceConfigAdaptivePrefetchTargetLatency             = "adaptivePrefetchTargetLatency"
+	SourceConfigAdminURL                                  = "adminURL"
+	SourceConfigAutoDecompressPayload                     = "autoDecompressPayload"
+	SourceConfigAutoDiscoveryPeriod                       = "autoDiscoveryPeriod"
+	SourceConfigAutoScaleReceiverQueue                    = "autoScaleReceiverQueue"
+	SourceConfigAutoScaleReceiverQueueMaxSize             = "autoScaleReceiverQueueMaxSize"
+	SourceConfigAutoScaleReceiverQueueMinSize             = "autoScaleReceiverQueueMinSize"
+	SourceConfigConnectionTimeout                         = "connectionTimeout"
+	SourceConfigConsumerName                              = "consumerName"
+	SourceConfigDisableLogging                            = "disableLogging"
+	SourceConfigDlqDiagnosticProperties                   = "dlqDiagnosticProperties"
+	SourceConfigDlqFailurePolicy                          = "dlqFailurePolicy"
+	SourceConfigDlqFailureTopics                          = "dlqFailureTopics"
+	SourceConfigDlqMaxDeliveries                          = "dlqMaxDeliveries"
+	SourceConfigDlqSchemaDefinition                       = "dlqSchemaDefinition"
+	SourceConfigDlqTopic                                  = "dlqTopic"
+	SourceConfigEmitWatermarks                            = "emitWatermarks"
+	SourceConfigEnableBatchIndexAck                       = "enableBatchIndexAck"
+	SourceConfigEnableRetry                               = "enableRetry"
+	SourceConfigEnableTransaction                         = "enableTransaction"
+	SourceConfigEventTimeFrom                             = "eventTimeFrom"
+	SourceConfigEventTimeTo                               = "eventTimeTo"
+	SourceConfigExpireTimeOfIncompleteChunk               = "expireTimeOfIncompleteChunk"
+	SourceConfigFlushAcksOnCommit                         = "flushAcksOnCommit"
+	SourceConfigGlobalOrderingWindow                      = "globalOrderingWindow"
+	SourceConfigInferPayloadType                          = "inferPayloadType"
+	SourceConfigJsonSchemaValidation                      = "jsonSchemaValidation"
+	SourceConfigKeepAliveInterval                         = "keepAliveInterval"
+	SourceConfigListenerName                              = "listenerName"
+	SourceConfigLookupTimeout                             = "lookupTimeout"
+	SourceConfigMaxBackoff                                = "maxBackoff"
+	SourceConfigMaxConnectionsPerBroker                   = "maxConnectionsPerBroker"
+	SourceConfigMaxPendingChunkedMessage                  = "maxPendingChunkedMessage"
+	SourceConfigMaxReassembledSize                        = "maxReassembledSize"
+	SourceConfigMaxTotalReceiverQueueSizeAcrossPartitions = "maxTotalReceiverQueueSizeAcrossPartitions"
+	SourceConfigMeasureLag                                = "measureLag"
+	SourceConfigMemoryLimitBytes                          = "memoryLimitBytes"
+	SourceConfigMessageListenerMode                       = "messageListenerMode"
+	SourceConfigMetricsAddress                            = "metricsAddress"
+	SourceConfigMetricsCardinality                        = "metricsCardinality"
+	SourceConfigNackInFlightOnShutdown                    = "nackInFlightOnShutdown"
+	SourceConfigNackRedeliveryDelay                       = "nackRedeliveryDelay"
+	SourceConfigNotifySchemaChange                        = "notifySchemaChange"
+	SourceConfigOauth2Audience                            = "oauth2Audience"
+	SourceConfigOauth2ClientID                            = "oauth2ClientID"
+	SourceConfigOauth2ClientSecret                        = "oauth2ClientSecret"
+	SourceConfigOauth2IssuerURL                           = "oauth2IssuerURL"
+	SourceConfigOauth2PrivateKeyFile                      = "oauth2PrivateKeyFile"
+	SourceConfigOauth2Scope                               = "oauth2Scope"
+	SourceConfigOperationTimeout                          = "operationTimeout"
+	SourceConfigPartitionCheckInterval                    = "partitionCheckInterval"
+	SourceConfigPinnedSchemaVersion                       = "pinnedSchemaVersion"
+	SourceConfigPreserveEncryptionContext                 = "preserveEncryptionContext"
+	SourceConfigProcessingDeadline                        = "processingDeadline"
+	SourceConfigReadBatchSize                             = "readBatchSize"
+	SourceConfigReadCompacted                             = "readCompacted"
+	SourceConfigReaderMessageLimit                        = "readerMessageLimit"
+	SourceConfigReaderStartMessageID                      = "readerStartMessageID"
+	SourceConfigReceiverQueueSize                         = "receiverQueueSize"
+	SourceConfigResetSubscription                         = "resetSubscription"
+	SourceConfigRetryDelay                                = "retryDelay"
+	SourceConfigRetryDelayMultiplier                      = "retryDelayMultiplier"
+	SourceConfigRetryLetterTopic                          = "retryLetterTopic"
+	SourceConfigSchemaDefinition                          = "schemaDefinition"
+	SourceConfigSchemaIncompatibilityAction               = "schemaIncompatibilityAction"
+	SourceConfigSchemaRegistryMaxRetries                  = "schemaRegistryMaxRetries"
+	SourceConfigSchemaRegistryRetryBackoff                = "schemaRegistryRetryBackoff"
+	SourceConfigSchemaType                                = "schemaType"
+	SourceConfigStartFromTimestamp                        = "startFromTimestamp"
+	SourceConfigSubscribeTimeout                          = "subscribeTimeout"
+	SourceConfigSubscriptionInitialPosition               = "subscriptionInitialPosition"
+	SourceConfigSubscriptionName                          = "subscriptionName"
+	SourceConfigSubscriptionType                          = "subscriptionType"
+	SourceConfigTeardownTimeout                           = "teardownTimeout"
+	SourceConfigTlsAllowInsecureConnection                = "tlsAllowInsecureConnection"
+	SourceConfigTlsCertificateFile                        = "tlsCertificateFile"
+	SourceConfigTlsKeyFilePath                            = "tlsKeyFilePath"
+	SourceConfigTlsTrustCertsFilePath                     = "tlsTrustCertsFilePath"
+	SourceConfigTlsValidateHostname                       = "tlsValidateHostname"
+	SourceConfigToken                                     = "token"
+	SourceConfigTokenFilePath                             = "tokenFilePath"
+	SourceConfigTopic                                     = "topic"
+	SourceConfigTopics                                    = "topics"
+	SourceConfigTopicsPattern                             = "topicsPattern"
+	SourceConfigUrl                                       = "url"
+	SourceConfigWatermarkInterval                         = "watermarkInterval"
 )
 
 func (SourceConfig) Parameters() map[string]config.Parameter {
 	return map[string]config.Parameter{
+		SourceConfigAckFlushBytes: {
+			Default:     "",
+			Description: "AckFlushBytes is the total payload size in bytes of the acknowledged\nmessages at which the batched acknowledgements are sent to the broker,\nwhich keeps the amount of redelivered data bounded when message sizes\nvary widely. Replaces the acknowledgement grouping of the client.\nDisabled when set to 0.",
+			Type:        config.ParameterTypeInt,
+			Validations: []config.Validation{
+				config.ValidationGreaterThan{V: -1},
+			},
+		},
+		SourceConfigAckFlushCount: {
+			Default:     "",
+			Description: "AckFlushCount is the number of acknowledgements the source batches\nbefore sending them to the broker. Replaces the acknowledgement\ngrouping of the client. Disabled when set to 0.",
+			Type:        config.ParameterTypeInt,
+			Validations: []config.Validation{
+				config.ValidationGreaterThan{V: -1},
+			},
+		},
+		SourceConfigAckFlushInterval: {
+			Default:     "100ms",
+			Description: "AckFlushInterval is the maximum time acknowledgements are batched when\nAckFlushCount or AckFlushBytes is set.",
+			Type:        config.ParameterTypeDuration,
+			Validations: []config.Validation{},
+		},
+		SourceConfigAckLatencyMetrics: {
+			Default:     "",
+			Description: "AckLatencyMetrics records the time between reading and acknowledging\neach record in the \"conduit_pulsar_source_ack_latency_seconds\"\nPrometheus histogram, which helps diagnose slow downstream processing.\nThe histogram is exposed together with the metrics of the Pulsar client.",
+			Type:        config.ParameterTypeBool,
+			Validations: []config.Validation{},
+		},
+		SourceConfigAckMode: {
+			Default:     "individual",
+			Description: "AckMode defines how records are acknowledged. \"individual\" acknowledges\neach message, \"cumulative\" acknowledges a message and all messages\nbefore it in the same partition, which is more efficient. Cumulative\nacknowledgements are only supported by \"exclusive\" and \"failover\"\nsubscriptions and can't be combined with options that redeliver\nindividual messages.",
+			Type:        config.ParameterTypeString,
+			Validations: []config.Validation{
+				config.ValidationInclusion{List: []string{"individual", "cumulative"}},
+			},
+		},
+		SourceConfigAckTimeout: {
+			Default:     "",
+			Description: "AckTimeout is the time after which messages that were not acknowledged\nare redelivered, e.g. because processing got stuck. The Go client has\nno ack timeout, so it is enforced by the source like\nProcessingDeadline, which it can't be combined with. Messages of a\nsource that died are redelivered by the broker once the consumer\ndisconnects. Disabled when set to 0.",
+			Type:        config.ParameterTypeDuration,
+			Validations: []config.Validation{},
+		},
+		SourceConfigAckTimeoutTickDuration: {
+			Default:     "",
+			Description: "AckTimeoutTickDuration is how often messages are checked for an\nexpired AckTimeout. Defaults to a quarter of AckTimeout when set to 0.",
+			Type:        config.ParameterTypeDuration,
+			Validations: []config.Validation{},
+		},
+		SourceConfigAdaptivePrefetch: {
+			Default:     "",
+			Description: "AdaptivePrefetch bounds the number of records that were read but not\nyet acknowledged. The bound starts at AdaptivePrefetchMin and grows\nwhile records are acknowledged within AdaptivePrefetchTargetLatency, up\nto AdaptivePrefetchMax, and shrinks when acknowledgements are slower.\nCan't be combined with AutoScaleReceiverQueue.",
+			Type:        config.ParameterTypeBool,
+			Validations: []config.Validation{},
+		},
+		SourceConfigAdaptivePrefetchMax: {
+			Default:     "1000",
+			Description: "AdaptivePrefetchMax is the upper bound of unacknowledged records, it is\nalso used as the size of the receive queue of the consumer.",
+			Type:        config.ParameterTypeInt,
+			Validations: []config.Validation{},
+		},
+		SourceConfigAdaptivePrefetchMin: {
+			Default:     "10",
+			Description: "AdaptivePrefetchMin is the lower bound of unacknowledged records.",
+			Type:        config.ParameterTypeInt,
+			Validations: []config.Validation{},
+		},
+		SourceConfigAdaptivePrefetchTargetLatency: {
+			Default:     "1s",
+			Description: "AdaptivePrefetchTargetLatency is the time within which records need to\nbe acknowledged for the bound of unacknowledged records to grow.",
+			Type:        config.ParameterTypeDuration,
+			Validations: []config.Validation{},
+		},
+		SourceConfigAdminURL: {
+			Default:     "",
+			Description: "AdminURL is the URL of the Pulsar admin (web service) API. It is only\nneeded by options that manage topic policies.",
+			Type:        config.ParameterTypeString,
+			Validations: []config.Validation{},
+		},
+		SourceConfigAutoDecompressPayload: {
+			Default:     "",
+			Description: "AutoDecompressPayload detects payloads compressed with gzip or zstd by\nthe producing application and decompresses them. The detected encoding\nis stored in the \"pulsar.contentEncoding\" metadata. Payloads that can't\nbe decompressed are returned unchanged.",
+			Type:        config.ParameterTypeBool,
+			Validations: []config.Validation{},
+		},
+		SourceConfigAutoDiscoveryPeriod: {
+			Default:     "1m",
+			Description: "AutoDiscoveryPeriod is how often topics matching TopicsPattern are\ndiscovered, so newly created topics are consumed.",
+			Type:        config.ParameterTypeDuration,
+			Validations: []config.Validation{},
+		},
+		SourceConfigAutoScaleReceiverQueue: {
+			Default:     "",
+			Description: "AutoScaleReceiverQueue enables scaling the receive queue based on the\nobserved consumption rate. Read buffers the messages that are already\navailable up to the queue size, which doubles when the buffer is filled\ncompletely and halves when less than half of it is used. The size starts\nat AutoScaleReceiverQueueMinSize and stays within the bounds. Can't be\ncombined with ReadBatchSize or MessageListenerMode.",
+			Type:        config.ParameterTypeBool,
+			Validations: []config.Validation{},
+		},
+		SourceConfigAutoScaleReceiverQueueMaxSize: {
+			Default:     "1000",
+			Description: "AutoScaleReceiverQueueMaxSize is the upper bound of the receive queue\nwhen AutoScaleReceiverQueue is enabled.",
+			Type:        config.ParameterTypeInt,
+			Validations: []config.Validation{
+				config.ValidationGreaterThan{V: 0},
+			},
+		},
+		SourceConfigAutoScaleReceiverQueueMinSize: {
+			Default:     "1",
+			Description: "AutoScaleReceiverQueueMinSize is the lower bound of the receive queue\nwhen AutoScaleReceiverQueue is enabled.",
+			Type:        config.ParameterTypeInt,
+			Validations: []config.Validation{
+				config.ValidationGreaterThan{V: 0},
+			},
+		},
 		SourceConfigConnectionTimeout: {
 			Default:     "",
 			Description: "ConnectionTimeout specifies the duration for which the client will\nattempt to establish a connection before timing out.",
 			Type:        config.ParameterTypeDuration,
 			Validations: []config.Validation{},
 		},
+		SourceConfigConsumerName: {
+			Default:     "",
+			Description: "ConsumerName is the name of the consumer, shown in the stats of the\ntopic and the admin UI. Generated by the client if empty.",
+			Type:        config.ParameterTypeString,
+			Validations: []config.Validation{},
+		},
 		SourceConfigDisableLogging: {
 			Default:     "",
 			Description: "DisableLogging disables pulsar client logs",
 			Type:        config.ParameterTypeBool,
 			Validations: []config.Validation{},
 		},
+		SourceConfigDlqDiagnosticProperties: {
+			Default:     "",
+			Description: "DLQDiagnosticProperties adds the original topic, failure reason and\nredelivery count as properties to messages routed to the dead letter\ntopic.",
+			Type:        config.ParameterTypeBool,
+			Validations: []config.Validation{},
+		},
+		SourceConfigDlqFailurePolicy: {
+			Default:     "block",
+			Description: "DLQFailurePolicy defines what happens when the dead letter topic is\nunavailable when the source is opened. With \"block\" routing is retried\nuntil it succeeds, with \"drop\" messages that exceeded DLQMaxDeliveries\nare acknowledged and logged, and with \"fail\" the source fails to open.",
+			Type:        config.ParameterTypeString,
+			Validations: []config.Validation{
+				config.ValidationInclusion{List: []string{"block", "drop", "fail"}},
+			},
+		},
+		SourceConfigDlqFailureTopics: {
+			Default:     "",
+			Description: "DLQFailureTopics routes rejected messages to a dead letter topic per\nfailure type, in the format \"type:topic\". The failure types are\n\"deserialization\" for payloads that are not valid JSON, \"schema\" for\nmessages that don't match the pinned schema version or JSON schema,\nand \"processing\" for messages that exceeded DLQMaxDeliveries, which\nreplaces DLQTopic. Messages are routed as soon as they are rejected.",
+			Type:        config.ParameterTypeString,
+			Validations: []config.Validation{},
+		},
+		SourceConfigDlqMaxDeliveries: {
+			Default:     "",
+			Description: "DLQMaxDeliveries is the maximum number of times a message is delivered\nbefore it is routed to the dead letter topic. Negatively acknowledged\nmessages are redelivered after the nack redelivery delay and count as a\ndelivery. Dead letter routing is disabled when set to 0.",
+			Type:        config.ParameterTypeInt,
+			Validations: []config.Validation{
+				config.ValidationGreaterThan{V: -1},
+			},
+		},
+		SourceConfigDlqSchemaDefinition: {
+			Default:     "",
+			Description: "DLQSchemaDefinition is an Avro record schema used to wrap messages\nrouted to the dead letter topic. The record must contain a \"payload\"\nfield of type bytes, which is set to the original payload. The optional\nfields \"key\", \"orderingKey\", \"originalTopic\", \"failureReason\",\n\"redeliveryCount\" and \"properties\" are set to the failure metadata,\nother fields must have a default. The schema is registered on the dead\nletter topic.",
+			Type:        config.ParameterTypeString,
+			Validations: []config.Validation{},
+		},
+		SourceConfigDlqTopic: {
+			Default:     "",
+			Description: "DLQTopic is the name of the topic where messages that exceeded\nDLQMaxDeliveries are routed to. Defaults to\n\"<topic>-<subscriptionName>-DLQ\" when a single topic is consumed.",
+			Type:        config.ParameterTypeString,
+			Validations: []config.Validation{},
+		},
+		SourceConfigEmitWatermarks: {
+			Default:     "",
+			Description: "EmitWatermarks emits watermark records carrying the minimum of the\nlatest event times seen across partitions in the \"pulsar.watermark\"\nmetadata field, enabling downstream windowing. Watermark records have\nno key and payload and don't need to be acknowledged.",
+			Type:        config.ParameterTypeBool,
+			Validations: []config.Validation{},
+		},
+		SourceConfigEnableBatchIndexAck: {
+			Default:     "",
+			Description: "EnableBatchIndexAck acknowledges individual messages of a batch on the\nbroker, so that only unacknowledged messages of a batch are redelivered.\nRequires acknowledgmentAtBatchIndexLevelEnabled on the broker.",
+			Type:        config.ParameterTypeBool,
+			Validations: []config.Validation{},
+		},
+		SourceConfigEnableRetry: {
+			Default:     "",
+			Description: "EnableRetry routes failed records to the retry letter topic, which the\nsource consumes as well, so they are retried after RetryDelay. Messages\nare routed to the dead letter topic once they were retried\nDLQMaxDeliveries times, or 16 times if it is not set.",
+			Type:        config.ParameterTypeBool,
+			Validations: []config.Validation{},
+		},
 		SourceConfigEnableTransaction: {
 			Default:     "",
 			Description: "EnableTransaction determines if the client should support transactions.",
 			Type:        config.ParameterTypeBool,
 			Validations: []config.Validation{},
 		},
+		SourceConfigEventTimeFrom: {
+			Default:     "",
+			Description: "EventTimeFrom is an RFC 3339 timestamp, messages with an earlier event\ntime are acknowledged and skipped. Messages without an event time are\nfiltered by their publish time.",
+			Type:        config.ParameterTypeString,
+			Validations: []config.Validation{},
+		},
+		SourceConfigEventTimeTo: {
+			Default:     "",
+			Description: "EventTimeTo is an RFC 3339 timestamp, messages with a later event time\nare acknowledged and skipped. Together with EventTimeFrom it bounds the\nreplayed time range.",
+			Type:        config.ParameterTypeString,
+			Validations: []config.Validation{},
+		},
+		SourceConfigExpireTimeOfIncompleteChunk: {
+			Default:     "1m",
+			Description: "ExpireTimeOfIncompleteChunk is the time after which an incomplete\nchunked message is discarded by the client.",
+			Type:        config.ParameterTypeDuration,
+			Validations: []config.Validation{},
+		},
+		SourceConfigFlushAcksOnCommit: {
+			Default:     "",
+			Description: "FlushAcksOnCommit sends each acknowledgement to the broker as soon as\nConduit commits the position of the record instead of grouping it with\nother acknowledgements, and waits for the broker's response. Errors\nreturned by the broker fail the ack. Messages of a batch are only\nacknowledged once the whole batch is acked, unless enableBatchIndexAck\nis set.",
+			Type:        config.ParameterTypeBool,
+			Validations: []config.Validation{},
+		},
+		SourceConfigGlobalOrderingWindow: {
+			Default:     "",
+			Description: "GlobalOrderingWindow buffers received messages for this long and emits\nthem in publish time order, approximating a global order across the\npartitions of a topic. Every message is delayed by up to the window and\nbuffered messages are kept in memory. Messages arriving later than the\nwindow after a newer message are still emitted out of order, and the\norder relies on the clocks of the producers being in sync. Disabled\nwhen set to 0.",
+			Type:        config.ParameterTypeDuration,
+			Validations: []config.Validation{},
+		},
+		SourceConfigInferPayloadType: {
+			Default:     "",
+			Description: "InferPayloadType detects whether the payload contains JSON, text or\nbinary data and sets the \"pulsar.contentType\" metadata accordingly.\nPayloads containing a JSON object are returned as structured data.",
+			Type:        config.ParameterTypeBool,
+			Validations: []config.Validation{},
+		},
+		SourceConfigJsonSchemaValidation: {
+			Default:     "",
+			Description: "JSONSchemaValidation is a JSON schema document, or the path of a file\ncontaining it, that the payloads of consumed messages are validated\nagainst, after decompressing them if AutoDecompressPayload is set.\nMessages with invalid payloads are negatively acknowledged, so they\nare routed to the dead letter topic once DLQMaxDeliveries is exceeded.\nWithout a dead letter topic they are acknowledged and dropped.",
+			Type:        config.ParameterTypeString,
+			Validations: []config.Validation{},
+		},
+		SourceConfigKeepAliveInterval: {
+			Default:     "30s",
+			Description: "KeepAliveInterval is the interval in which the client pings the\nbrokers to keep idle connections alive.",
+			Type:        config.ParameterTypeDuration,
+			Validations: []config.Validation{},
+		},
+		SourceConfigListenerName: {
+			Default:     "",
+			Description: "ListenerName selects the advertised listener of the brokers the client\nconnects to, for clusters that advertise multiple listeners, e.g. for\ninternal and external networks. Uses the default listener if empty.",
+			Type:        config.ParameterTypeString,
+			Validations: []config.Validation{},
+		},
+		SourceConfigLookupTimeout: {
+			Default:     "",
+			Description: "LookupTimeout bounds looking up the topic before subscribing to it or\nproducing to it, independently of OperationTimeout. Disabled when set\nto 0.",
+			Type:        config.ParameterTypeDuration,
+			Validations: []config.Validation{},
+		},
+		SourceConfigMaxBackoff: {
+			Default:     "60s",
+			Description: "MaxBackoff is the upper bound of the exponential backoff between\nattempts to reconnect a consumer or producer to a broker.",
+			Type:        config.ParameterTypeDuration,
+			Validations: []config.Validation{},
+		},
 		SourceConfigMaxConnectionsPerBroker: {
 			Default:     "",
 			Description: "MaxConnectionsPerBroker limits the number of connections to each broker.",
 			Type:        config.ParameterTypeInt,
 			Validations: []config.Validation{},
 		},
+		SourceConfigMaxPendingChunkedMessage: {
+			Default:     "100",
+			Description: "MaxPendingChunkedMessage is the maximum number of chunked messages the\nclient reassembles at the same time. The oldest incomplete message is\ndiscarded when the limit is reached.",
+			Type:        config.ParameterTypeInt,
+			Validations: []config.Validation{},
+		},
+		SourceConfigMaxReassembledSize: {
+			Default:     "",
+			Description: "MaxReassembledSize is the maximum size in bytes of a message reassembled\nfrom chunks. Larger messages are logged, acknowledged and skipped. The\nlimit is checked once the client reassembled the message, it keeps\noversized records out of the pipeline. The memory used while chunks are\nreassembled is bounded by MaxPendingChunkedMessage and\nExpireTimeOfIncompleteChunk instead. Disabled when set to 0.",
+			Type:        config.ParameterTypeInt,
+			Validations: []config.Validation{
+				config.ValidationGreaterThan{V: -1},
+			},
+		},
+		SourceConfigMaxTotalReceiverQueueSizeAcrossPartitions: {
+			Default:     "",
+			Description: "MaxTotalReceiverQueueSizeAcrossPartitions is the number of messages the\nconsumer prefetches across all partitions of the consumed topics. The\nreceive queue of each partition is shrunk so their total stays within\nthe limit. Disabled when set to 0, can't be combined with\nTopicsPattern.",
+			Type:        config.ParameterTypeInt,
+			Validations: []config.Validation{},
+		},
+		SourceConfigMeasureLag: {
+			Default:     "",
+			Description: "MeasureLag enables logging the lag between the publish time of each\nmessage and the time it was received by the source.",
+			Type:        config.ParameterTypeBool,
+			Validations: []config.Validation{},
+		},
 		SourceConfigMemoryLimitBytes: {
 			Default:     "",
 			Description: "MemoryLimitBytes sets the memory limit for the client in bytes.\nIf the limit is exceeded, the client may start to block or fail operations.",
 			Type:        config.ParameterTypeInt,
 			Validations: []config.Validation{},
 		},
+		SourceConfigMessageListenerMode: {
+			Default:     "",
+			Description: "MessageListenerMode makes the consumer push messages into a channel\nthat is drained by Read, instead of polling the consumer on each Read.",
+			Type:        config.ParameterTypeBool,
+			Validations: []config.Validation{},
+		},
+		SourceConfigMetricsAddress: {
+			Default:     "",
+			Description: "MetricsAddress is the address, e.g. \":9090\", the metrics of the Pulsar\nclient and the source are served on under the \"/metrics\" path, so they\ncan be scraped by Prometheus. Disabled if empty.",
+			Type:        config.ParameterTypeString,
+			Validations: []config.Validation{},
+		},
+		SourceConfigMetricsCardinality: {
+			Default:     "namespace",
+			Description: "MetricsCardinality defines the labels of the Pulsar client metrics,\n\"none\", \"tenant\", \"namespace\" or \"topic\". Labelling by topic is the most\ndetailed, but creates a time series per topic.",
+			Type:        config.ParameterTypeString,
+			Validations: []config.Validation{
+				config.ValidationInclusion{List: []string{"none", "tenant", "namespace", "topic"}},
+			},
+		},
+		SourceConfigNackInFlightOnShutdown: {
+			Default:     "",
+			Description: "NackInFlightOnShutdown releases messages that were read but not acked\nwhen the source is torn down by closing the consumer before anything\nelse, so they are redelivered to other consumers of the subscription\nright away.",
+			Type:        config.ParameterTypeBool,
+			Validations: []config.Validation{},
+		},
+		SourceConfigNackRedeliveryDelay: {
+			Default:     "1m",
+			Description: "NackRedeliveryDelay is the delay after which negatively acknowledged\nmessages are redelivered.",
+			Type:        config.ParameterTypeDuration,
+			Validations: []config.Validation{},
+		},
+		SourceConfigNotifySchemaChange: {
+			Default:     "",
+			Description: "NotifySchemaChange stores the schema version of each message in the\n\"pulsar.schemaVersion\" metadata and logs a warning when it changes. The\nfirst message with a new version gets the \"pulsar.schemaChanged\"\nmetadata set to \"true\".",
+			Type:        config.ParameterTypeBool,
+			Validations: []config.Validation{},
+		},
+		SourceConfigOauth2Audience: {
+			Default:     "",
+			Description: "OAuth2Audience is the audience the access token is requested for,\nusually the URL of the Pulsar cluster.",
+			Type:        config.ParameterTypeString,
+			Validations: []config.Validation{},
+		},
+		SourceConfigOauth2ClientID: {
+			Default:     "",
+			Description: "OAuth2ClientID is the OAuth2 client ID, required with\nOAuth2ClientSecret.",
+			Type:        config.ParameterTypeString,
+			Validations: []config.Validation{},
+		},
+		SourceConfigOauth2ClientSecret: {
+			Default:     "",
+			Description: "OAuth2ClientSecret is the OAuth2 client secret. Can't be combined with\nOAuth2PrivateKeyFile.",
+			Type:        config.ParameterTypeString,
+			Validations: []config.Validation{},
+		},
+		SourceConfigOauth2IssuerURL: {
+			Default:     "",
+			Description: "OAuth2IssuerURL is the URL of the OAuth2 authorization server. If set,\nthe connector authenticates with the OAuth2 client credentials flow\ninstead of a TLS certificate.",
+			Type:        config.ParameterTypeString,
+			Validations: []config.Validation{},
+		},
+		SourceConfigOauth2PrivateKeyFile: {
+			Default:     "",
+			Description: "OAuth2PrivateKeyFile is the path to a JSON key file containing the\nclient credentials, as provided by the authorization server. Can't be\ncombined with OAuth2ClientSecret.",
+			Type:        config.ParameterTypeString,
+			Validations: []config.Validation{},
+		},
+		SourceConfigOauth2Scope: {
+			Default:     "",
+			Description: "OAuth2Scope is the scope of the requested access token.",
+			Type:        config.ParameterTypeString,
+			Validations: []config.Validation{},
+		},
 		SourceConfigOperationTimeout: {
 			Default:     "",
 			Description: "OperationTimeout is the duration after which an operation is considered\nto have timed out.",
 			Type:        config.ParameterTypeDuration,
 			Validations: []config.Validation{},
 		},
+		SourceConfigPartitionCheckInterval: {
+			Default:     "",
+			Description: "PartitionCheckInterval is the interval at which the partition count of\nthe topic is checked. If partitions were removed, e.g. by a forced\ndeletion, the source subscribes again instead of failing. Added\npartitions are discovered by the Pulsar client. Disabled when set to 0.",
+			Type:        config.ParameterTypeDuration,
+			Validations: []config.Validation{},
+		},
+		SourceConfigPinnedSchemaVersion: {
+			Default:     "",
+			Description: "PinnedSchemaVersion is the schema version of the topic the source reads,\ne.g. \"2\". Messages using another schema version, or no schema, are\nrejected: they are negatively acknowledged and routed to the dead\nletter topic if DLQMaxDeliveries is set, otherwise they are\nacknowledged and skipped. The version must exist on the topic.\nRequires AdminURL.",
+			Type:        config.ParameterTypeString,
+			Validations: []config.Validation{},
+		},
+		SourceConfigPreserveEncryptionContext: {
+			Default:     "",
+			Description: "PreserveEncryptionContext passes encrypted messages through without\ndecrypting them and stores their encryption context in the metadata,\nso a downstream system can decrypt the payload.",
+			Type:        config.ParameterTypeBool,
+			Validations: []config.Validation{},
+		},
+		SourceConfigProcessingDeadline: {
+			Default:     "",
+			Description: "ProcessingDeadline is the time within which a read record has to be\nacknowledged. Records that are not acknowledged in time are negatively\nacknowledged, so the message is redelivered after NackRedeliveryDelay,\nor after the retry delay if EnableRetry is set, instead of blocking\nprogress. Disabled when set to 0.",
+			Type:        config.ParameterTypeDuration,
+			Validations: []config.Validation{},
+		},
+		SourceConfigReadBatchSize: {
+			Default:     "",
+			Description: "ReadBatchSize is the maximum number of messages taken from the consumer\nat once. Read waits for the first message and buffers the messages that\nare already available up to the batch size, subsequent reads are served\nfrom the buffer. Disabled when set to 0 or 1, can't be combined with\nMessageListenerMode or AutoScaleReceiverQueue.",
+			Type:        config.ParameterTypeInt,
+			Validations: []config.Validation{},
+		},
+		SourceConfigReadCompacted: {
+			Default:     "",
+			Description: "ReadCompacted reads the compacted view of the topic, which only\ncontains the latest message of each key. Only supported by \"exclusive\"\nand \"failover\" subscriptions.",
+			Type:        config.ParameterTypeBool,
+			Validations: []config.Validation{},
+		},
+		SourceConfigReaderMessageLimit: {
+			Default:     "",
+			Description: "ReaderMessageLimit is the number of messages read when replaying the\ntopic from ReaderStartMessageID. Once the limit is reached the source\nproduces no more records. Unlimited when set to 0.",
+			Type:        config.ParameterTypeInt,
+			Validations: []config.Validation{
+				config.ValidationGreaterThan{V: -1},
+			},
+		},
+		SourceConfigReaderStartMessageID: {
+			Default:     "",
+			Description: "ReaderStartMessageID is a base64 encoded serialized message ID. If set,\nthe source replays the topic with a reader starting at this message\n(inclusive) instead of consuming it through a subscription. Message IDs\nare encoded the same way as in the record position.",
+			Type:        config.ParameterTypeString,
+			Validations: []config.Validation{},
+		},
+		SourceConfigReceiverQueueSize: {
+			Default:     "",
+			Description: "ReceiverQueueSize is the number of messages the consumer prefetches.\nUses the client default when set to 0. Can't be combined with\nAutoScaleReceiverQueue or AdaptivePrefetch, which size the queue\nthemselves.",
+			Type:        config.ParameterTypeInt,
+			Validations: []config.Validation{},
+		},
+		SourceConfigResetSubscription: {
+			Default:     "",
+			Description: "ResetSubscription moves the subscription to the \"earliest\" or \"latest\"\nmessage of the topic using the admin API before subscribing, to replay\nthe topic without deleting the subscription. The reset is applied each\ntime the source is opened, so it should be removed after the replay.\nRequires AdminURL and SubscriptionName.",
+			Type:        config.ParameterTypeString,
+			Validations: []config.Validation{
+				config.ValidationInclusion{List: []string{"earliest", "latest"}},
+			},
+		},
+		SourceConfigRetryDelay: {
+			Default:     "1m",
+			Description: "RetryDelay is the delay after which a failed record is retried the\nfirst time.",
+			Type:        config.ParameterTypeDuration,
+			Validations: []config.Validation{},
+		},
+		SourceConfigRetryDelayMultiplier: {
+			Default:     "1",
+			Description: "RetryDelayMultiplier is the factor the retry delay grows by with each\nretry of a record, so the n-th retry happens after\nRetryDelay * RetryDelayMultiplier^(n-1). Must be at least 1.",
+			Type:        config.ParameterTypeFloat,
+			Validations: []config.Validation{},
+		},
+		SourceConfigRetryLetterTopic: {
+			Default:     "",
+			Description: "RetryLetterTopic is the name of the topic failed records are retried\nfrom. Defaults to \"<topic>-<subscriptionName>-RETRY\".",
+			Type:        config.ParameterTypeString,
+			Validations: []config.Validation{},
+		},
+		SourceConfigSchemaDefinition: {
+			Default:     "",
+			Description: "SchemaDefinition is the Avro schema definition used to decode payloads\nwhen SchemaType is \"json\" or \"avro\", or the path of a file containing\nit.",
+			Type:        config.ParameterTypeString,
+			Validations: []config.Validation{},
+		},
+		SourceConfigSchemaIncompatibilityAction: {
+			Default:     "",
+			Description: "SchemaIncompatibilityAction defines what happens to messages that don't\nmatch PinnedSchemaVersion or JSONSchemaValidation. With \"skip\" they are\nacknowledged and skipped, with \"dlq\" they are routed to the dead letter\ntopic and with \"raw\" they are passed through with the raw payload and\nthe reason in the \"pulsar.schemaIncompatible\" metadata field. If unset,\nthey are handled as described for the schema options.",
+			Type:        config.ParameterTypeString,
+			Validations: []config.Validation{
+				config.ValidationInclusion{List: []string{"skip", "dlq", "raw"}},
+			},
+		},
+		SourceConfigSchemaRegistryMaxRetries: {
+			Default:     "",
+			Description: "SchemaRegistryMaxRetries is the number of times creating the consumer or\nproducer is retried when it fails, e.g. because the schema registry is\ntemporarily unavailable. Permanent errors like an invalid configuration\nor a closed producer aren't retried. Retries are disabled by default.",
+			Type:        config.ParameterTypeInt,
+			Validations: []config.Validation{
+				config.ValidationGreaterThan{V: -1},
+			},
+		},
+		SourceConfigSchemaRegistryRetryBackoff: {
+			Default:     "1s",
+			Description: "SchemaRegistryRetryBackoff is the delay before the first retry, it is\ndoubled after each failed attempt up to one minute.",
+			Type:        config.ParameterTypeDuration,
+			Validations: []config.Validation{},
+		},
+		SourceConfigSchemaType: {
+			Default:     "none",
+			Description: "SchemaType is the schema the source subscribes with. With \"json\" and\n\"avro\" the payload is decoded with SchemaDefinition and returned as\nstructured data, with \"string\" and \"none\" it is returned as raw data.\nMessages that can't be decoded are rejected like messages that don't\nmatch JSONSchemaValidation.",
+			Type:        config.ParameterTypeString,
+			Validations: []config.Validation{
+				config.ValidationInclusion{List: []string{"none", "json", "avro", "string"}},
+			},
+		},
+		SourceConfigStartFromTimestamp: {
+			Default:     "",
+			Description: "StartFromTimestamp starts a new subscription at the first message\npublished at or after this time, e.g. for backfills. Accepts an RFC\n3339 timestamp or unix milliseconds. Ignored when resuming from a\nposition. Requires a single topic.",
+			Type:        config.ParameterTypeString,
+			Validations: []config.Validation{},
+		},
+		SourceConfigSubscribeTimeout: {
+			Default:     "",
+			Description: "SubscribeTimeout bounds subscribing to the topic, independently of\nOperationTimeout. Disabled when set to 0.",
+			Type:        config.ParameterTypeDuration,
+			Validations: []config.Validation{},
+		},
+		SourceConfigSubscriptionInitialPosition: {
+			Default:     "earliest",
+			Description: "SubscriptionInitialPosition is the position a new subscription starts\nfrom, the \"earliest\" message available in the topic or the \"latest\"\none. Existing subscriptions continue from their stored position. Any\nsubscription type is accepted with either position, a new \"shared\" or\n\"key_shared\" subscription starting from \"earliest\" distributes the\nwhole backlog across its consumers.",
+			Type:        config.ParameterTypeString,
+			Validations: []config.Validation{
+				config.ValidationInclusion{List: []string{"earliest", "latest"}},
+			},
+		},
 		SourceConfigSubscriptionName: {
 			Default:     "",
 			Description: "SubscriptionName is the name of the subscription to be used for\nconsuming messages.",
 			Type:        config.ParameterTypeString,
 			Validations: []config.Validation{},
 		},
+		SourceConfigSubscriptionType: {
+			Default:     "exclusive",
+			Description: "SubscriptionType defines how messages are delivered to the consumers of\nthe subscription. Use \"shared\", \"failover\" or \"key_shared\" to run\nmultiple connector instances against the same subscription.",
+			Type:        config.ParameterTypeString,
+			Validations: []config.Validation{
+				config.ValidationInclusion{List: []string{"exclusive", "shared", "failover", "key_shared"}},
+			},
+		},
+		SourceConfigTeardownTimeout: {
+			Default:     "",
+			Description: "TeardownTimeout is how long teardown waits for records that were read\nbut not acknowledged yet. Messages that are still not acknowledged are\nredelivered once the consumer is closed. Disabled when set to 0.",
+			Type:        config.ParameterTypeDuration,
+			Validations: []config.Validation{},
+		},
 		SourceConfigTlsAllowInsecureConnection: {
 			Default:     "",
 			Description: "TLSAllowInsecureConnection configures whether the internal Pulsar client accepts untrusted TLS certificate from broker (default: false)",
@@ -98,13 +674,35 @@ func (SourceConfig) Parameters() map[string]config.Parameter {
 			Type:        config.ParameterTypeBool,
 			Validations: []config.Validation{},
 		},
+		SourceConfigToken: {
+			Default:     "",
+			Description: "Token is a JWT token the connector authenticates with. Can't be\ncombined with TokenFilePath.",
+			Type:        config.ParameterTypeString,
+			Validations: []config.Validation{},
+		},
+		SourceConfigTokenFilePath: {
+			Default:     "",
+			Description: "TokenFilePath is the path to a file containing a JWT token the connector\nauthenticates with. The file is read again whenever a token is needed,\nso it can be rotated. Can't be combined with Token.",
+			Type:        config.ParameterTypeString,
+			Validations: []config.Validation{},
+		},
 		SourceConfigTopic: {
 			Default:     "",
-			Description: "Topic specifies the Pulsar topic used by the connector.",
+			Description: "Topic specifies the Pulsar topic used by the connector. In the\ndestination it can contain a Go template that is executed with the\nrecord to determine the topic, e.g.\n`events-{{index .Metadata \"tenant\"}}`. A producer is created for each\nresolved topic when the first record is written to it. Required in the\ndestination, the source can be configured with Topics instead.",
 			Type:        config.ParameterTypeString,
-			Validations: []config.Validation{
-				config.ValidationRequired{},
-			},
+			Validations: []config.Validation{},
+		},
+		SourceConfigTopics: {
+			Default:     "",
+			Description: "Topics is a comma separated list of topics consumed under the same\nsubscription. The position of a record contains the last message read\nfrom each topic, so messages redelivered after a restart are skipped\nper topic. Can't be combined with Topic or TopicsPattern.",
+			Type:        config.ParameterTypeString,
+			Validations: []config.Validation{},
+		},
+		SourceConfigTopicsPattern: {
+			Default:     "",
+			Description: "TopicsPattern is a regular expression, all topics matching it are\nconsumed under the same subscription, e.g.\n`persistent://tenant/ns/events-.*`. Can't be combined with Topic or\nTopics.",
+			Type:        config.ParameterTypeString,
+			Validations: []config.Validation{},
 		},
 		SourceConfigUrl: {
 			Default:     "",
@@ -114,5 +712,11 @@ func (SourceConfig) Parameters() map[string]config.Parameter {
 				config.ValidationRequired{},
 			},
 		},
+		SourceConfigWatermarkInterval: {
+			Default:     "10s",
+			Description: "WatermarkInterval is the minimum time between two watermark records.\nA watermark is only emitted if it advanced since the last one.",
+			Type:        config.ParameterTypeDuration,
+			Validations: []config.Validation{},
+		},
 	}
 }
@@ -0,0 +1,87 @@
+// Copyright © 2024 Meroxa, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pulsar
+
+import (
+	"context"
+	"time"
+
+	sdk "github.com/conduitio/conduit-connector-sdk"
+)
+
+// partitionGrowth measures the produce throughput in fixed windows and
+// signals when it exceeds the threshold.
+type partitionGrowth struct {
+	// threshold is the number of messages per second above which partitions
+	// are grown.
+	threshold int
+	window    time.Duration
+
+	count       int
+	windowStart time.Time
+	now         func() time.Time
+}
+
+func newPartitionGrowth(threshold int, window time.Duration) *partitionGrowth {
+	return &partitionGrowth{threshold: threshold, window: window, now: time.Now}
+}
+
+// record counts produced messages. It returns true once per window if the
+// throughput of the window exceeded the threshold.
+func (g *partitionGrowth) record(n int) bool {
+	now := g.now()
+	if g.windowStart.IsZero() {
+		g.windowStart = now
+	}
+	g.count += n
+
+	elapsed := now.Sub(g.windowStart)
+	if elapsed < g.window {
+		return false
+	}
+
+	rate := float64(g.count) / elapsed.Seconds()
+	g.count = 0
+	g.windowStart = now
+	return rate > float64(g.threshold)
+}
+
+// growPartitionsIfNeeded grows the partitions of the topic if the produce
+// throughput exceeded the threshold. Failing to grow the partitions doesn't
+// fail the write, the throughput is only limited.
+func (d *Destination) growPartitionsIfNeeded(ctx context.Context, n int) {
+	if !d.growth.record(n) {
+		return
+	}
+
+	partitions, err := growPartitions(d.admin, d.config.Topic, d.config.MaxPartitions)
+	if err != nil {
+		sdk.Logger(ctx).Warn().Err(err).Msg("failed to grow partitions")
+		return
+	}
+	if partitions == 0 {
+		sdk.Logger(ctx).Debug().
+			Int("maxPartitions", d.config.MaxPartitions).
+			Msg("throughput threshold exceeded, but the topic already has the max number of partitions")
+		return
+	}
+	if d.partitionCounts != nil {
+		d.partitionCounts[d.config.Topic] = partitions
+	}
+	sdk.Logger(ctx).Info().
+		Str("topic", d.config.Topic).
+		Int("partitions", partitions).
+		Msg("throughput threshold exceeded, grew partitions")
+}
@@ -0,0 +1,166 @@
+// Copyright © 2024 Meroxa, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pulsar
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/apache/pulsar-client-go/pulsaradmin"
+	"github.com/apache/pulsar-client-go/pulsaradmin/pkg/admin"
+	"github.com/apache/pulsar-client-go/pulsaradmin/pkg/utils"
+	"github.com/conduitio-labs/conduit-connector-pulsar/test"
+	"github.com/conduitio/conduit-commons/opencdc"
+	sdk "github.com/conduitio/conduit-connector-sdk"
+	"github.com/google/uuid"
+	"github.com/matryer/is"
+)
+
+func TestPartitionGrowth_Record(t *testing.T) {
+	is := is.New(t)
+
+	now := time.Now()
+	growth := newPartitionGrowth(10, time.Second)
+	growth.now = func() time.Time { return now }
+
+	is.True(!growth.record(50))
+
+	// 15 messages per second, but the window hasn't passed yet
+	now = now.Add(500 * time.Millisecond)
+	is.True(!growth.record(100))
+
+	now = now.Add(500 * time.Millisecond)
+	is.True(growth.record(0))
+
+	// the next window starts empty, 5 messages per second
+	now = now.Add(time.Second)
+	is.True(!growth.record(5))
+}
+
+// fakeTopics keeps the partition count of a single topic.
+type fakeTopics struct {
+	admin.Topics
+	partitions int
+}
+
+func (t *fakeTopics) GetMetadata(utils.TopicName) (utils.PartitionedTopicMetadata, error) {
+	return utils.PartitionedTopicMetadata{Partitions: t.partitions}, nil
+}
+
+func (t *fakeTopics) Update(_ utils.TopicName, partitions int) error {
+	t.partitions = partitions
+	return nil
+}
+
+type fakeAdmin struct {
+	pulsaradmin.Client
+	topics *fakeTopics
+}
+
+func (a fakeAdmin) Topics() admin.Topics { return a.topics }
+
+func TestGrowPartitions(t *testing.T) {
+	is := is.New(t)
+
+	topics := &fakeTopics{partitions: 2}
+	client := fakeAdmin{topics: topics}
+
+	var grown []int
+	for i := 0; i < 3; i++ {
+		partitions, err := growPartitions(client, "test-topic", 6)
+		is.NoErr(err)
+		grown = append(grown, partitions)
+	}
+	// doubled until the max is reached
+	is.Equal(grown, []int{4, 6, 0})
+	is.Equal(topics.partitions, 6)
+}
+
+func TestGrowPartitions_NonPartitionedTopic(t *testing.T) {
+	is := is.New(t)
+
+	_, err := growPartitions(fakeAdmin{topics: &fakeTopics{}}, "test-topic", 6)
+	is.True(err != nil)
+}
+
+func TestDestination_Configure_AutoGrowPartitionsRequiresAdminURL(t *testing.T) {
+	is := is.New(t)
+	con := NewDestination()
+
+	err := con.Configure(context.Background(), map[string]string{
+		DestinationConfigUrl:                         test.PulsarURL,
+		DestinationConfigTopic:                       "test-topic",
+		DestinationConfigAutoGrowPartitions:          "true",
+		DestinationConfigMaxPartitions:               "8",
+		DestinationConfigAutoGrowPartitionsThreshold: "1000",
+	})
+	is.True(err != nil)
+}
+
+func TestDestination_Integration_AutoGrowPartitions(t *testing.T) {
+	is := is.New(t)
+	ctx := context.Background()
+
+	topic := test.SetupTopicName(t, is)
+
+	client, err := pulsaradmin.NewClient(&pulsaradmin.Config{WebServiceURL: test.PulsarAdminURL})
+	is.NoErr(err)
+	topicName, err := utils.GetTopicName(topic)
+	is.NoErr(err)
+	err = client.Topics().Create(*topicName, 1)
+	is.NoErr(err)
+
+	con := NewDestination()
+	err = con.Configure(ctx, map[string]string{
+		DestinationConfigUrl:                         test.PulsarURL,
+		DestinationConfigTopic:                       topic,
+		DestinationConfigAdminURL:                    test.PulsarAdminURL,
+		DestinationConfigAutoGrowPartitions:          "true",
+		DestinationConfigMaxPartitions:               "4",
+		DestinationConfigAutoGrowPartitionsThreshold: "10",
+		DestinationConfigAutoGrowPartitionsWindow:    "500ms",
+	})
+	is.NoErr(err)
+	err = con.Open(ctx)
+	is.NoErr(err)
+	defer func() {
+		err := con.Teardown(ctx)
+		is.NoErr(err)
+	}()
+
+	// sustain more than 10 messages per second over several windows
+	deadline := time.Now().Add(3 * time.Second)
+	for time.Now().Before(deadline) {
+		var records []opencdc.Record
+		for i := 0; i < 10; i++ {
+			records = append(records, sdk.Util.Source.NewRecordCreate(
+				[]byte(uuid.NewString()),
+				nil,
+				opencdc.RawData(fmt.Sprintf("key-%d", i)),
+				opencdc.RawData(exampleMessage),
+			))
+		}
+		written, err := con.Write(ctx, records)
+		is.NoErr(err)
+		is.Equal(written, len(records))
+		time.Sleep(100 * time.Millisecond)
+	}
+
+	metadata, err := client.Topics().GetMetadata(*topicName)
+	is.NoErr(err)
+	is.Equal(metadata.Partitions, 4)
+}
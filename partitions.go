@@ -0,0 +1,130 @@
+// Copyright © 2024 Meroxa, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pulsar
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/apache/pulsar-client-go/pulsar"
+	sdk "github.com/conduitio/conduit-connector-sdk"
+)
+
+// partitionTracker keeps track of the partition count of the topic. The
+// Pulsar client discovers added partitions, but its consumer breaks when
+// partitions are removed.
+type partitionTracker struct {
+	interval  time.Duration
+	checkedAt time.Time
+
+	// mu guards count, which acks read while Read checks the partitions.
+	mu    sync.RWMutex
+	count int
+
+	now func() time.Time
+}
+
+func newPartitionTracker(interval time.Duration) *partitionTracker {
+	return &partitionTracker{
+		interval: interval,
+		now:      time.Now,
+	}
+}
+
+// init fetches the current partition count of the topic.
+func (t *partitionTracker) init(client pulsar.Client, topic string) error {
+	partitions, err := client.TopicPartitions(topic)
+	if err != nil {
+		return fmt.Errorf("failed to fetch partitions of topic %q: %w", topic, err)
+	}
+	t.mu.Lock()
+	t.count = len(partitions)
+	t.mu.Unlock()
+	t.checkedAt = t.now()
+	return nil
+}
+
+// nextCheck returns when the partition count should be checked next.
+func (t *partitionTracker) nextCheck() time.Time {
+	return t.checkedAt.Add(t.interval)
+}
+
+// isRemoved returns true if the message belongs to a partition that was
+// removed.
+func (t *partitionTracker) isRemoved(msgID pulsar.MessageID) bool {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+	return int(msgID.PartitionIdx()) >= t.count
+}
+
+// receiveCheckingPartitions returns the next message, checking the partition
+// count of the topic at the configured interval while waiting.
+func (s *Source) receiveCheckingPartitions(ctx context.Context) (pulsar.Message, error) {
+	for {
+		if err := s.checkPartitions(ctx); err != nil {
+			return nil, err
+		}
+
+		receiveCtx, cancel := context.WithDeadline(ctx, s.partitions.nextCheck())
+		msg, err := s.receiveNext(receiveCtx)
+		cancel()
+		if errors.Is(err, context.DeadlineExceeded) && ctx.Err() == nil {
+			continue
+		}
+		return msg, err
+	}
+}
+
+// checkPartitions subscribes again if partitions of the topic were removed
+// since the last check.
+func (s *Source) checkPartitions(ctx context.Context) error {
+	if s.partitions.now().Before(s.partitions.nextCheck()) {
+		return nil
+	}
+
+	previous := s.partitions.count
+	if err := s.partitions.init(s.client, s.config.topics()[0]); err != nil {
+		// the broker might be temporarily unavailable, check again later
+		sdk.Logger(ctx).Warn().Err(err).Msg("failed to check partition count")
+		s.partitions.checkedAt = s.partitions.now()
+		return nil
+	}
+	if s.partitions.count == previous {
+		return nil
+	}
+
+	sdk.Logger(ctx).Warn().
+		Int("previousPartitions", previous).
+		Int("partitions", s.partitions.count).
+		Msg("partition count of the topic changed")
+	if s.partitions.count > previous {
+		return nil
+	}
+
+	// acks and nacks wait until the new consumer is subscribed
+	s.consumerMu.Lock()
+	defer s.consumerMu.Unlock()
+	s.consumer.Close()
+	// buffered messages of the closed consumer are redelivered to the new one
+	s.batch = nil
+	if err := s.subscribe(ctx); err != nil {
+		return err
+	}
+	sdk.Logger(ctx).Info().Msg("subscribed again after partitions were removed")
+	return nil
+}
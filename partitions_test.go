@@ -0,0 +1,185 @@
+// Copyright © 2024 Meroxa, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pulsar
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/apache/pulsar-client-go/pulsar"
+	"github.com/matryer/is"
+)
+
+// partitionedClient returns the configured partitions of the topic and hands
+// out the queued consumers on subscribe.
+type partitionedClient struct {
+	pulsar.Client
+
+	partitions int
+	consumers  []*queueConsumer
+	subscribed int
+}
+
+func (c *partitionedClient) TopicPartitions(topic string) ([]string, error) {
+	partitions := make([]string, c.partitions)
+	for i := range partitions {
+		partitions[i] = topic
+	}
+	return partitions, nil
+}
+
+func (c *partitionedClient) Subscribe(pulsar.ConsumerOptions) (pulsar.Consumer, error) {
+	consumer := c.consumers[c.subscribed]
+	c.subscribed++
+	return consumer, nil
+}
+
+// queueConsumer returns the queued messages and then blocks until the context
+// is done.
+type queueConsumer struct {
+	pulsar.Consumer
+
+	messages []pulsar.Message
+	closed   bool
+}
+
+func (c *queueConsumer) Receive(ctx context.Context) (pulsar.Message, error) {
+	if len(c.messages) > 0 {
+		msg := c.messages[0]
+		c.messages = c.messages[1:]
+		return msg, nil
+	}
+	<-ctx.Done()
+	return nil, ctx.Err()
+}
+
+func (c *queueConsumer) Close() { c.closed = true }
+
+func (c *queueConsumer) AckID(pulsar.MessageID) error {
+	if c.closed {
+		return errors.New("consumer closed")
+	}
+	return nil
+}
+
+// readableMessage implements the methods of pulsar.Message used by Read.
+type readableMessage struct {
+	fakeMessage
+}
+
+func (readableMessage) EventTime() time.Time    { return time.Time{} }
+func (readableMessage) Key() string             { return "" }
+func (readableMessage) OrderingKey() string     { return "" }
+func (readableMessage) Payload() []byte         { return nil }
+func (readableMessage) RedeliveryCount() uint32 { return 0 }
+func (readableMessage) SchemaVersion() []byte   { return nil }
+
+func (readableMessage) Properties() map[string]string { return nil }
+
+func TestSource_Read_PartitionCountDecreased(t *testing.T) {
+	is := is.New(t)
+	ctx := context.Background()
+
+	oldConsumer := &queueConsumer{messages: []pulsar.Message{
+		readableMessage{fakeMessage{topic: "test-topic-partition-2", id: pulsar.NewMessageID(1, 1, 0, 2)}},
+	}}
+	newConsumer := &queueConsumer{messages: []pulsar.Message{
+		readableMessage{fakeMessage{topic: "test-topic-partition-0", id: pulsar.NewMessageID(2, 1, 0, 0)}},
+	}}
+	client := &partitionedClient{partitions: 3, consumers: []*queueConsumer{newConsumer}}
+
+	underTest := &Source{
+		client:     client,
+		consumer:   oldConsumer,
+		config:     SourceConfig{Config: Config{Topic: "test-topic"}},
+		partitions: newPartitionTracker(time.Millisecond),
+	}
+	is.NoErr(underTest.partitions.init(client, "test-topic"))
+
+	rec, err := underTest.Read(ctx)
+	is.NoErr(err)
+	is.Equal(rec.Metadata["pulsar.topic"], "test-topic-partition-2")
+
+	// the partition is removed while the source waits for the next message
+	client.partitions = 2
+
+	rec2, err := underTest.Read(ctx)
+	is.NoErr(err)
+	is.Equal(rec2.Metadata["pulsar.topic"], "test-topic-partition-0")
+	is.True(oldConsumer.closed)
+	is.Equal(client.subscribed, 1)
+
+	// the message from the removed partition can't be acked, but doesn't
+	// fail the pipeline
+	is.NoErr(underTest.Ack(ctx, rec.Position))
+	is.NoErr(underTest.Ack(ctx, rec2.Position))
+}
+
+func TestSource_Read_PartitionCountDecreasedWhileAcking(t *testing.T) {
+	is := is.New(t)
+	ctx := context.Background()
+
+	oldConsumer := &queueConsumer{messages: []pulsar.Message{
+		readableMessage{fakeMessage{topic: "test-topic-partition-0", id: pulsar.NewMessageID(1, 1, 0, 0)}},
+	}}
+	newConsumer := &queueConsumer{messages: []pulsar.Message{
+		readableMessage{fakeMessage{topic: "test-topic-partition-0", id: pulsar.NewMessageID(2, 1, 0, 0)}},
+	}}
+	client := &partitionedClient{partitions: 3, consumers: []*queueConsumer{newConsumer}}
+
+	underTest := &Source{
+		client:     client,
+		consumer:   oldConsumer,
+		config:     SourceConfig{Config: Config{Topic: "test-topic"}},
+		partitions: newPartitionTracker(time.Millisecond),
+	}
+	is.NoErr(underTest.partitions.init(client, "test-topic"))
+
+	rec, err := underTest.Read(ctx)
+	is.NoErr(err)
+
+	// the record is acked over and over while the source subscribes again
+	done := make(chan struct{})
+	var wg sync.WaitGroup
+	var ackErr error
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		for {
+			select {
+			case <-done:
+				return
+			default:
+			}
+			if err := underTest.Ack(ctx, rec.Position); err != nil {
+				ackErr = err
+				return
+			}
+		}
+	}()
+
+	client.partitions = 2
+	rec2, err := underTest.Read(ctx)
+	close(done)
+	wg.Wait()
+
+	is.NoErr(err)
+	is.Equal(rec2.Metadata["pulsar.topic"], "test-topic-partition-0")
+	is.True(oldConsumer.closed)
+	is.NoErr(ackErr) // acks must not reach the closed consumer
+}
@@ -0,0 +1,67 @@
+// Copyright © 2024 Meroxa, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pulsar
+
+import (
+	"encoding/json"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/conduitio/conduit-commons/opencdc"
+)
+
+const (
+	contentTypeJSON   = "application/json"
+	contentTypeText   = "text/plain"
+	contentTypeBinary = "application/octet-stream"
+)
+
+// inferPayload guesses the type of the payload and returns it in the matching
+// representation together with its content type. The heuristics are:
+//   - a payload containing a JSON object is returned as structured data,
+//   - a payload that is valid UTF-8 without control characters (other than
+//     whitespace) is considered text,
+//   - anything else is considered binary.
+//
+// JSON arrays and scalars can't be represented as structured data, they are
+// returned as raw data with the JSON content type. Binary formats that happen
+// to be valid UTF-8 are detected as text.
+func inferPayload(payload []byte) (opencdc.Data, string) {
+	if json.Valid(payload) {
+		var structured opencdc.StructuredData
+		if err := json.Unmarshal(payload, &structured); err == nil && structured != nil {
+			return structured, contentTypeJSON
+		}
+		return opencdc.RawData(payload), contentTypeJSON
+	}
+
+	if isText(payload) {
+		return opencdc.RawData(payload), contentTypeText
+	}
+
+	return opencdc.RawData(payload), contentTypeBinary
+}
+
+func isText(payload []byte) bool {
+	if !utf8.Valid(payload) {
+		return false
+	}
+	for _, r := range string(payload) {
+		if unicode.IsControl(r) && !unicode.IsSpace(r) {
+			return false
+		}
+	}
+	return true
+}
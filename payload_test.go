@@ -0,0 +1,78 @@
+// Copyright © 2024 Meroxa, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pulsar
+
+import (
+	"context"
+	"testing"
+
+	"github.com/apache/pulsar-client-go/pulsar"
+	"github.com/conduitio/conduit-commons/opencdc"
+	"github.com/matryer/is"
+)
+
+func TestInferPayload(t *testing.T) {
+	testCases := []struct {
+		name            string
+		payload         []byte
+		wantData        opencdc.Data
+		wantContentType string
+	}{{
+		name:            "json object",
+		payload:         []byte(`{"id":1,"name":"foo"}`),
+		wantData:        opencdc.StructuredData{"id": float64(1), "name": "foo"},
+		wantContentType: contentTypeJSON,
+	}, {
+		name:            "json array",
+		payload:         []byte(`[1,2,3]`),
+		wantData:        opencdc.RawData(`[1,2,3]`),
+		wantContentType: contentTypeJSON,
+	}, {
+		name:            "plain text",
+		payload:         []byte("hello world\n"),
+		wantData:        opencdc.RawData("hello world\n"),
+		wantContentType: contentTypeText,
+	}, {
+		name:            "binary",
+		payload:         []byte{0x00, 0x01, 0xff, 0xfe},
+		wantData:        opencdc.RawData([]byte{0x00, 0x01, 0xff, 0xfe}),
+		wantContentType: contentTypeBinary,
+	}}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			is := is.New(t)
+
+			data, contentType := inferPayload(tc.payload)
+			is.Equal(data, tc.wantData)
+			is.Equal(contentType, tc.wantContentType)
+		})
+	}
+}
+
+func TestSource_Read_InferPayloadType(t *testing.T) {
+	is := is.New(t)
+
+	msg := payloadMessage{readableMessage{fakeMessage{id: pulsar.NewMessageID(1, 1, 0, 0)}}, []byte(`{"id": 1}`)}
+	underTest := &Source{
+		consumer: &queueConsumer{messages: []pulsar.Message{msg}},
+		config:   SourceConfig{InferPayloadType: true},
+	}
+
+	rec, err := underTest.Read(context.Background())
+	is.NoErr(err)
+	is.Equal(rec.Metadata[metadataContentType], contentTypeJSON)
+	is.Equal(rec.Payload.After, opencdc.StructuredData{"id": float64(1)})
+}
@@ -0,0 +1,39 @@
+// Copyright © 2024 Meroxa, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pulsar
+
+import (
+	"context"
+
+	"github.com/conduitio/conduit-commons/opencdc"
+)
+
+// PositionStore persists the positions of acknowledged records in an external
+// system, for environments that manage offsets outside of Conduit. Positions
+// are stored per subscription.
+type PositionStore interface {
+	// Save stores the position of the last acknowledged record.
+	Save(ctx context.Context, subscription string, position opencdc.Position) error
+	// Load returns the stored position, or nil if no position was stored.
+	Load(ctx context.Context, subscription string) (opencdc.Position, error)
+}
+
+// noopPositionStore is the default position store, it relies on the position
+// managed by Conduit.
+type noopPositionStore struct{}
+
+func (noopPositionStore) Save(context.Context, string, opencdc.Position) error { return nil }
+
+func (noopPositionStore) Load(context.Context, string) (opencdc.Position, error) { return nil, nil }
@@ -0,0 +1,89 @@
+// Copyright © 2024 Meroxa, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pulsar
+
+import (
+	"sync"
+	"time"
+)
+
+// adaptivePrefetch bounds the number of records that were read but not yet
+// acknowledged. The bound grows by one with every record acknowledged within
+// the target latency and is halved when a record takes longer, so a slow
+// pipeline doesn't hold on to more messages than it can process.
+type adaptivePrefetch struct {
+	min           int
+	max           int
+	targetLatency time.Duration
+
+	mu     sync.Mutex
+	limit  int
+	readAt map[string]time.Time
+
+	now func() time.Time
+}
+
+func newAdaptivePrefetch(min, max int, targetLatency time.Duration) *adaptivePrefetch {
+	return &adaptivePrefetch{
+		min:           min,
+		max:           max,
+		targetLatency: targetLatency,
+		limit:         min,
+		readAt:        make(map[string]time.Time),
+		now:           time.Now,
+	}
+}
+
+// full returns true if no more records should be read until some are
+// acknowledged.
+func (p *adaptivePrefetch) full() bool {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return len(p.readAt) >= p.limit
+}
+
+// read records when the message with the serialized ID was read.
+func (p *adaptivePrefetch) read(msgID []byte) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.readAt[string(msgID)] = p.now()
+}
+
+// forget frees the slot of the message with the serialized ID, which was
+// negatively acknowledged, without adjusting the limit.
+func (p *adaptivePrefetch) forget(msgID []byte) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	delete(p.readAt, string(msgID))
+}
+
+// acked adjusts the limit to the time it took to acknowledge the message with
+// the serialized ID.
+func (p *adaptivePrefetch) acked(msgID []byte) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	readAt, ok := p.readAt[string(msgID)]
+	if !ok {
+		return
+	}
+	delete(p.readAt, string(msgID))
+
+	if p.now().Sub(readAt) <= p.targetLatency {
+		p.limit = min(p.limit+1, p.max)
+	} else {
+		p.limit = max(p.limit/2, p.min)
+	}
+}
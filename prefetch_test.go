@@ -0,0 +1,97 @@
+// Copyright © 2024 Meroxa, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pulsar
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/apache/pulsar-client-go/pulsar"
+	"github.com/conduitio/conduit-commons/opencdc"
+	sdk "github.com/conduitio/conduit-connector-sdk"
+	"github.com/matryer/is"
+)
+
+func TestSource_AdaptivePrefetch(t *testing.T) {
+	testCases := []struct {
+		name       string
+		ackLatency time.Duration
+		wantLimit  int
+	}{
+		// every ack is within the target latency, the limit grows by one
+		// per ack up to the max
+		{name: "fast acks", ackLatency: 100 * time.Millisecond, wantLimit: 6},
+		// every ack is slower than the target latency, the limit is halved
+		// down to the min
+		{name: "slow acks", ackLatency: 2 * time.Second, wantLimit: 2},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			is := is.New(t)
+			ctx := context.Background()
+
+			var messages []pulsar.Message
+			for i := int64(0); i < 20; i++ {
+				messages = append(messages, readableMessage{fakeMessage{topic: "test-topic", id: pulsar.NewMessageID(1, i, 0, 0)}})
+			}
+
+			prefetch := newAdaptivePrefetch(2, 6, time.Second)
+			prefetch.limit = 4
+			clock := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+			prefetch.now = func() time.Time { return clock }
+
+			underTest := &Source{
+				consumer: &queueConsumer{messages: messages},
+				config:   SourceConfig{Config: Config{Topic: "test-topic"}},
+				prefetch: prefetch,
+			}
+
+			for round := 0; round < 3; round++ {
+				// read until the source waits for acks
+				var recs []opencdc.Record
+				for {
+					rec, err := underTest.Read(ctx)
+					if errors.Is(err, sdk.ErrBackoffRetry) {
+						break
+					}
+					is.NoErr(err)
+					recs = append(recs, rec)
+				}
+				is.Equal(len(recs), prefetch.limit)
+
+				clock = clock.Add(tc.ackLatency)
+				for _, rec := range recs {
+					is.NoErr(underTest.Ack(ctx, rec.Position))
+				}
+			}
+			is.Equal(prefetch.limit, tc.wantLimit)
+		})
+	}
+}
+
+func TestSource_Configure_AdaptivePrefetchBounds(t *testing.T) {
+	is := is.New(t)
+
+	cfgMap := newSourceCfg("topic")
+	cfgMap[SourceConfigAdaptivePrefetch] = "true"
+	cfgMap[SourceConfigAdaptivePrefetchMin] = "100"
+	cfgMap[SourceConfigAdaptivePrefetchMax] = "10"
+
+	err := (&Source{}).Configure(context.Background(), cfgMap)
+	is.True(err != nil)
+}
@@ -0,0 +1,91 @@
+// Copyright © 2024 Meroxa, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pulsar
+
+import (
+	"sort"
+	"strconv"
+
+	"github.com/conduitio/conduit-commons/opencdc"
+)
+
+// Pulsar has no notion of message priority, so priorities are applied by the
+// connector to the order in which a batch of records is produced. Records that
+// are written in separate batches are not reordered.
+
+// writeOrder returns the indices of the records in the order they should be
+// produced. Records are ordered by the priority stored in the metadata under
+// priorityKey, highest first. Records without a valid priority have priority
+// 0, records with the same priority keep their original order. Records with
+// the same key are never reordered relative to each other, a record with a
+// higher priority only overtakes records with a different key.
+func writeOrder(records []opencdc.Record, priorityKey string) []int {
+	order := make([]int, len(records))
+	for i := range order {
+		order[i] = i
+	}
+	if priorityKey == "" {
+		return order
+	}
+
+	priorities := make([]int, len(records))
+	for i, r := range records {
+		priorities[i], _ = strconv.Atoi(r.Metadata[priorityKey])
+	}
+
+	sort.SliceStable(order, func(i, j int) bool {
+		return priorities[order[i]] > priorities[order[j]]
+	})
+
+	// restore the original order within each group of records with the same
+	// key, keeping the slots the group was assigned by priority
+	groups := make(map[string][]int)
+	for _, i := range order {
+		key := recordKey(records[i])
+		groups[key] = append(groups[key], i)
+	}
+	next := make(map[string]int, len(groups))
+	for slot, i := range order {
+		key := recordKey(records[i])
+		group := groups[key]
+		if next[key] == 0 {
+			sort.Ints(group)
+		}
+		order[slot] = group[next[key]]
+		next[key]++
+	}
+	return order
+}
+
+// recordKey returns the key of the record used to group records that must
+// not be reordered.
+func recordKey(r opencdc.Record) string {
+	if r.Key == nil {
+		return ""
+	}
+	return string(r.Key.Bytes())
+}
+
+// writtenPrefix returns the number of records at the start of the batch that
+// were written. Records are acknowledged in order, so a record sent out of
+// order only counts as written once all records before it are written.
+func writtenPrefix(written []bool) int {
+	for i, ok := range written {
+		if !ok {
+			return i
+		}
+	}
+	return len(written)
+}
@@ -0,0 +1,99 @@
+// Copyright © 2024 Meroxa, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pulsar
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/apache/pulsar-client-go/pulsar"
+)
+
+// Error codes that can be listed in DestinationConfig.ProduceRetryableErrors.
+const (
+	ProduceErrorTimeout            = "timeout"
+	ProduceErrorProducerBusy       = "producerBusy"
+	ProduceErrorProducerQueueFull  = "producerQueueFull"
+	ProduceErrorMemoryBufferFull   = "memoryBufferFull"
+	ProduceErrorMessageTooBig      = "messageTooBig"
+	ProduceErrorTopicTerminated    = "topicTerminated"
+	ProduceErrorSchemaIncompatible = "schemaIncompatible"
+	ProduceErrorProducerFenced     = "producerFenced"
+)
+
+// produceErrorMatchers detect the error codes in errors returned when sending
+// a message. Some errors reported by the broker are only available as text.
+var produceErrorMatchers = map[string]func(error) bool{
+	ProduceErrorTimeout: func(err error) bool {
+		return errors.Is(err, context.DeadlineExceeded) || hasResult(err, pulsar.TimeoutError)
+	},
+	ProduceErrorProducerBusy: func(err error) bool {
+		return strings.Contains(err.Error(), "ProducerBusy")
+	},
+	ProduceErrorProducerQueueFull: func(err error) bool {
+		return hasResult(err, pulsar.ProducerQueueIsFull)
+	},
+	ProduceErrorMemoryBufferFull: func(err error) bool {
+		return hasResult(err, pulsar.ClientMemoryBufferIsFull)
+	},
+	ProduceErrorMessageTooBig: func(err error) bool {
+		return hasResult(err, pulsar.MessageTooBig)
+	},
+	ProduceErrorTopicTerminated: func(err error) bool {
+		return hasResult(err, pulsar.TopicTerminated)
+	},
+	ProduceErrorSchemaIncompatible: func(err error) bool {
+		return hasResult(err, pulsar.SchemaFailure) || strings.Contains(err.Error(), "IncompatibleSchema")
+	},
+	ProduceErrorProducerFenced: func(err error) bool {
+		return hasResult(err, pulsar.ProducerFenced)
+	},
+}
+
+// validateProduceErrors checks that all codes are known.
+func validateProduceErrors(codes []string) error {
+	for _, code := range codes {
+		if _, ok := produceErrorMatchers[code]; !ok {
+			known := make([]string, 0, len(produceErrorMatchers))
+			for k := range produceErrorMatchers {
+				known = append(known, k)
+			}
+			slices.Sort(known)
+			return fmt.Errorf("unknown error code %q, expected one of %s", code, strings.Join(known, ", "))
+		}
+	}
+	return nil
+}
+
+// isRetryableProduceError returns true if the error matches one of the codes.
+func isRetryableProduceError(codes []string, err error) bool {
+	if err == nil {
+		return false
+	}
+	for _, code := range codes {
+		if produceErrorMatchers[code](err) {
+			return true
+		}
+	}
+	return false
+}
+
+func hasResult(err error, result pulsar.Result) bool {
+	var pulsarErr *pulsar.Error
+	return errors.As(err, &pulsarErr) && pulsarErr.Result() == result
+}
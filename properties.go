@@ -0,0 +1,46 @@
+// Copyright © 2024 Meroxa, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pulsar
+
+import (
+	"strings"
+
+	"github.com/conduitio/conduit-commons/opencdc"
+)
+
+// metadataProperties returns the message properties stored in the metadata
+// by the source, i.e. the metadata under metadataPropertiesPrefix with the
+// prefix stripped. If all is true, the remaining metadata is included as is,
+// unless a prefixed key has the same name, which always takes precedence.
+// It returns nil if there are no properties.
+func metadataProperties(metadata opencdc.Metadata, all bool) map[string]string {
+	var properties map[string]string
+	for key, val := range metadata {
+		name, ok := strings.CutPrefix(key, metadataPropertiesPrefix)
+		if !ok {
+			if !all {
+				continue
+			}
+			if _, shadowed := metadata[metadataPropertiesPrefix+key]; shadowed {
+				continue
+			}
+		}
+		if properties == nil {
+			properties = make(map[string]string)
+		}
+		properties[name] = val
+	}
+	return properties
+}
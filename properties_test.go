@@ -0,0 +1,90 @@
+// Copyright © 2024 Meroxa, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pulsar
+
+import (
+	"context"
+	"testing"
+
+	"github.com/apache/pulsar-client-go/pulsar"
+	"github.com/conduitio/conduit-commons/opencdc"
+	"github.com/matryer/is"
+)
+
+func TestMetadataProperties(t *testing.T) {
+	metadata := opencdc.Metadata{
+		"pulsar.properties.origin": "billing",
+		"pulsar.topic":             "orders",
+	}
+
+	testCases := []struct {
+		name string
+		all  bool
+		want map[string]string
+	}{
+		{name: "properties only", want: map[string]string{"origin": "billing"}},
+		{name: "all metadata", all: true, want: map[string]string{"origin": "billing", "pulsar.topic": "orders"}},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			is := is.New(t)
+			is.Equal(metadataProperties(metadata, tc.all), tc.want)
+		})
+	}
+}
+
+func TestMetadataProperties_PrefixedKeyWins(t *testing.T) {
+	is := is.New(t)
+
+	metadata := opencdc.Metadata{
+		"pulsar.properties.origin": "billing",
+		"origin":                   "shipping",
+	}
+
+	// metadata is iterated in random order, the prefixed key has to win in
+	// every iteration
+	for i := 0; i < 100; i++ {
+		is.Equal(metadataProperties(metadata, true), map[string]string{"origin": "billing"})
+	}
+}
+
+func TestMetadataProperties_None(t *testing.T) {
+	is := is.New(t)
+	is.Equal(metadataProperties(opencdc.Metadata{"pulsar.topic": "orders"}, false), nil)
+}
+
+func TestProperties_RoundTrip(t *testing.T) {
+	is := is.New(t)
+	ctx := context.Background()
+
+	msg := propertiesMessage{
+		readableMessage: readableMessage{fakeMessage{topic: "test-topic", id: pulsar.NewMessageID(1, 1, 0, 0)}},
+		properties:      map[string]string{"origin": "billing", "traceID": "abc123"},
+	}
+	source := &Source{
+		consumer: &queueConsumer{messages: []pulsar.Message{msg}},
+	}
+	producer := &recordingProducer{}
+	destination := &Destination{producer: producer}
+
+	rec, err := source.Read(ctx)
+	is.NoErr(err)
+
+	written, err := destination.Write(ctx, []opencdc.Record{rec})
+	is.NoErr(err)
+	is.Equal(written, 1)
+	is.Equal(producer.sent[0].Properties, msg.properties)
+}
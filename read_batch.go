@@ -0,0 +1,63 @@
+// Copyright © 2024 Meroxa, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pulsar
+
+import (
+	"context"
+
+	"github.com/apache/pulsar-client-go/pulsar"
+)
+
+// receiveBatched returns the next buffered message. If the buffer is empty it
+// waits for the next message and buffers the messages that are already
+// available, up to ReadBatchSize or the size of the receive queue.
+func (s *Source) receiveBatched(ctx context.Context) (pulsar.Message, error) {
+	if len(s.batch) == 0 {
+		msg, err := s.consumer.Receive(ctx)
+		if err != nil {
+			return nil, err
+		}
+		s.batch = append(s.batch, msg)
+		s.fillBatch()
+		if s.receiverQueue != nil {
+			s.receiverQueue.filled(len(s.batch))
+		}
+	}
+
+	msg := s.batch[0]
+	s.batch[0] = nil
+	s.batch = s.batch[1:]
+	return msg, nil
+}
+
+// fillBatch buffers messages of the consumer without waiting for new ones.
+func (s *Source) fillBatch() {
+	size := s.config.ReadBatchSize
+	if s.receiverQueue != nil {
+		size = s.receiverQueue.size
+	}
+	messages := s.consumer.Chan()
+	for len(s.batch) < size {
+		select {
+		case cm, ok := <-messages:
+			if !ok {
+				return
+			}
+			s.batch = append(s.batch, cm.Message)
+		default:
+			return
+		}
+	}
+}
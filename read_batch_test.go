@@ -0,0 +1,99 @@
+// Copyright © 2024 Meroxa, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pulsar
+
+import (
+	"context"
+	"testing"
+
+	"github.com/apache/pulsar-client-go/pulsar"
+	"github.com/matryer/is"
+)
+
+// batchConsumer returns the first message on Receive and exposes the rest on
+// its channel.
+type batchConsumer struct {
+	queueConsumer
+
+	ch       chan pulsar.ConsumerMessage
+	receives int
+	acked    []pulsar.MessageID
+}
+
+func (c *batchConsumer) Receive(ctx context.Context) (pulsar.Message, error) {
+	c.receives++
+	return c.queueConsumer.Receive(ctx)
+}
+
+func (c *batchConsumer) Chan() <-chan pulsar.ConsumerMessage { return c.ch }
+
+func (c *batchConsumer) AckID(id pulsar.MessageID) error {
+	c.acked = append(c.acked, id)
+	return nil
+}
+
+func TestSource_Read_Batched(t *testing.T) {
+	is := is.New(t)
+	ctx := context.Background()
+
+	consumer := &batchConsumer{
+		queueConsumer: queueConsumer{messages: []pulsar.Message{
+			readableMessage{fakeMessage{topic: "test-topic", id: pulsar.NewMessageID(1, 1, 0, 0)}},
+		}},
+		ch: make(chan pulsar.ConsumerMessage, 3),
+	}
+	for i := int64(2); i <= 4; i++ {
+		consumer.ch <- pulsar.ConsumerMessage{
+			Message: readableMessage{fakeMessage{topic: "test-topic", id: pulsar.NewMessageID(1, i, 0, 0)}},
+		}
+	}
+
+	underTest := &Source{
+		consumer: consumer,
+		config:   SourceConfig{Config: Config{Topic: "test-topic"}, ReadBatchSize: 3},
+	}
+
+	for i := 0; i < 3; i++ {
+		rec, err := underTest.Read(ctx)
+		is.NoErr(err)
+		is.NoErr(underTest.Ack(ctx, rec.Position))
+	}
+
+	// the batch is filled up to its size, the last message stays on the channel
+	is.Equal(consumer.receives, 1)
+	is.Equal(len(consumer.ch), 1)
+	is.Equal(len(consumer.acked), 3)
+	for i, id := range consumer.acked {
+		is.Equal(id.EntryID(), int64(i+1))
+	}
+}
+
+func TestSourceConfig_Validate_ReadBatchSize(t *testing.T) {
+	is := is.New(t)
+	ctx := context.Background()
+
+	cfg := newSourceCfg("topic")
+	cfg[SourceConfigReadBatchSize] = "-1"
+	is.True((&Source{}).Configure(ctx, cfg) != nil)
+
+	cfg = newSourceCfg("topic")
+	cfg[SourceConfigReadBatchSize] = "100"
+	cfg[SourceConfigMessageListenerMode] = "true"
+	is.True((&Source{}).Configure(ctx, cfg) != nil)
+
+	cfg = newSourceCfg("topic")
+	cfg[SourceConfigReadBatchSize] = "100"
+	is.NoErr((&Source{}).Configure(ctx, cfg))
+}
@@ -0,0 +1,96 @@
+// Copyright © 2024 Meroxa, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pulsar
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+
+	"github.com/apache/pulsar-client-go/pulsar"
+	"github.com/conduitio/conduit-commons/opencdc"
+	sdk "github.com/conduitio/conduit-connector-sdk"
+)
+
+// parseReaderStartMessageID decodes a base64 encoded serialized message ID,
+// the same representation used for the message ID in the position.
+func parseReaderStartMessageID(s string) (pulsar.MessageID, error) {
+	bs, err := base64.StdEncoding.DecodeString(s)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode message ID: %w", err)
+	}
+	msgID, err := pulsar.DeserializeMessageID(bs)
+	if err != nil {
+		return nil, fmt.Errorf("failed to deserialize message ID: %w", err)
+	}
+	return msgID, nil
+}
+
+// openReader creates a reader that replays the topic starting at the
+// configured message ID, or right after the message in the position when
+// resuming.
+func (s *Source) openReader(ctx context.Context, pos opencdc.Position) error {
+	// the start message ID was validated when configuring the source
+	startID, _ := parseReaderStartMessageID(s.config.ReaderStartMessageID)
+	inclusive := true
+
+	if pos != nil {
+		p, err := parsePosition(pos)
+		if err != nil {
+			return err
+		}
+		startID, err = pulsar.DeserializeMessageID(p.MessageID)
+		if err != nil {
+			return fmt.Errorf("failed to deserialize message ID: %w", err)
+		}
+		inclusive = false
+		s.readerCount = p.ReaderCount
+
+		sdk.Logger(ctx).Info().
+			Str("messageID", startID.String()).
+			Int("readerCount", s.readerCount).
+			Msg("resuming reader from position")
+	}
+
+	var err error
+	s.reader, err = s.client.CreateReader(pulsar.ReaderOptions{
+		Topic:                   s.config.topics()[0],
+		StartMessageID:          startID,
+		StartMessageIDInclusive: inclusive,
+		Schema:                  s.schema,
+		BackoffPolicyFunc:       newReconnectBackoff(s.config.MaxBackoff),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to create reader: %w", err)
+	}
+	sdk.Logger(ctx).Debug().Msg("created pulsar reader")
+
+	return nil
+}
+
+// readNext returns the next message of the reader, or sdk.ErrBackoffRetry
+// once the configured number of messages has been read.
+func (s *Source) readNext(ctx context.Context) (pulsar.Message, error) {
+	if s.config.ReaderMessageLimit > 0 && s.readerCount >= s.config.ReaderMessageLimit {
+		return nil, sdk.ErrBackoffRetry
+	}
+
+	msg, err := s.reader.Next(ctx)
+	if err != nil {
+		return nil, err
+	}
+	s.readerCount++
+	return msg, nil
+}
@@ -0,0 +1,79 @@
+// Copyright © 2024 Meroxa, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pulsar
+
+import (
+	"fmt"
+
+	"github.com/apache/pulsar-client-go/pulsar"
+)
+
+// defaultReceiverQueueSize is the receive queue size the Pulsar client uses
+// when none is configured.
+const defaultReceiverQueueSize = 1000
+
+// receiverQueueSizePerPartition returns the receive queue size of each
+// partition consumer, so the queues of all partitions together hold at most
+// MaxTotalReceiverQueueSizeAcrossPartitions messages. The Go client doesn't
+// support the limit natively, it creates a queue of ReceiverQueueSize
+// messages for every partition.
+func receiverQueueSizePerPartition(client pulsar.Client, cfg SourceConfig) (int, error) {
+	var partitions int
+	for _, topic := range cfg.topics() {
+		p, err := client.TopicPartitions(topic)
+		if err != nil {
+			return 0, fmt.Errorf("failed to get partitions of topic %q: %w", topic, err)
+		}
+		partitions += len(p)
+	}
+
+	size := cfg.ReceiverQueueSize
+	if size == 0 {
+		size = defaultReceiverQueueSize
+	}
+	if partitions == 0 {
+		return size, nil
+	}
+	return max(min(size, cfg.MaxTotalReceiverQueueSizeAcrossPartitions/partitions), 1), nil
+}
+
+// adaptiveReceiverQueue sizes the buffer of received messages to the
+// consumption rate. The size doubles when a fill of the buffer uses it
+// completely, as more messages are waiting than it holds, and halves when
+// less than half of it is used.
+type adaptiveReceiverQueue struct {
+	min  int
+	max  int
+	size int
+}
+
+func newAdaptiveReceiverQueue(min, max int) *adaptiveReceiverQueue {
+	return &adaptiveReceiverQueue{
+		min:  min,
+		max:  max,
+		size: min,
+	}
+}
+
+// filled adjusts the size to the number of messages the last fill of the
+// buffer took from the consumer.
+func (q *adaptiveReceiverQueue) filled(n int) {
+	switch {
+	case n >= q.size:
+		q.size = min(q.size*2, q.max)
+	case n < q.size/2:
+		q.size = max(q.size/2, q.min)
+	}
+}
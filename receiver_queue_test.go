@@ -0,0 +1,188 @@
+// Copyright © 2024 Meroxa, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pulsar
+
+import (
+	"context"
+	"testing"
+
+	"github.com/apache/pulsar-client-go/pulsar"
+	"github.com/matryer/is"
+)
+
+func TestReceiverQueueSizePerPartition(t *testing.T) {
+	testCases := []struct {
+		name              string
+		partitions        int
+		topics            []string
+		receiverQueueSize int
+		maxTotal          int
+		want              int
+	}{
+		{name: "split across partitions", partitions: 4, maxTotal: 1000, want: 250},
+		{name: "split across topics", partitions: 4, topics: []string{"topic-a", "topic-b"}, maxTotal: 1000, want: 125},
+		{name: "receiver queue size is smaller", partitions: 4, receiverQueueSize: 100, maxTotal: 1000, want: 100},
+		{name: "client default is smaller", partitions: 1, maxTotal: 5000, want: defaultReceiverQueueSize},
+		{name: "at least one message", partitions: 8, maxTotal: 4, want: 1},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			is := is.New(t)
+
+			cfg := SourceConfig{
+				Config:            Config{Topic: "test-topic"},
+				ReceiverQueueSize: tc.receiverQueueSize,
+				MaxTotalReceiverQueueSizeAcrossPartitions: tc.maxTotal,
+			}
+			if len(tc.topics) > 0 {
+				cfg.Topic = ""
+				cfg.Topics = tc.topics
+			}
+
+			got, err := receiverQueueSizePerPartition(&partitionedClient{partitions: tc.partitions}, cfg)
+			is.NoErr(err)
+			is.Equal(got, tc.want)
+		})
+	}
+}
+
+func TestSource_Configure_ReceiverQueueSize(t *testing.T) {
+	testCases := []struct {
+		name    string
+		cfg     map[string]string
+		wantErr bool
+	}{{
+		name: "unset",
+		cfg:  map[string]string{},
+	}, {
+		name: "valid",
+		cfg: map[string]string{
+			SourceConfigReceiverQueueSize:                         "5000",
+			SourceConfigMaxTotalReceiverQueueSizeAcrossPartitions: "50000",
+		},
+	}, {
+		name:    "negative receiver queue size",
+		cfg:     map[string]string{SourceConfigReceiverQueueSize: "-1"},
+		wantErr: true,
+	}, {
+		name:    "negative max total",
+		cfg:     map[string]string{SourceConfigMaxTotalReceiverQueueSizeAcrossPartitions: "-1"},
+		wantErr: true,
+	}, {
+		name: "auto scaling bounds",
+		cfg: map[string]string{
+			SourceConfigAutoScaleReceiverQueue:        "true",
+			SourceConfigAutoScaleReceiverQueueMinSize: "10",
+			SourceConfigAutoScaleReceiverQueueMaxSize: "100",
+		},
+	}, {
+		name: "auto scaling min greater than max",
+		cfg: map[string]string{
+			SourceConfigAutoScaleReceiverQueue:        "true",
+			SourceConfigAutoScaleReceiverQueueMinSize: "100",
+			SourceConfigAutoScaleReceiverQueueMaxSize: "10",
+		},
+		wantErr: true,
+	}, {
+		name: "auto scaling min not positive",
+		cfg: map[string]string{
+			SourceConfigAutoScaleReceiverQueue:        "true",
+			SourceConfigAutoScaleReceiverQueueMinSize: "0",
+		},
+		wantErr: true,
+	}, {
+		name: "auto scaling combined with read batch size",
+		cfg: map[string]string{
+			SourceConfigAutoScaleReceiverQueue: "true",
+			SourceConfigReadBatchSize:          "10",
+		},
+		wantErr: true,
+	}, {
+		name: "combined with auto scaling",
+		cfg: map[string]string{
+			SourceConfigReceiverQueueSize:      "5000",
+			SourceConfigAutoScaleReceiverQueue: "true",
+		},
+		wantErr: true,
+	}}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			is := is.New(t)
+
+			cfgMap := newSourceCfg("topic")
+			for key, val := range tc.cfg {
+				cfgMap[key] = val
+			}
+
+			err := (&Source{}).Configure(context.Background(), cfgMap)
+			is.Equal(err != nil, tc.wantErr)
+		})
+	}
+}
+
+func TestSource_Read_AutoScaleReceiverQueue(t *testing.T) {
+	is := is.New(t)
+	ctx := context.Background()
+
+	consumer := &batchConsumer{ch: make(chan pulsar.ConsumerMessage, 8)}
+	underTest := &Source{
+		consumer:      consumer,
+		config:        SourceConfig{Config: Config{Topic: "test-topic"}},
+		receiverQueue: newAdaptiveReceiverQueue(2, 8),
+	}
+
+	// each step makes the given number of messages available, the queue grows
+	// while it is filled completely and shrinks when it is mostly empty
+	testCases := []struct {
+		available int
+		wantSize  int
+	}{
+		{available: 8, wantSize: 4},
+		{available: 8, wantSize: 8},
+		{available: 8, wantSize: 8},
+		{available: 1, wantSize: 4},
+		{available: 1, wantSize: 2},
+		{available: 1, wantSize: 2},
+	}
+
+	var entryID int64
+	for _, tc := range testCases {
+		for i := 0; i < tc.available; i++ {
+			entryID++
+			msg := readableMessage{fakeMessage{topic: "test-topic", id: pulsar.NewMessageID(1, entryID, 0, 0)}}
+			if i == 0 {
+				consumer.messages = append(consumer.messages, msg)
+			} else {
+				consumer.ch <- pulsar.ConsumerMessage{Message: msg}
+			}
+		}
+
+		// the first read fills the buffer and adjusts the size
+		_, err := underTest.Read(ctx)
+		is.NoErr(err)
+		is.Equal(underTest.receiverQueue.size, tc.wantSize)
+
+		for len(underTest.batch) > 0 {
+			_, err := underTest.Read(ctx)
+			is.NoErr(err)
+		}
+		// messages that didn't fit into the queue aren't needed anymore
+		for len(consumer.ch) > 0 {
+			<-consumer.ch
+		}
+	}
+}
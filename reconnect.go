@@ -0,0 +1,69 @@
+// Copyright © 2024 Meroxa, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pulsar
+
+import (
+	"math/rand"
+	"time"
+
+	"github.com/apache/pulsar-client-go/pulsar/backoff"
+)
+
+const (
+	// reconnectMinBackoff is the delay before the first reconnection attempt,
+	// same as in the client.
+	reconnectMinBackoff = 100 * time.Millisecond
+	// reconnectJitter is the fraction of the delay added as random jitter.
+	reconnectJitter = 0.2
+)
+
+// reconnectBackoff is the exponential backoff of the client used when
+// reconnecting consumers, producers and readers to a broker, with a
+// configurable upper bound.
+type reconnectBackoff struct {
+	max     time.Duration
+	backoff time.Duration
+}
+
+var _ backoff.Policy = (*reconnectBackoff)(nil)
+
+// newReconnectBackoff returns a function creating reconnection policies that
+// back off up to maxBackoff.
+func newReconnectBackoff(maxBackoff time.Duration) func() backoff.Policy {
+	return func() backoff.Policy {
+		return &reconnectBackoff{max: maxBackoff}
+	}
+}
+
+func (b *reconnectBackoff) Next() time.Duration {
+	b.backoff *= 2
+	switch {
+	case b.backoff < reconnectMinBackoff:
+		b.backoff = reconnectMinBackoff
+	case b.backoff > b.max:
+		b.backoff = b.max
+	}
+	//nolint:gosec // jitter doesn't need a secure random number
+	jitter := rand.Float64() * float64(b.backoff) * reconnectJitter
+	return b.backoff + time.Duration(jitter)
+}
+
+func (b *reconnectBackoff) IsMaxBackoffReached() bool {
+	return b.backoff >= b.max
+}
+
+func (b *reconnectBackoff) Reset() {
+	b.backoff = 0
+}
@@ -0,0 +1,72 @@
+// Copyright © 2024 Meroxa, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pulsar
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/matryer/is"
+)
+
+func TestReconnectBackoff(t *testing.T) {
+	is := is.New(t)
+
+	policy := newReconnectBackoff(time.Second)()
+	between := func(d, lower time.Duration) bool {
+		return d >= lower && d <= lower+time.Duration(float64(lower)*reconnectJitter)
+	}
+
+	is.True(between(policy.Next(), 100*time.Millisecond))
+	is.True(between(policy.Next(), 200*time.Millisecond))
+	is.True(between(policy.Next(), 400*time.Millisecond))
+	is.True(between(policy.Next(), 800*time.Millisecond))
+	is.True(!policy.IsMaxBackoffReached())
+
+	// the delay is capped at the max backoff
+	is.True(between(policy.Next(), time.Second))
+	is.True(between(policy.Next(), time.Second))
+	is.True(policy.IsMaxBackoffReached())
+
+	policy.Reset()
+	is.True(between(policy.Next(), 100*time.Millisecond))
+}
+
+func TestSource_Configure_KeepAliveAndMaxBackoff(t *testing.T) {
+	testCases := []struct {
+		name    string
+		key     string
+		value   string
+		wantErr bool
+	}{
+		{name: "keep alive interval", key: SourceConfigKeepAliveInterval, value: "10s"},
+		{name: "zero keep alive interval", key: SourceConfigKeepAliveInterval, value: "0s", wantErr: true},
+		{name: "max backoff", key: SourceConfigMaxBackoff, value: "5s"},
+		{name: "negative max backoff", key: SourceConfigMaxBackoff, value: "-1s", wantErr: true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			is := is.New(t)
+
+			cfgMap := newSourceCfg("topic")
+			cfgMap[tc.key] = tc.value
+
+			err := (&Source{}).Configure(context.Background(), cfgMap)
+			is.Equal(err != nil, tc.wantErr)
+		})
+	}
+}
@@ -0,0 +1,110 @@
+// Copyright © 2024 Meroxa, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pulsar
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/apache/pulsar-client-go/pulsar"
+	sdk "github.com/conduitio/conduit-connector-sdk"
+)
+
+// maxRetryBackoff caps the delay between attempts, so a high number of
+// retries doesn't result in hours between attempts.
+const maxRetryBackoff = time.Minute
+
+// permanentResults are the results of errors that won't go away by retrying,
+// e.g. an invalid configuration or a closed producer.
+var permanentResults = []pulsar.Result{
+	pulsar.InvalidConfiguration,
+	pulsar.InvalidTopicName,
+	pulsar.InvalidURL,
+	pulsar.AuthenticationError,
+	pulsar.AuthorizationError,
+	pulsar.AlreadyClosedError,
+	pulsar.ProducerClosed,
+	pulsar.ConsumerClosed,
+	pulsar.OperationNotSupported,
+	pulsar.UnsupportedVersionError,
+	pulsar.TopicTerminated,
+	pulsar.ProducerFenced,
+}
+
+// isRetryableError returns true unless the error is known to be permanent.
+func isRetryableError(err error) bool {
+	var pulsarErr *pulsar.Error
+	if !errors.As(err, &pulsarErr) {
+		return true
+	}
+	for _, result := range permanentResults {
+		if pulsarErr.Result() == result {
+			return false
+		}
+	}
+	return true
+}
+
+// retryWithBackoff calls fn until it succeeds, fails with an error that isn't
+// retryable or maxRetries retries have been exhausted. The delay between
+// attempts starts at backoff and doubles after every failed attempt, up to
+// maxRetryBackoff. It returns early if ctx is cancelled.
+func retryWithBackoff(ctx context.Context, maxRetries int, backoff time.Duration, fn func() error) error {
+	return retryWithBackoffIf(ctx, maxRetries, backoff, isRetryableError, fn)
+}
+
+// retryWithBackoffIf behaves like retryWithBackoff, but only retries errors
+// for which retryable returns true. The returned error wraps the error of the
+// last attempt.
+func retryWithBackoffIf(
+	ctx context.Context,
+	maxRetries int,
+	backoff time.Duration,
+	retryable func(error) bool,
+	fn func() error,
+) error {
+	err := fn()
+	attempt := 1
+	for ; err != nil && retryable(err) && attempt <= maxRetries; attempt++ {
+		sdk.Logger(ctx).Warn().Err(err).
+			Int("attempt", attempt).
+			Dur("backoff", backoff).
+			Msg("operation failed, retrying")
+
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("stopped retrying: %w: %w", ctx.Err(), err)
+		case <-time.After(backoff):
+		}
+
+		backoff = nextBackoff(backoff)
+		err = fn()
+	}
+	if err != nil && attempt > 1 {
+		return fmt.Errorf("failed after %d attempts: %w", attempt, err)
+	}
+	return err
+}
+
+// nextBackoff doubles the backoff up to maxRetryBackoff. Backoffs that are
+// configured above the cap are kept.
+func nextBackoff(backoff time.Duration) time.Duration {
+	if backoff >= maxRetryBackoff {
+		return backoff
+	}
+	return min(backoff*2, maxRetryBackoff)
+}
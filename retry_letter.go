@@ -0,0 +1,80 @@
+// Copyright © 2024 Meroxa, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pulsar
+
+import (
+	"math"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/apache/pulsar-client-go/pulsar"
+)
+
+// retryTracker keeps the read messages that were not acked yet, so failed
+// records can be retried from the retry letter topic, which requires the
+// message and not only its ID. Read, Ack and Nack can be called
+// concurrently.
+type retryTracker struct {
+	mu       sync.Mutex
+	messages map[string]pulsar.Message
+}
+
+func newRetryTracker() *retryTracker {
+	return &retryTracker{messages: make(map[string]pulsar.Message)}
+}
+
+func (t *retryTracker) add(msg pulsar.Message) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.messages[string(msg.ID().Serialize())] = msg
+}
+
+// remove stops tracking the message with the serialized ID and returns it,
+// or nil if it isn't tracked.
+func (t *retryTracker) remove(serializedID []byte) pulsar.Message {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	msg := t.messages[string(serializedID)]
+	delete(t.messages, string(serializedID))
+	return msg
+}
+
+// redeliver retries the message from the retry letter topic after the retry
+// delay if retries are enabled, otherwise it is negatively acknowledged.
+func (s *Source) redeliver(msg pulsar.Message) {
+	s.consumerMu.RLock()
+	defer s.consumerMu.RUnlock()
+	if s.config.EnableRetry {
+		s.consumer.ReconsumeLater(msg, s.retryDelay(msg))
+		return
+	}
+	s.consumer.NackID(msg.ID())
+}
+
+// retryDelay returns the delay before the message is retried. The delay grows
+// exponentially with the number of times the message was already retried,
+// which the client stores in the message properties.
+func (s *Source) retryDelay(msg pulsar.Message) time.Duration {
+	if s.config.RetryDelayMultiplier <= 1 {
+		return s.config.RetryDelay
+	}
+	retries, _ := strconv.Atoi(msg.Properties()[pulsar.SysPropertyReconsumeTimes])
+	delay := float64(s.config.RetryDelay) * math.Pow(s.config.RetryDelayMultiplier, float64(retries))
+	if delay >= math.MaxInt64 {
+		return time.Duration(math.MaxInt64)
+	}
+	return time.Duration(delay)
+}
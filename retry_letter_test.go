@@ -0,0 +1,262 @@
+// Copyright © 2024 Meroxa, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pulsar
+
+import (
+	"context"
+	"math"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/apache/pulsar-client-go/pulsar"
+	"github.com/matryer/is"
+)
+
+// reconsumingConsumer records messages retried from the retry letter topic.
+type reconsumingConsumer struct {
+	rejectRecordingConsumer
+	reconsumed []pulsar.MessageID
+	delays     []time.Duration
+}
+
+func (c *reconsumingConsumer) ReconsumeLater(msg pulsar.Message, delay time.Duration) {
+	c.reconsumed = append(c.reconsumed, msg.ID())
+	c.delays = append(c.delays, delay)
+}
+
+func TestNewDLQPolicy_Retry(t *testing.T) {
+	testCases := []struct {
+		name                 string
+		cfg                  SourceConfig
+		wantMaxDeliveries    uint32
+		wantDeadLetterTopic  string
+		wantRetryLetterTopic string
+	}{{
+		name:                 "default topics",
+		cfg:                  SourceConfig{Config: Config{Topic: "orders"}, SubscriptionName: "sub", EnableRetry: true},
+		wantMaxDeliveries:    pulsar.MaxReconsumeTimes,
+		wantDeadLetterTopic:  "orders-sub-DLQ",
+		wantRetryLetterTopic: "orders-sub-RETRY",
+	}, {
+		name: "with dead letter policy",
+		cfg: SourceConfig{
+			Config:           Config{Topic: "orders"},
+			SubscriptionName: "sub",
+			EnableRetry:      true,
+			RetryLetterTopic: "orders-retry",
+			DLQTopic:         "orders-dlq",
+			DLQMaxDeliveries: 3,
+		},
+		wantMaxDeliveries:    3,
+		wantDeadLetterTopic:  "orders-dlq",
+		wantRetryLetterTopic: "orders-retry",
+	}}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			is := is.New(t)
+
+			policy := newDLQPolicy(tc.cfg)
+			is.Equal(policy.MaxDeliveries, tc.wantMaxDeliveries)
+			is.Equal(policy.DeadLetterTopic, tc.wantDeadLetterTopic)
+			is.Equal(policy.RetryLetterTopic, tc.wantRetryLetterTopic)
+		})
+	}
+}
+
+// topicProducer is a producer of a topic.
+type topicProducer struct {
+	pulsar.Producer
+	topic string
+}
+
+func (p topicProducer) Topic() string { return p.topic }
+
+func TestNewDLQPolicy_RetryMessageUnchanged(t *testing.T) {
+	is := is.New(t)
+
+	policy := newDLQPolicy(SourceConfig{
+		Config:                  Config{Topic: "orders"},
+		SubscriptionName:        "sub",
+		EnableRetry:             true,
+		DLQMaxDeliveries:        3,
+		DLQDiagnosticProperties: true,
+		DLQSchemaDefinition:     testDLQSchema,
+	})
+	newMessage := func() *pulsar.ProducerMessage {
+		return &pulsar.ProducerMessage{
+			Payload:    []byte("test-payload"),
+			Properties: map[string]string{pulsar.SysPropertyRealTopic: "persistent://public/default/orders"},
+		}
+	}
+
+	// the client produces to the retry letter topic with the options of the
+	// dead letter producer
+	retried := newMessage()
+	policy.ProducerOptions.Interceptors.BeforeSend(topicProducer{topic: "persistent://public/default/orders-sub-RETRY"}, retried)
+	is.Equal(retried, newMessage())
+
+	dead := newMessage()
+	policy.ProducerOptions.Interceptors.BeforeSend(topicProducer{topic: "persistent://public/default/orders-sub-DLQ"}, dead)
+	is.True(string(dead.Payload) != "test-payload")
+	is.Equal(dead.Properties[dlqPropertyFailureReason], dlqFailureReasonMaxDeliveries)
+}
+
+func TestIsSameTopic(t *testing.T) {
+	is := is.New(t)
+
+	is.True(isSameTopic("orders-DLQ", "persistent://public/default/orders-DLQ"))
+	is.True(isSameTopic("persistent://public/default/orders-DLQ-partition-1", "orders-DLQ"))
+	is.True(!isSameTopic("orders-RETRY", "orders-DLQ"))
+}
+
+func TestSource_Nack_Retry(t *testing.T) {
+	is := is.New(t)
+	ctx := context.Background()
+
+	failed := readableMessage{fakeMessage{topic: "orders", id: pulsar.NewMessageID(1, 1, 0, 0)}}
+	processed := readableMessage{fakeMessage{topic: "orders", id: pulsar.NewMessageID(1, 2, 0, 0)}}
+	consumer := &reconsumingConsumer{rejectRecordingConsumer: rejectRecordingConsumer{
+		queueConsumer: queueConsumer{messages: []pulsar.Message{failed, processed}},
+	}}
+	underTest := &Source{
+		consumer: consumer,
+		config:   SourceConfig{Config: Config{Topic: "orders"}, EnableRetry: true, RetryDelay: 10 * time.Second},
+		retries:  newRetryTracker(),
+	}
+
+	failedRec, err := underTest.Read(ctx)
+	is.NoErr(err)
+	processedRec, err := underTest.Read(ctx)
+	is.NoErr(err)
+
+	is.NoErr(underTest.Nack(ctx, failedRec.Position))
+	is.NoErr(underTest.Ack(ctx, processedRec.Position))
+
+	// the failed record is retried instead of being nacked
+	is.Equal(consumer.reconsumed, []pulsar.MessageID{failed.ID()})
+	is.Equal(consumer.delays, []time.Duration{10 * time.Second})
+	is.Equal(len(consumer.nacked), 0)
+	is.Equal(consumer.acked, []pulsar.MessageID{processed.ID()})
+
+	// acked messages are no longer tracked and can't be retried
+	is.NoErr(underTest.Nack(ctx, processedRec.Position))
+	is.Equal(len(consumer.reconsumed), 1)
+	is.Equal(len(underTest.retries.messages), 0)
+}
+
+func TestSource_Nack_RetryBackoff(t *testing.T) {
+	is := is.New(t)
+	ctx := context.Background()
+
+	// the client increments the reconsume times property each time a message
+	// is retried and routes it to the dead letter topic after DLQMaxDeliveries
+	var messages []pulsar.Message
+	for i := 0; i < 3; i++ {
+		msg := readableMessage{fakeMessage{topic: "orders", id: pulsar.NewMessageID(1, int64(i), 0, 0)}}
+		properties := map[string]string{}
+		if i > 0 {
+			properties[pulsar.SysPropertyReconsumeTimes] = strconv.Itoa(i)
+		}
+		messages = append(messages, propertiesMessage{readableMessage: msg, properties: properties})
+	}
+	consumer := &reconsumingConsumer{rejectRecordingConsumer: rejectRecordingConsumer{
+		queueConsumer: queueConsumer{messages: messages},
+	}}
+	underTest := &Source{
+		consumer: consumer,
+		config: SourceConfig{
+			Config:               Config{Topic: "orders"},
+			EnableRetry:          true,
+			RetryDelay:           time.Second,
+			RetryDelayMultiplier: 2,
+			DLQMaxDeliveries:     3,
+		},
+		retries: newRetryTracker(),
+	}
+
+	for range messages {
+		rec, err := underTest.Read(ctx)
+		is.NoErr(err)
+		is.NoErr(underTest.Nack(ctx, rec.Position))
+	}
+
+	is.Equal(consumer.delays, []time.Duration{time.Second, 2 * time.Second, 4 * time.Second})
+}
+
+func TestSource_RetryDelay_Overflow(t *testing.T) {
+	is := is.New(t)
+
+	underTest := &Source{config: SourceConfig{RetryDelay: time.Hour, RetryDelayMultiplier: 10}}
+	msg := propertiesMessage{properties: map[string]string{pulsar.SysPropertyReconsumeTimes: "100"}}
+	is.Equal(underTest.retryDelay(msg), time.Duration(math.MaxInt64))
+}
+
+func TestSource_Configure_Retry(t *testing.T) {
+	testCases := []struct {
+		name    string
+		cfg     map[string]string
+		wantErr bool
+	}{
+		{name: "enabled", cfg: map[string]string{SourceConfigEnableRetry: "true"}},
+		{name: "with dead letter policy", cfg: map[string]string{
+			SourceConfigEnableRetry:      "true",
+			SourceConfigRetryLetterTopic: "topic-retry",
+			SourceConfigDlqMaxDeliveries: "3",
+		}},
+		{name: "retry topic without retry", cfg: map[string]string{
+			SourceConfigRetryLetterTopic: "topic-retry",
+		}, wantErr: true},
+		{name: "negative delay", cfg: map[string]string{
+			SourceConfigEnableRetry: "true",
+			SourceConfigRetryDelay:  "-1s",
+		}, wantErr: true},
+		{name: "exponential delay", cfg: map[string]string{
+			SourceConfigEnableRetry:          "true",
+			SourceConfigRetryDelay:           "10s",
+			SourceConfigRetryDelayMultiplier: "1.5",
+		}},
+		{name: "multiplier below 1", cfg: map[string]string{
+			SourceConfigEnableRetry:          "true",
+			SourceConfigRetryDelayMultiplier: "0.5",
+		}, wantErr: true},
+		{name: "multiple topics", cfg: map[string]string{
+			SourceConfigTopic:       "",
+			SourceConfigTopics:      "topic-1,topic-2",
+			SourceConfigEnableRetry: "true",
+		}, wantErr: true},
+		{name: "topics pattern", cfg: map[string]string{
+			SourceConfigTopic:            "",
+			SourceConfigTopicsPattern:    "topic-.*",
+			SourceConfigEnableRetry:      "true",
+			SourceConfigRetryLetterTopic: "topic-retry",
+			SourceConfigDlqTopic:         "topic-dlq",
+		}, wantErr: true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			is := is.New(t)
+
+			cfgMap := newSourceCfg("topic")
+			for k, v := range tc.cfg {
+				cfgMap[k] = v
+			}
+			err := (&Source{}).Configure(context.Background(), cfgMap)
+			is.Equal(err != nil, tc.wantErr)
+		})
+	}
+}
@@ -0,0 +1,127 @@
+// Copyright © 2024 Meroxa, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pulsar
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/apache/pulsar-client-go/pulsar"
+	"github.com/matryer/is"
+)
+
+var errSchemaRegistryUnavailable = errors.New("schema registry unavailable")
+
+func TestRetryWithBackoff_Recovers(t *testing.T) {
+	is := is.New(t)
+
+	// the schema registry becomes available on the third attempt
+	var attempts int
+	err := retryWithBackoff(context.Background(), 5, time.Millisecond, func() error {
+		attempts++
+		if attempts < 3 {
+			return errSchemaRegistryUnavailable
+		}
+		return nil
+	})
+	is.NoErr(err)
+	is.Equal(attempts, 3)
+}
+
+func TestRetryWithBackoff_ExhaustsRetries(t *testing.T) {
+	is := is.New(t)
+
+	var attempts int
+	err := retryWithBackoff(context.Background(), 2, time.Millisecond, func() error {
+		attempts++
+		return errSchemaRegistryUnavailable
+	})
+	is.True(errors.Is(err, errSchemaRegistryUnavailable))
+	is.Equal(err.Error(), "failed after 3 attempts: schema registry unavailable")
+	is.Equal(attempts, 3)
+}
+
+func TestRetryWithBackoff_PermanentError(t *testing.T) {
+	// creating a client without a URL fails with an invalid configuration
+	_, errInvalidConfig := pulsar.NewClient(pulsar.ClientOptions{})
+
+	testCases := []struct {
+		name string
+		err  error
+	}{
+		{name: "producer closed", err: pulsar.ErrProducerClosed},
+		{name: "invalid configuration", err: errInvalidConfig},
+		{name: "wrapped", err: fmt.Errorf("failed to create producer: %w", pulsar.ErrProducerClosed)},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			is := is.New(t)
+			is.True(!isRetryableError(tc.err))
+
+			var attempts int
+			err := retryWithBackoff(context.Background(), 5, time.Millisecond, func() error {
+				attempts++
+				return tc.err
+			})
+			is.Equal(err, tc.err)
+			is.Equal(attempts, 1)
+		})
+	}
+}
+
+func TestIsRetryableError(t *testing.T) {
+	testCases := []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{name: "unknown error", err: errSchemaRegistryUnavailable, want: true},
+		{name: "timeout", err: pulsar.ErrSendTimeout, want: true},
+		{name: "topic not found", err: pulsar.ErrTopicNotfound, want: true},
+		{name: "producer closed", err: pulsar.ErrProducerClosed, want: false},
+		{name: "producer fenced", err: pulsar.ErrProducerFenced, want: false},
+		{name: "topic terminated", err: pulsar.ErrTopicTerminated, want: false},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			is := is.New(t)
+			is.Equal(isRetryableError(tc.err), tc.want)
+		})
+	}
+}
+
+func TestNextBackoff(t *testing.T) {
+	testCases := []struct {
+		backoff time.Duration
+		want    time.Duration
+	}{
+		{backoff: time.Second, want: 2 * time.Second},
+		{backoff: 40 * time.Second, want: maxRetryBackoff},
+		{backoff: maxRetryBackoff, want: maxRetryBackoff},
+		{backoff: 2 * maxRetryBackoff, want: 2 * maxRetryBackoff},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.backoff.String(), func(t *testing.T) {
+			is := is.New(t)
+			is.Equal(nextBackoff(tc.backoff), tc.want)
+		})
+	}
+}
@@ -0,0 +1,88 @@
+// Copyright © 2024 Meroxa, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pulsar
+
+import (
+	"fmt"
+	"hash/fnv"
+	"sync/atomic"
+
+	"github.com/apache/pulsar-client-go/pulsar"
+)
+
+// Supported values of DestinationConfig.OrderingGuarantee.
+const (
+	// OrderingGuaranteeNone spreads messages across all partitions without
+	// taking the key into account.
+	OrderingGuaranteeNone = "none"
+	// OrderingGuaranteePartition routes all messages to a single partition,
+	// preserving the order of all messages.
+	OrderingGuaranteePartition = "partition"
+	// OrderingGuaranteeKey routes messages with the same key to the same
+	// partition, preserving the order of messages per key.
+	OrderingGuaranteeKey = "key"
+)
+
+// applyOrderingGuarantee configures the routing of the producer so that
+// messages are delivered with the requested ordering guarantee.
+func applyOrderingGuarantee(guarantee string, opts *pulsar.ProducerOptions) {
+	switch guarantee {
+	case OrderingGuaranteeNone:
+		opts.MessageRouter = newRoundRobinRouter()
+	case OrderingGuaranteePartition:
+		opts.MessageRouter = newSinglePartitionRouter(0)
+	case OrderingGuaranteeKey:
+		// the default router of the client hashes the key, we only need to
+		// make sure batches don't mix messages with different keys
+		opts.BatcherBuilderType = pulsar.KeyBasedBatchBuilder
+	}
+}
+
+func newRoundRobinRouter() func(*pulsar.ProducerMessage, pulsar.TopicMetadata) int {
+	var next atomic.Uint32
+	return func(_ *pulsar.ProducerMessage, md pulsar.TopicMetadata) int {
+		return int((next.Add(1) - 1) % md.NumPartitions())
+	}
+}
+
+func newSinglePartitionRouter(partition int) func(*pulsar.ProducerMessage, pulsar.TopicMetadata) int {
+	return func(*pulsar.ProducerMessage, pulsar.TopicMetadata) int {
+		return partition
+	}
+}
+
+// propertyPartitionKey carries the value of DestinationConfig.PartitionByField
+// the message is routed by.
+const propertyPartitionKey = "conduit.partitionKey"
+
+// routeByPartitionKey routes messages with the same partition key to the same
+// partition.
+func routeByPartitionKey(msg *pulsar.ProducerMessage, md pulsar.TopicMetadata) int {
+	h := fnv.New32a()
+	_, _ = h.Write([]byte(msg.Properties[propertyPartitionKey]))
+	return int(h.Sum32() % md.NumPartitions())
+}
+
+// checkPartition verifies that the topic has the given partition.
+func checkPartition(client pulsar.Client, topic string, partition int) error {
+	partitions, err := client.TopicPartitions(topic)
+	if err != nil {
+		return fmt.Errorf("failed to fetch partitions of topic %q: %w", topic, err)
+	}
+	if partition >= len(partitions) {
+		return fmt.Errorf("topic %q has %d partitions, can't produce to partition %d", topic, len(partitions), partition)
+	}
+	return nil
+}
@@ -0,0 +1,62 @@
+// Copyright © 2024 Meroxa, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pulsar
+
+import (
+	"testing"
+
+	"github.com/apache/pulsar-client-go/pulsar"
+	"github.com/matryer/is"
+)
+
+type topicMetadata uint32
+
+func (m topicMetadata) NumPartitions() uint32 { return uint32(m) }
+
+func TestApplyOrderingGuarantee_None(t *testing.T) {
+	is := is.New(t)
+
+	var opts pulsar.ProducerOptions
+	applyOrderingGuarantee(OrderingGuaranteeNone, &opts)
+
+	// messages with the same key are spread across all partitions
+	var got []int
+	for i := 0; i < 6; i++ {
+		got = append(got, opts.MessageRouter(&pulsar.ProducerMessage{Key: "same-key"}, topicMetadata(3)))
+	}
+	is.Equal(got, []int{0, 1, 2, 0, 1, 2})
+}
+
+func TestApplyOrderingGuarantee_Partition(t *testing.T) {
+	is := is.New(t)
+
+	var opts pulsar.ProducerOptions
+	applyOrderingGuarantee(OrderingGuaranteePartition, &opts)
+
+	for _, key := range []string{"", "key-1", "key-2", "key-3"} {
+		is.Equal(opts.MessageRouter(&pulsar.ProducerMessage{Key: key}, topicMetadata(3)), 0)
+	}
+}
+
+func TestApplyOrderingGuarantee_Key(t *testing.T) {
+	is := is.New(t)
+
+	var opts pulsar.ProducerOptions
+	applyOrderingGuarantee(OrderingGuaranteeKey, &opts)
+
+	// the default router of the client routes by key
+	is.True(opts.MessageRouter == nil)
+	is.Equal(opts.BatcherBuilderType, pulsar.KeyBasedBatchBuilder)
+}
@@ -0,0 +1,71 @@
+// Copyright © 2024 Meroxa, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pulsar
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"strconv"
+)
+
+// Metadata keys describing the schema version of a message when
+// NotifySchemaChange is enabled.
+const (
+	metadataSchemaVersion = "pulsar.schemaVersion"
+	metadataSchemaChanged = "pulsar.schemaChanged"
+)
+
+// schemaVersionTracker remembers the last schema version seen on each topic.
+type schemaVersionTracker struct {
+	versions map[string][]byte
+}
+
+func newSchemaVersionTracker() *schemaVersionTracker {
+	return &schemaVersionTracker{versions: make(map[string][]byte)}
+}
+
+// observe records the schema version of a message and returns true if it
+// differs from the version of the previous message on the same topic. The
+// first version seen on a topic is not considered a change.
+func (t *schemaVersionTracker) observe(topic string, version []byte) bool {
+	last, seen := t.versions[topic]
+	t.versions[topic] = version
+	return seen && !bytes.Equal(last, version)
+}
+
+// formatSchemaVersion formats the schema version the way it is shown by the
+// Pulsar admin tools. Brokers encode the version as a big endian int64, other
+// encodings are formatted as hex.
+func formatSchemaVersion(version []byte) string {
+	if len(version) == 8 {
+		return strconv.FormatInt(int64(binary.BigEndian.Uint64(version)), 10)
+	}
+	return hex.EncodeToString(version)
+}
+
+// parseSchemaVersion parses a schema version as shown by the Pulsar admin
+// tools and returns it in the encoding used by brokers.
+func parseSchemaVersion(version string) ([]byte, error) {
+	v, err := strconv.ParseInt(version, 10, 64)
+	if err != nil {
+		return nil, err
+	}
+	if v < 0 {
+		return nil, errors.New("schema version must not be negative")
+	}
+	return binary.BigEndian.AppendUint64(nil, uint64(v)), nil
+}
@@ -0,0 +1,57 @@
+// Copyright © 2024 Meroxa, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pulsar
+
+// Supported values of SourceConfig.SchemaIncompatibilityAction.
+const (
+	// SchemaIncompatibilityActionSkip acknowledges and skips messages that
+	// don't match the configured schema.
+	SchemaIncompatibilityActionSkip = "skip"
+	// SchemaIncompatibilityActionDLQ routes messages that don't match the
+	// configured schema to the dead letter topic.
+	SchemaIncompatibilityActionDLQ = "dlq"
+	// SchemaIncompatibilityActionRaw passes messages that don't match the
+	// configured schema through with their raw payload.
+	SchemaIncompatibilityActionRaw = "raw"
+)
+
+// metadataSchemaIncompatible is set to the reason a message passed through
+// with SchemaIncompatibilityActionRaw doesn't match the configured schema.
+const metadataSchemaIncompatible = "pulsar.schemaIncompatible"
+
+// schemaFailure returns how a message that doesn't match the configured schema
+// is dropped, depending on SchemaIncompatibilityAction. Without an action it
+// is redelivered, so it is routed to the dead letter topic if one is
+// configured.
+func (s *Source) schemaFailure(reason string, redeliver bool) (string, string, bool) {
+	switch s.config.SchemaIncompatibilityAction {
+	case SchemaIncompatibilityActionSkip:
+		// no failure type, so it isn't routed to a dead letter topic
+		return reason, "", false
+	case SchemaIncompatibilityActionDLQ:
+		return reason, FailureTypeSchema, true
+	default:
+		return reason, FailureTypeSchema, redeliver
+	}
+}
+
+// hasSchemaDLQ returns true if messages rejected for their schema can be
+// routed to a dead letter topic.
+func (c SourceConfig) hasSchemaDLQ() bool {
+	// the failure topics were validated before
+	failureTopics, _ := parseDLQFailureTopics(c.DLQFailureTopics)
+	_, ok := failureTopics[FailureTypeSchema]
+	return ok || c.DLQMaxDeliveries > 0
+}
@@ -0,0 +1,114 @@
+// Copyright © 2024 Meroxa, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pulsar
+
+import (
+	"context"
+	"testing"
+
+	"github.com/apache/pulsar-client-go/pulsar"
+	"github.com/matryer/is"
+)
+
+func TestSource_Read_SchemaIncompatibilityAction(t *testing.T) {
+	v0, _ := parseSchemaVersion("0")
+	v1, _ := parseSchemaVersion("1")
+	incompatible := versionedMessage{readableMessage{fakeMessage{id: pulsar.NewMessageID(1, 1, 0, 0)}}, v0}
+	compatible := versionedMessage{readableMessage{fakeMessage{id: pulsar.NewMessageID(1, 2, 0, 0)}}, v1}
+
+	testCases := []struct {
+		action     string
+		wantID     pulsar.MessageID
+		wantAcked  int
+		wantNacked int
+	}{
+		{action: SchemaIncompatibilityActionSkip, wantID: compatible.ID(), wantAcked: 1},
+		{action: SchemaIncompatibilityActionDLQ, wantID: compatible.ID(), wantNacked: 1},
+		{action: SchemaIncompatibilityActionRaw, wantID: incompatible.ID()},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.action, func(t *testing.T) {
+			is := is.New(t)
+
+			consumer := &rejectRecordingConsumer{queueConsumer: queueConsumer{messages: []pulsar.Message{incompatible, compatible}}}
+			underTest := &Source{
+				consumer: consumer,
+				config: SourceConfig{
+					DLQMaxDeliveries:            3,
+					SchemaIncompatibilityAction: tc.action,
+				},
+				pinnedSchemaVersion: v1,
+			}
+
+			rec, err := underTest.Read(context.Background())
+			is.NoErr(err)
+			pos, err := parsePosition(rec.Position)
+			is.NoErr(err)
+			is.Equal(pos.MessageID, tc.wantID.Serialize())
+			is.Equal(len(consumer.acked), tc.wantAcked)
+			is.Equal(len(consumer.nacked), tc.wantNacked)
+
+			_, incompatible := rec.Metadata[metadataSchemaIncompatible]
+			is.Equal(incompatible, tc.action == SchemaIncompatibilityActionRaw)
+		})
+	}
+}
+
+func TestSource_Configure_SchemaIncompatibilityAction(t *testing.T) {
+	testCases := []struct {
+		name    string
+		cfg     map[string]string
+		wantErr bool
+	}{
+		{name: "unknown action", cfg: map[string]string{
+			SourceConfigJsonSchemaValidation:        testJSONSchema,
+			SourceConfigSchemaIncompatibilityAction: "fail",
+		}, wantErr: true},
+		{name: "without schema", cfg: map[string]string{
+			SourceConfigSchemaIncompatibilityAction: SchemaIncompatibilityActionSkip,
+		}, wantErr: true},
+		{name: "skip", cfg: map[string]string{
+			SourceConfigJsonSchemaValidation:        testJSONSchema,
+			SourceConfigSchemaIncompatibilityAction: SchemaIncompatibilityActionSkip,
+		}},
+		{name: "dlq without dead letter topic", cfg: map[string]string{
+			SourceConfigJsonSchemaValidation:        testJSONSchema,
+			SourceConfigSchemaIncompatibilityAction: SchemaIncompatibilityActionDLQ,
+		}, wantErr: true},
+		{name: "dlq with failure topic", cfg: map[string]string{
+			SourceConfigJsonSchemaValidation:        testJSONSchema,
+			SourceConfigSchemaIncompatibilityAction: SchemaIncompatibilityActionDLQ,
+			SourceConfigDlqFailureTopics:            "schema:schema-dlq",
+		}},
+		{name: "raw", cfg: map[string]string{
+			SourceConfigJsonSchemaValidation:        testJSONSchema,
+			SourceConfigSchemaIncompatibilityAction: SchemaIncompatibilityActionRaw,
+		}},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			is := is.New(t)
+
+			cfgMap := newSourceCfg("topic")
+			for k, v := range tc.cfg {
+				cfgMap[k] = v
+			}
+			err := (&Source{}).Configure(context.Background(), cfgMap)
+			is.Equal(err != nil, tc.wantErr)
+		})
+	}
+}
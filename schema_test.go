@@ -0,0 +1,42 @@
+// Copyright © 2024 Meroxa, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pulsar
+
+import (
+	"testing"
+
+	"github.com/matryer/is"
+)
+
+func TestSchemaVersionTracker_Observe(t *testing.T) {
+	is := is.New(t)
+
+	v0 := []byte{0, 0, 0, 0, 0, 0, 0, 0}
+	v1 := []byte{0, 0, 0, 0, 0, 0, 0, 1}
+
+	tracker := newSchemaVersionTracker()
+	is.True(!tracker.observe("topic-a", v0)) // first version is not a change
+	is.True(!tracker.observe("topic-a", v0))
+	is.True(tracker.observe("topic-a", v1))
+	is.True(!tracker.observe("topic-b", v1)) // versions are tracked per topic
+	is.True(!tracker.observe("topic-a", v1))
+}
+
+func TestFormatSchemaVersion(t *testing.T) {
+	is := is.New(t)
+
+	is.Equal(formatSchemaVersion([]byte{0, 0, 0, 0, 0, 0, 0, 3}), "3")
+	is.Equal(formatSchemaVersion([]byte{0xab, 0xcd}), "abcd")
+}
@@ -0,0 +1,81 @@
+// Copyright © 2024 Meroxa, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pulsar
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/apache/pulsar-client-go/pulsar"
+	"github.com/conduitio/conduit-commons/opencdc"
+)
+
+// Supported values of SourceConfig.SchemaType.
+const (
+	SchemaTypeNone   = "none"
+	SchemaTypeJSON   = "json"
+	SchemaTypeAvro   = "avro"
+	SchemaTypeString = "string"
+)
+
+// newConsumerSchema returns the schema the source subscribes with, or nil if
+// it subscribes without a schema. The definition is either an Avro schema or
+// the path of a file containing it.
+func newConsumerSchema(schemaType, definition string) (pulsar.Schema, error) {
+	if schemaType == SchemaTypeJSON || schemaType == SchemaTypeAvro {
+		if !strings.HasPrefix(strings.TrimSpace(definition), "{") {
+			doc, err := os.ReadFile(definition)
+			if err != nil {
+				return nil, fmt.Errorf("failed to read schema definition: %w", err)
+			}
+			definition = string(doc)
+		}
+	}
+
+	switch schemaType {
+	case SchemaTypeJSON:
+		schema, err := pulsar.NewJSONSchemaWithValidation(definition, nil)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse schema definition: %w", err)
+		}
+		return schema, nil
+	case SchemaTypeAvro:
+		schema, err := pulsar.NewAvroSchemaWithValidation(definition, nil)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse schema definition: %w", err)
+		}
+		return schema, nil
+	case SchemaTypeString:
+		return pulsar.NewStringSchema(nil), nil
+	default:
+		return nil, nil
+	}
+}
+
+// decodePayload decodes the payload of the message with the schema it was
+// produced with and returns it as structured data. Payloads of messages
+// without a JSON or Avro schema are returned as raw data.
+func (s *Source) decodePayload(msg pulsar.Message, payload []byte) (opencdc.Data, error) {
+	if s.config.SchemaType != SchemaTypeJSON && s.config.SchemaType != SchemaTypeAvro {
+		return opencdc.RawData(payload), nil
+	}
+
+	var decoded map[string]any
+	if err := msg.GetSchemaValue(&decoded); err != nil {
+		return nil, fmt.Errorf("failed to decode payload with %s schema: %w", s.config.SchemaType, err)
+	}
+	return opencdc.StructuredData(decoded), nil
+}
@@ -0,0 +1,217 @@
+// Copyright © 2024 Meroxa, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pulsar
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/apache/pulsar-client-go/pulsar"
+	"github.com/conduitio/conduit-commons/opencdc"
+	"github.com/matryer/is"
+)
+
+func TestNewConsumerSchema(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "schema.avsc")
+	err := os.WriteFile(path, []byte(testAvroSchema), 0o600)
+	is.New(t).NoErr(err)
+
+	testCases := []struct {
+		name       string
+		schemaType string
+		definition string
+		wantType   pulsar.SchemaType
+		wantNil    bool
+		wantErr    bool
+	}{
+		{name: "none", schemaType: SchemaTypeNone, wantNil: true},
+		{name: "string", schemaType: SchemaTypeString, wantType: pulsar.STRING},
+		{name: "json", schemaType: SchemaTypeJSON, definition: testAvroSchema, wantType: pulsar.JSON},
+		{name: "avro", schemaType: SchemaTypeAvro, definition: testAvroSchema, wantType: pulsar.AVRO},
+		{name: "definition file", schemaType: SchemaTypeAvro, definition: path, wantType: pulsar.AVRO},
+		{name: "missing file", schemaType: SchemaTypeAvro, definition: "does-not-exist.avsc", wantErr: true},
+		{name: "invalid definition", schemaType: SchemaTypeJSON, definition: `{"type": "record"}`, wantErr: true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			is := is.New(t)
+
+			schema, err := newConsumerSchema(tc.schemaType, tc.definition)
+			switch {
+			case tc.wantErr:
+				is.True(err != nil)
+			case tc.wantNil:
+				is.NoErr(err)
+				is.Equal(schema, nil)
+			default:
+				is.NoErr(err)
+				is.Equal(schema.GetSchemaInfo().Type, tc.wantType)
+			}
+		})
+	}
+}
+
+// schemaMessage is a payloadMessage decoded with a schema.
+type schemaMessage struct {
+	payloadMessage
+	schema pulsar.Schema
+}
+
+func (m schemaMessage) GetSchemaValue(v any) error {
+	return m.schema.Decode(m.payload, v)
+}
+
+func TestSource_Read_SchemaType(t *testing.T) {
+	order := map[string]any{"id": 1, "status": "NEW", "note": nil, "tags": []any{}}
+
+	testCases := []struct {
+		schemaType string
+	}{
+		{schemaType: SchemaTypeJSON},
+		{schemaType: SchemaTypeAvro},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.schemaType, func(t *testing.T) {
+			is := is.New(t)
+
+			schema, err := newConsumerSchema(tc.schemaType, testAvroSchema)
+			is.NoErr(err)
+			payload, err := schema.Encode(order)
+			is.NoErr(err)
+
+			msg := schemaMessage{
+				payloadMessage: payloadMessage{readableMessage{fakeMessage{id: pulsar.NewMessageID(1, 1, 0, 0)}}, payload},
+				schema:         schema,
+			}
+			underTest := &Source{
+				consumer: &queueConsumer{messages: []pulsar.Message{msg}},
+				config:   SourceConfig{SchemaType: tc.schemaType},
+				schema:   schema,
+			}
+
+			rec, err := underTest.Read(context.Background())
+			is.NoErr(err)
+
+			structured, ok := rec.Payload.After.(opencdc.StructuredData)
+			is.True(ok)
+			is.Equal(structured["status"], "NEW")
+		})
+	}
+}
+
+func TestSource_Read_UndecodablePayload(t *testing.T) {
+	testCases := []struct {
+		name             string
+		dlqMaxDeliveries int
+		wantAcked        int
+		wantNacked       int
+	}{
+		{name: "dropped", wantAcked: 1},
+		{name: "redelivered until routed to the dead letter topic", dlqMaxDeliveries: 3, wantNacked: 1},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			is := is.New(t)
+
+			schema, err := newConsumerSchema(SchemaTypeAvro, testAvroSchema)
+			is.NoErr(err)
+			payload, err := schema.Encode(map[string]any{"id": 1, "status": "NEW", "note": nil, "tags": []any{}})
+			is.NoErr(err)
+
+			undecodable := schemaMessage{
+				payloadMessage: payloadMessage{readableMessage{fakeMessage{id: pulsar.NewMessageID(1, 1, 0, 0)}}, []byte("truncated")},
+				schema:         schema,
+			}
+			valid := schemaMessage{
+				payloadMessage: payloadMessage{readableMessage{fakeMessage{id: pulsar.NewMessageID(1, 2, 0, 0)}}, payload},
+				schema:         schema,
+			}
+			consumer := &rejectRecordingConsumer{queueConsumer: queueConsumer{messages: []pulsar.Message{undecodable, valid}}}
+			underTest := &Source{
+				consumer: consumer,
+				config:   SourceConfig{SchemaType: SchemaTypeAvro, DLQMaxDeliveries: tc.dlqMaxDeliveries},
+				schema:   schema,
+				inFlight: newInFlightTracker(),
+			}
+
+			rec, err := underTest.Read(context.Background())
+			is.NoErr(err)
+
+			// the undecodable message is rejected and skipped, only the valid
+			// message is tracked
+			pos, err := parsePosition(rec.Position)
+			is.NoErr(err)
+			is.Equal(pos.MessageID, valid.ID().Serialize())
+			is.Equal(underTest.inFlight.len(), 1)
+			is.Equal(len(consumer.acked), tc.wantAcked)
+			is.Equal(len(consumer.nacked), tc.wantNacked)
+		})
+	}
+}
+
+func TestSource_Read_SchemaTypeString(t *testing.T) {
+	is := is.New(t)
+
+	msg := payloadMessage{readableMessage{fakeMessage{id: pulsar.NewMessageID(1, 1, 0, 0)}}, []byte("hello")}
+	underTest := &Source{
+		consumer: &queueConsumer{messages: []pulsar.Message{msg}},
+		config:   SourceConfig{SchemaType: SchemaTypeString},
+		schema:   pulsar.NewStringSchema(nil),
+	}
+
+	rec, err := underTest.Read(context.Background())
+	is.NoErr(err)
+	is.Equal(rec.Payload.After, opencdc.RawData("hello"))
+}
+
+func TestSource_Configure_SchemaType(t *testing.T) {
+	testCases := []struct {
+		name    string
+		cfg     map[string]string
+		wantErr bool
+	}{
+		{name: "none", cfg: map[string]string{}},
+		{name: "string", cfg: map[string]string{SourceConfigSchemaType: SchemaTypeString}},
+		{name: "avro", cfg: map[string]string{SourceConfigSchemaType: SchemaTypeAvro, SourceConfigSchemaDefinition: testAvroSchema}},
+		{name: "unknown type", cfg: map[string]string{SourceConfigSchemaType: "protobuf"}, wantErr: true},
+		{name: "missing definition", cfg: map[string]string{SourceConfigSchemaType: SchemaTypeJSON}, wantErr: true},
+		{name: "definition without schema type", cfg: map[string]string{SourceConfigSchemaDefinition: testAvroSchema}, wantErr: true},
+		{name: "invalid definition", cfg: map[string]string{SourceConfigSchemaType: SchemaTypeAvro, SourceConfigSchemaDefinition: `{"type": "record"}`}, wantErr: true},
+		{name: "inferred payload type", cfg: map[string]string{
+			SourceConfigSchemaType:       SchemaTypeJSON,
+			SourceConfigSchemaDefinition: testAvroSchema,
+			SourceConfigInferPayloadType: "true",
+		}, wantErr: true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			is := is.New(t)
+
+			cfgMap := newSourceCfg("topic")
+			for key, val := range tc.cfg {
+				cfgMap[key] = val
+			}
+
+			err := (&Source{}).Configure(context.Background(), cfgMap)
+			is.Equal(err != nil, tc.wantErr)
+		})
+	}
+}
@@ -0,0 +1,162 @@
+// Copyright © 2024 Meroxa, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pulsar
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"slices"
+
+	"github.com/hamba/avro/v2"
+)
+
+// errSchemaMismatch is returned when a record doesn't match the schema of the
+// topic it is produced to.
+var errSchemaMismatch = errors.New("record doesn't match the schema of the topic")
+
+// Pulsar schema types whose definition is an Avro record schema.
+const (
+	pulsarSchemaTypeAvro = "AVRO"
+	pulsarSchemaTypeJSON = "JSON"
+)
+
+// recordValidator checks that JSON payloads can be read with the Avro schema
+// of a topic, which is the definition format of both Avro and JSON schemas in
+// Pulsar.
+type recordValidator struct {
+	schema avro.Schema
+}
+
+func newRecordValidator(definition []byte) (*recordValidator, error) {
+	// a separate cache keeps named types of different topics apart
+	schema, err := avro.ParseWithCache(string(definition), "", &avro.SchemaCache{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse schema: %w", err)
+	}
+	return &recordValidator{schema: schema}, nil
+}
+
+// validate returns an error wrapping errSchemaMismatch if the payload is not a
+// JSON document matching the schema.
+func (v *recordValidator) validate(payload []byte) error {
+	dec := json.NewDecoder(bytes.NewReader(payload))
+	dec.UseNumber()
+
+	var doc any
+	if err := dec.Decode(&doc); err != nil {
+		return fmt.Errorf("%w: payload is not valid JSON: %w", errSchemaMismatch, err)
+	}
+	if err := validateAvroValue(v.schema, doc, "$"); err != nil {
+		return fmt.Errorf("%w: %w", errSchemaMismatch, err)
+	}
+	return nil
+}
+
+// validateAvroValue checks that the decoded JSON value matches the schema.
+// Fields that are not part of a record schema are ignored.
+func validateAvroValue(schema avro.Schema, val any, path string) error {
+	switch s := schema.(type) {
+	case *avro.RefSchema:
+		return validateAvroValue(s.Schema(), val, path)
+	case *avro.RecordSchema:
+		obj, ok := val.(map[string]any)
+		if !ok {
+			return fmt.Errorf("%s must be an object", path)
+		}
+		for _, f := range s.Fields() {
+			fieldVal, ok := obj[f.Name()]
+			if !ok {
+				if f.HasDefault() || isNullable(f.Type()) {
+					continue
+				}
+				return fmt.Errorf("%s.%s is required", path, f.Name())
+			}
+			if err := validateAvroValue(f.Type(), fieldVal, path+"."+f.Name()); err != nil {
+				return err
+			}
+		}
+		return nil
+	case *avro.UnionSchema:
+		for _, t := range s.Types() {
+			if validateAvroValue(t, val, path) == nil {
+				return nil
+			}
+		}
+		return fmt.Errorf("%s doesn't match any type of the union", path)
+	case *avro.ArraySchema:
+		items, ok := val.([]any)
+		if !ok {
+			return fmt.Errorf("%s must be an array", path)
+		}
+		for i, item := range items {
+			if err := validateAvroValue(s.Items(), item, fmt.Sprintf("%s[%d]", path, i)); err != nil {
+				return err
+			}
+		}
+		return nil
+	case *avro.MapSchema:
+		obj, ok := val.(map[string]any)
+		if !ok {
+			return fmt.Errorf("%s must be an object", path)
+		}
+		for key, item := range obj {
+			if err := validateAvroValue(s.Values(), item, path+"."+key); err != nil {
+				return err
+			}
+		}
+		return nil
+	case *avro.EnumSchema:
+		str, ok := val.(string)
+		if !ok || !slices.Contains(s.Symbols(), str) {
+			return fmt.Errorf("%s must be one of %v", path, s.Symbols())
+		}
+		return nil
+	}
+
+	var ok bool
+	switch schema.Type() {
+	case avro.Null:
+		ok = val == nil
+	case avro.Boolean:
+		_, ok = val.(bool)
+	case avro.String, avro.Bytes, avro.Fixed:
+		_, ok = val.(string)
+	case avro.Int:
+		n, isNumber := val.(json.Number)
+		i, err := n.Int64()
+		ok = isNumber && err == nil && i >= math.MinInt32 && i <= math.MaxInt32
+	case avro.Long:
+		n, isNumber := val.(json.Number)
+		_, err := n.Int64()
+		ok = isNumber && err == nil
+	case avro.Float, avro.Double:
+		_, ok = val.(json.Number)
+	default:
+		ok = true
+	}
+	if !ok {
+		return fmt.Errorf("%s must be of type %s", path, schema.Type())
+	}
+	return nil
+}
+
+// isNullable returns true if the schema is a union containing null.
+func isNullable(schema avro.Schema) bool {
+	union, ok := schema.(*avro.UnionSchema)
+	return ok && union.Nullable()
+}
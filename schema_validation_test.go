@@ -0,0 +1,131 @@
+// Copyright © 2024 Meroxa, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pulsar
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/conduitio-labs/conduit-connector-pulsar/test"
+	"github.com/conduitio/conduit-commons/opencdc"
+	sdk "github.com/conduitio/conduit-connector-sdk"
+	"github.com/matryer/is"
+)
+
+const testAvroSchema = `{
+	"type": "record",
+	"name": "Order",
+	"fields": [
+		{"name": "id", "type": "int"},
+		{"name": "status", "type": {"type": "enum", "name": "Status", "symbols": ["NEW", "SHIPPED"]}},
+		{"name": "note", "type": ["null", "string"]},
+		{"name": "tags", "type": {"type": "array", "items": "string"}, "default": []}
+	]
+}`
+
+func TestRecordValidator(t *testing.T) {
+	testCases := []struct {
+		name    string
+		payload string
+		wantErr bool
+	}{
+		{name: "valid", payload: `{"id": 1, "status": "NEW", "note": "fragile", "tags": ["a"]}`},
+		{name: "optional fields missing", payload: `{"id": 1, "status": "SHIPPED"}`},
+		{name: "unknown fields are ignored", payload: `{"id": 1, "status": "NEW", "extra": true}`},
+		{name: "null in nullable field", payload: `{"id": 1, "status": "NEW", "note": null}`},
+		{name: "not JSON", payload: `id=1`, wantErr: true},
+		{name: "not an object", payload: `[1]`, wantErr: true},
+		{name: "required field missing", payload: `{"status": "NEW"}`, wantErr: true},
+		{name: "wrong type", payload: `{"id": "one", "status": "NEW"}`, wantErr: true},
+		{name: "fraction in int field", payload: `{"id": 1.5, "status": "NEW"}`, wantErr: true},
+		{name: "int out of range", payload: `{"id": 3000000000, "status": "NEW"}`, wantErr: true},
+		{name: "unknown enum symbol", payload: `{"id": 1, "status": "LOST"}`, wantErr: true},
+		{name: "wrong array item", payload: `{"id": 1, "status": "NEW", "tags": [1]}`, wantErr: true},
+	}
+
+	validator, err := newRecordValidator([]byte(testAvroSchema))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			is := is.New(t)
+
+			err := validator.validate([]byte(tc.payload))
+			is.Equal(err != nil, tc.wantErr)
+			if tc.wantErr {
+				is.True(errors.Is(err, errSchemaMismatch))
+			}
+		})
+	}
+}
+
+func TestDestination_Write_ValidateBeforeSend(t *testing.T) {
+	is := is.New(t)
+
+	// messages contain the whole OpenCDC record
+	validator, err := newRecordValidator([]byte(`{
+		"type": "record",
+		"name": "Record",
+		"fields": [
+			{"name": "operation", "type": "string"},
+			{"name": "payload", "type": {
+				"type": "record",
+				"name": "Change",
+				"fields": [{"name": "after", "type": ` + testAvroSchema + `}]
+			}}
+		]
+	}`))
+	is.NoErr(err)
+
+	producer := &recordingProducer{}
+	con := &Destination{
+		producer: producer,
+		config: DestinationConfig{
+			Config:             Config{Topic: "test-topic"},
+			ValidateBeforeSend: true,
+		},
+		schemas: map[string]*recordValidator{"test-topic": validator},
+	}
+
+	records := []opencdc.Record{
+		sdk.Util.Source.NewRecordCreate(nil, nil, opencdc.RawData("1"),
+			opencdc.StructuredData{"id": 1, "status": "NEW"}),
+		sdk.Util.Source.NewRecordCreate(nil, nil, opencdc.RawData("2"),
+			opencdc.StructuredData{"id": 2, "status": "LOST"}),
+	}
+
+	written, err := con.Write(context.Background(), records)
+	is.True(errors.Is(err, errSchemaMismatch))
+	is.Equal(written, 1)
+
+	// the invalid record was rejected before it was sent
+	is.Equal(len(producer.sent), 1)
+	is.Equal(producer.sent[0].Key, "1")
+}
+
+func TestDestination_Configure_ValidateBeforeSendRequiresAdminURL(t *testing.T) {
+	is := is.New(t)
+	con := NewDestination()
+
+	err := con.Configure(context.Background(), map[string]string{
+		DestinationConfigUrl:                test.PulsarURL,
+		DestinationConfigTopic:              "test-topic",
+		DestinationConfigValidateBeforeSend: "true",
+	})
+	is.True(err != nil)
+}
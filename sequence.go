@@ -0,0 +1,100 @@
+// Copyright © 2024 Meroxa, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pulsar
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// SequenceStore persists the sequence ID of the last message confirmed by the
+// broker, so a named producer continues its sequence after a restart. Together
+// with broker-side deduplication this makes sure records that are written
+// again after a restart are not produced twice. Sequence IDs are stored per
+// producer name.
+type SequenceStore interface {
+	// Save stores the sequence ID of the last confirmed message.
+	Save(ctx context.Context, producerName string, sequenceID int64) error
+	// Load returns the stored sequence ID, or -1 if no sequence ID was stored.
+	Load(ctx context.Context, producerName string) (int64, error)
+}
+
+// fileSequenceStore is the default sequence store, it keeps the sequence ID of
+// each producer in a file in dir.
+type fileSequenceStore struct {
+	dir string
+}
+
+func newFileSequenceStore(dir string) *fileSequenceStore {
+	return &fileSequenceStore{dir: dir}
+}
+
+func (s *fileSequenceStore) Save(_ context.Context, producerName string, sequenceID int64) error {
+	if err := os.MkdirAll(s.dir, 0o755); err != nil {
+		return fmt.Errorf("failed to create sequence store directory: %w", err)
+	}
+
+	// write to a temporary file first, so a crash never leaves a partially
+	// written sequence ID behind
+	f, err := os.CreateTemp(s.dir, ".sequence-*")
+	if err != nil {
+		return fmt.Errorf("failed to create sequence file: %w", err)
+	}
+	defer os.Remove(f.Name())
+
+	_, err = f.WriteString(strconv.FormatInt(sequenceID, 10))
+	if err == nil {
+		err = f.Sync()
+	}
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return fmt.Errorf("failed to write sequence file: %w", err)
+	}
+
+	if err := os.Rename(f.Name(), s.path(producerName)); err != nil {
+		return fmt.Errorf("failed to replace sequence file: %w", err)
+	}
+	return nil
+}
+
+func (s *fileSequenceStore) Load(_ context.Context, producerName string) (int64, error) {
+	data, err := os.ReadFile(s.path(producerName))
+	if errors.Is(err, os.ErrNotExist) {
+		return -1, nil
+	}
+	if err != nil {
+		return 0, fmt.Errorf("failed to read sequence file: %w", err)
+	}
+
+	sequenceID, err := strconv.ParseInt(strings.TrimSpace(string(data)), 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid sequence file %q: %w", s.path(producerName), err)
+	}
+	return sequenceID, nil
+}
+
+// path returns the path of the file containing the sequence ID of the
+// producer. The producer name is escaped, since it may contain slashes.
+func (s *fileSequenceStore) path(producerName string) string {
+	return filepath.Join(s.dir, url.PathEscape(producerName))
+}
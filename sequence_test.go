@@ -0,0 +1,80 @@
+// Copyright © 2024 Meroxa, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pulsar
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/matryer/is"
+)
+
+func TestFileSequenceStore(t *testing.T) {
+	is := is.New(t)
+	ctx := context.Background()
+
+	store := newFileSequenceStore(filepath.Join(t.TempDir(), "sequences"))
+
+	got, err := store.Load(ctx, "producer")
+	is.NoErr(err)
+	is.Equal(got, int64(-1))
+
+	err = store.Save(ctx, "producer", 41)
+	is.NoErr(err)
+	err = store.Save(ctx, "producer", 42)
+	is.NoErr(err)
+
+	got, err = store.Load(ctx, "producer")
+	is.NoErr(err)
+	is.Equal(got, int64(42))
+
+	// sequence IDs are stored per producer
+	got, err = store.Load(ctx, "other-producer")
+	is.NoErr(err)
+	is.Equal(got, int64(-1))
+}
+
+func TestFileSequenceStore_EscapesProducerName(t *testing.T) {
+	is := is.New(t)
+	ctx := context.Background()
+
+	dir := t.TempDir()
+	store := newFileSequenceStore(dir)
+
+	err := store.Save(ctx, "tenant/producer", 7)
+	is.NoErr(err)
+
+	entries, err := os.ReadDir(dir)
+	is.NoErr(err)
+	is.Equal(len(entries), 1)
+	is.Equal(entries[0].Name(), "tenant%2Fproducer")
+
+	got, err := store.Load(ctx, "tenant/producer")
+	is.NoErr(err)
+	is.Equal(got, int64(7))
+}
+
+func TestFileSequenceStore_InvalidFile(t *testing.T) {
+	is := is.New(t)
+
+	dir := t.TempDir()
+	err := os.WriteFile(filepath.Join(dir, "producer"), []byte("not a number"), 0o600)
+	is.NoErr(err)
+
+	_, err = newFileSequenceStore(dir).Load(context.Background(), "producer")
+	is.True(err != nil)
+}
@@ -15,9 +15,16 @@
 package pulsar
 
 import (
+	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
 
 	"github.com/apache/pulsar-client-go/pulsar"
 	"github.com/apache/pulsar-client-go/pulsar/log"
@@ -25,18 +32,123 @@ import (
 	"github.com/conduitio/conduit-commons/opencdc"
 	sdk "github.com/conduitio/conduit-connector-sdk"
 	"github.com/google/uuid"
+	"github.com/prometheus/client_golang/prometheus"
 )
 
+// Metadata keys set on every record read from a consumer or reader.
+const (
+	metadataTopic           = "pulsar.topic"
+	metadataPublishTime     = "pulsar.publishTime"
+	metadataMessageID       = "pulsar.messageID"
+	metadataRedeliveryCount = "pulsar.redeliveryCount"
+	// metadataOrderingKey is only set if the message has an ordering key. The
+	// destination produces messages with the ordering key.
+	metadataOrderingKey = "pulsar.orderingKey"
+	// metadataContentType is only set if InferPayloadType is enabled.
+	metadataContentType = "pulsar.contentType"
+	// metadataContentEncoding is only set if a compressed payload was
+	// decompressed.
+	metadataContentEncoding = "pulsar.contentEncoding"
+)
+
+// metadataPropertiesPrefix is the prefix of the metadata keys the properties
+// of a message are stored under, so they can't clobber other metadata.
+const metadataPropertiesPrefix = "pulsar.properties."
+
 type Source struct {
 	sdk.UnimplementedSource
 
 	client   pulsar.Client
 	consumer pulsar.Consumer
 	config   SourceConfig
+
+	// consumerMu guards the consumer while it is replaced after partitions
+	// were removed. Read replaces it, so only acks and nacks from other
+	// goroutines lock it for reading.
+	consumerMu sync.RWMutex
+
+	// consumerOpts are kept to subscribe again when partitions are removed.
+	consumerOpts pulsar.ConsumerOptions
+
+	// reader replaces the consumer when ReaderStartMessageID is set.
+	reader      pulsar.Reader
+	readerCount int
+
+	// inFlight is set when in-flight messages are nacked on shutdown.
+	inFlight *inFlightTracker
+	// schemaVersions is set when schema changes are reported.
+	schemaVersions *schemaVersionTracker
+	// ordering is set when messages are emitted in publish time order.
+	ordering *orderingBuffer
+	// eventTimes filters messages by their event time.
+	eventTimes eventTimeRange
+	// partitions is set when the partition count of the topic is checked.
+	partitions *partitionTracker
+	// ackLatency is set when the time between read and ack is measured.
+	ackLatency *ackLatencyRecorder
+	// pinnedSchemaVersion is set when messages with other schema versions
+	// are rejected.
+	pinnedSchemaVersion []byte
+	// deadlines is set when records are nacked if they are not acked in time.
+	deadlines *processingDeadlines
+	// stopDeadlines stops nacking records with expired deadlines.
+	stopDeadlines func()
+	// payloads is set when payloads are validated against a JSON schema.
+	payloads *payloadValidator
+	// schema is the schema the source subscribes with, nil if it subscribes
+	// without a schema.
+	schema pulsar.Schema
+	// failureProducers route messages to the dead letter topic of their
+	// failure type.
+	failureProducers map[string]pulsar.Producer
+	// prefetch is set when the number of unacknowledged records adapts to
+	// the ack latency.
+	prefetch *adaptivePrefetch
+	// acks is set when acknowledgements are batched by the source.
+	acks *ackBatch
+	// stopAckFlush stops sending batched acknowledgements periodically.
+	stopAckFlush func()
+
+	// retries is set when failed records are retried from the retry letter
+	// topic.
+	retries *retryTracker
+	// watermarks is set when watermark records are emitted.
+	watermarks *watermarkTracker
+	// topicPositions is set when multiple topics are consumed, so the
+	// position of a record tracks each topic.
+	topicPositions *topicPositions
+
+	// batch buffers messages received from the consumer when ReadBatchSize
+	// is greater than 1 or the receive queue is scaled.
+	batch []pulsar.Message
+	// receiverQueue is set when the receive queue scales with the
+	// consumption rate, it limits the size of batch.
+	receiverQueue *adaptiveReceiverQueue
+
+	// messages is fed by the consumer when MessageListenerMode is enabled.
+	messages chan pulsar.ConsumerMessage
+	// dropUndeliverable is set when the dead letter topic is unavailable and
+	// messages that exceeded the max deliveries should be dropped.
+	dropUndeliverable bool
+
+	positionStore PositionStore
+
+	// metrics is the registry of the client and source metrics, the default
+	// Prometheus registry is used if it is nil.
+	metrics *prometheus.Registry
+	// metricsServer serves the metrics when MetricsAddress is set.
+	metricsServer *http.Server
 }
 
 func NewSource() sdk.Source {
-	return sdk.SourceWithMiddleware(&Source{}, sdk.DefaultSourceMiddleware()...)
+	return NewSourceWithPositionStore(noopPositionStore{})
+}
+
+// NewSourceWithPositionStore creates a source that additionally persists the
+// positions of acknowledged records in store. The stored position is used
+// when Conduit opens the source without a position.
+func NewSourceWithPositionStore(store PositionStore) sdk.Source {
+	return sdk.SourceWithMiddleware(&Source{positionStore: store}, sdk.DefaultSourceMiddleware()...)
 }
 
 func (s *Source) Parameters() config.Parameters {
@@ -48,21 +160,58 @@ func (s *Source) Configure(ctx context.Context, cfg config.Config) error {
 		return fmt.Errorf("failed to parse config: %w", err)
 	}
 
-	sdk.Logger(ctx).Info().Str("topic", s.config.Topic).Msg("configured source")
+	if err := s.config.Validate(); err != nil {
+		return fmt.Errorf("invalid config: %w", err)
+	}
+
+	// the range and schema version were already validated, parsing can't fail
+	s.eventTimes, _ = parseEventTimeRange(s.config.EventTimeFrom, s.config.EventTimeTo)
+	if s.config.PinnedSchemaVersion != "" {
+		s.pinnedSchemaVersion, _ = parseSchemaVersion(s.config.PinnedSchemaVersion)
+	}
+	if s.config.JSONSchemaValidation != "" {
+		var err error
+		s.payloads, err = newPayloadValidator(s.config.JSONSchemaValidation)
+		if err != nil {
+			return fmt.Errorf("invalid config: %w", err)
+		}
+	}
+	schema, err := newConsumerSchema(s.config.SchemaType, s.config.SchemaDefinition)
+	if err != nil {
+		return fmt.Errorf("invalid config: invalid %q: %w", SourceConfigSchemaDefinition, err)
+	}
+	s.schema = schema
+
+	sdk.Logger(ctx).Info().
+		Strs("topics", s.config.topics()).
+		Str("topicsPattern", s.config.TopicsPattern).
+		Msg("configured source")
 
 	return nil
 }
 
 func (s *Source) Open(ctx context.Context, pos opencdc.Position) (err error) {
+	pos, err = s.resolvePosition(ctx, pos)
+	if err != nil {
+		return err
+	}
+
 	var logger log.Logger
 	if s.config.DisableLogging {
 		logger = log.DefaultNopLogger()
 	}
 
+	authentication, err := newAuthentication(s.config.Config)
+	if err != nil {
+		return err
+	}
+
 	s.client, err = pulsar.NewClient(pulsar.ClientOptions{
 		URL:                        s.config.URL,
+		ListenerName:               s.config.ListenerName,
 		ConnectionTimeout:          s.config.ConnectionTimeout,
 		OperationTimeout:           s.config.OperationTimeout,
+		KeepAliveInterval:          s.config.KeepAliveInterval,
 		MaxConnectionsPerBroker:    s.config.MaxConnectionsPerBroker,
 		MemoryLimitBytes:           s.config.MemoryLimitBytes,
 		EnableTransaction:          s.config.EnableTransaction,
@@ -71,6 +220,9 @@ func (s *Source) Open(ctx context.Context, pos opencdc.Position) (err error) {
 		TLSTrustCertsFilePath:      s.config.TLSTrustCertsFilePath,
 		TLSAllowInsecureConnection: s.config.TLSAllowInsecureConnection,
 		TLSValidateHostname:        s.config.TLSValidateHostname,
+		Authentication:             authentication,
+		MetricsCardinality:         toMetricsCardinality(s.config.MetricsCardinality),
+		MetricsRegisterer:          s.metricsRegisterer(),
 
 		Logger: logger,
 	})
@@ -78,19 +230,205 @@ func (s *Source) Open(ctx context.Context, pos opencdc.Position) (err error) {
 		return fmt.Errorf("failed to create client: %w", err)
 	}
 	sdk.Logger(ctx).Debug().Msg("Created Pulsar client")
+	defer func() {
+		if err != nil {
+			s.client.Close()
+		}
+	}()
 
-	s.consumer, err = s.client.Subscribe(pulsar.ConsumerOptions{
-		Topic:                       s.config.Topic,
+	if s.config.MetricsAddress != "" {
+		s.metricsServer, err = serveMetrics(ctx, s.config.MetricsAddress, s.metricsGatherer())
+		if err != nil {
+			return err
+		}
+	}
+
+	if s.config.LookupTimeout > 0 {
+		for _, topic := range s.config.topics() {
+			if err := lookupTopic(s.client, topic, s.config.LookupTimeout); err != nil {
+				return err
+			}
+		}
+	}
+
+	if s.config.ResetSubscription != "" {
+		admin, err := newAdminClient(s.config.Config)
+		if err != nil {
+			return err
+		}
+		for _, topic := range s.config.topics() {
+			if err := resetSubscription(admin, topic, s.config.SubscriptionName, s.config.ResetSubscription); err != nil {
+				return err
+			}
+		}
+		sdk.Logger(ctx).Info().
+			Str("subscriptionName", s.config.SubscriptionName).
+			Str("position", s.config.ResetSubscription).
+			Msg("reset subscription")
+	}
+
+	if s.pinnedSchemaVersion != nil {
+		admin, err := newAdminClient(s.config.Config)
+		if err != nil {
+			return err
+		}
+		version, _ := strconv.ParseInt(s.config.PinnedSchemaVersion, 10, 64)
+		for _, topic := range s.config.topics() {
+			if err := checkSchemaVersion(admin, topic, version); err != nil {
+				return err
+			}
+		}
+	}
+
+	if s.config.NotifySchemaChange {
+		s.schemaVersions = newSchemaVersionTracker()
+	}
+	if s.config.GlobalOrderingWindow > 0 {
+		s.ordering = newOrderingBuffer(s.config.GlobalOrderingWindow)
+	}
+	if s.config.AckLatencyMetrics {
+		topics := s.config.TopicsPattern
+		if topics == "" {
+			topics = strings.Join(s.config.topics(), ",")
+		}
+		s.ackLatency, err = newAckLatencyRecorder(s.metricsRegisterer(), topics)
+		if err != nil {
+			return err
+		}
+	}
+
+	if s.config.ReaderStartMessageID != "" {
+		if err := s.openReader(ctx, pos); err != nil {
+			return err
+		}
+		return nil
+	}
+
+	var interceptors pulsar.ConsumerInterceptors
+	if s.config.MeasureLag {
+		interceptors = append(interceptors, newLagInterceptor(ctx))
+	}
+
+	dlqPolicy := newDLQPolicy(s.config)
+	if dlqPolicy != nil && s.config.DLQFailurePolicy != DLQFailurePolicyBlock {
+		if err := checkDLQTopic(s.client, dlqPolicy.DeadLetterTopic); err != nil {
+			if s.config.DLQFailurePolicy == DLQFailurePolicyFail {
+				return fmt.Errorf("dead letter topic is unavailable: %w", err)
+			}
+			sdk.Logger(ctx).Warn().Err(err).Msg("dead letter topic is unavailable, undeliverable messages will be dropped")
+			dlqPolicy = nil
+			s.dropUndeliverable = true
+		}
+	}
+
+	if dlqPolicy != nil && s.config.DLQSchemaDefinition != "" {
+		envelope, _ := newDLQEnvelope(s.config.DLQSchemaDefinition)
+		if err := registerDLQSchema(s.client, dlqPolicy.DeadLetterTopic, envelope); err != nil {
+			return err
+		}
+	}
+
+	if len(s.config.DLQFailureTopics) > 0 {
+		failureTopics, _ := parseDLQFailureTopics(s.config.DLQFailureTopics)
+		s.failureProducers, err = openFailureProducers(s.client, failureTopics, s.config.MaxBackoff)
+		if err != nil {
+			return err
+		}
+	}
+
+	consumerOpts := pulsar.ConsumerOptions{
 		SubscriptionName:            s.config.SubscriptionName,
-		Type:                        pulsar.Exclusive,
-		SubscriptionInitialPosition: pulsar.SubscriptionPositionEarliest,
-	})
-	if err != nil {
-		s.client.Close()
-		return fmt.Errorf("failed to create consumer: %w", err)
+		Name:                        s.config.ConsumerName,
+		ReadCompacted:               s.config.ReadCompacted,
+		Type:                        toSubscriptionType(s.config.SubscriptionType),
+		SubscriptionInitialPosition: toSubscriptionInitialPosition(s.config.SubscriptionInitialPosition),
+		Interceptors:                interceptors,
+		DLQ:                         dlqPolicy,
+		NackRedeliveryDelay:         s.config.NackRedeliveryDelay,
+		ReceiverQueueSize:           s.config.ReceiverQueueSize,
+		Schema:                      s.schema,
+		BackOffPolicyFunc:           newReconnectBackoff(s.config.MaxBackoff),
+		MaxPendingChunkedMessage:    s.config.MaxPendingChunkedMessage,
+		ExpireTimeOfIncompleteChunk: s.config.ExpireTimeOfIncompleteChunk,
+
+		EnableBatchIndexAcknowledgment: s.config.EnableBatchIndexAck,
+		// AckID and AckIDCumulative of the client wait for the broker response
+		// instead of going through the ack grouping tracker
+		AckWithResponse: s.config.FlushAcksOnCommit,
+	}
+	switch topics := s.config.topics(); {
+	case s.config.TopicsPattern != "":
+		consumerOpts.TopicsPattern = s.config.TopicsPattern
+		consumerOpts.AutoDiscoveryPeriod = s.config.AutoDiscoveryPeriod
+	case len(topics) > 1:
+		consumerOpts.Topics = topics
+	default:
+		consumerOpts.Topic = topics[0]
+	}
+	if s.config.ackBatchingEnabled() {
+		// batched acknowledgements are sent right away by the client
+		consumerOpts.AckGroupingOptions = &pulsar.AckGroupingOptions{MaxSize: 1}
+	}
+	if s.config.MaxTotalReceiverQueueSizeAcrossPartitions > 0 {
+		consumerOpts.ReceiverQueueSize, err = receiverQueueSizePerPartition(s.client, s.config)
+		if err != nil {
+			return err
+		}
+	}
+	if s.config.AutoScaleReceiverQueue {
+		s.receiverQueue = newAdaptiveReceiverQueue(s.config.AutoScaleReceiverQueueMinSize, s.config.AutoScaleReceiverQueueMaxSize)
+		consumerOpts.EnableAutoScaledReceiverQueueSize = true
+		consumerOpts.ReceiverQueueSize = s.config.AutoScaleReceiverQueueMaxSize
+	}
+	if s.config.EnableRetry {
+		s.retries = newRetryTracker()
+		consumerOpts.RetryEnable = true
+	}
+	if s.config.EmitWatermarks {
+		s.watermarks = newWatermarkTracker(s.config.WatermarkInterval)
+	}
+	if s.config.AdaptivePrefetch {
+		s.prefetch = newAdaptivePrefetch(s.config.AdaptivePrefetchMin, s.config.AdaptivePrefetchMax, s.config.AdaptivePrefetchTargetLatency)
+		consumerOpts.ReceiverQueueSize = s.config.AdaptivePrefetchMax
+	}
+	if s.config.PreserveEncryptionContext {
+		consumerOpts.Decryption = passThroughDecryption
+	}
+	if s.config.NackInFlightOnShutdown || s.config.TeardownTimeout > 0 {
+		s.inFlight = newInFlightTracker()
+	}
+	if s.config.MessageListenerMode {
+		s.messages = make(chan pulsar.ConsumerMessage)
+		consumerOpts.MessageChannel = s.messages
+	}
+
+	s.consumerOpts = consumerOpts
+	if err := s.subscribe(ctx); err != nil {
+		return err
 	}
 	sdk.Logger(ctx).Debug().Msg("created pulsar consumer")
 
+	if err := s.seekToStartTimestamp(ctx, pos); err != nil {
+		return err
+	}
+
+	if deadline, checkInterval := s.config.processingDeadline(); deadline > 0 {
+		s.deadlines = newProcessingDeadlines(deadline)
+		s.stopDeadlines = s.enforceDeadlines(ctx, checkInterval)
+	}
+	if s.config.ackBatchingEnabled() {
+		s.acks = newAckBatch(s.config.AckFlushCount, s.config.AckFlushBytes, s.config.AckFlushInterval)
+		s.stopAckFlush = s.flushAcksEvery(ctx)
+	}
+
+	if s.config.PartitionCheckInterval > 0 {
+		s.partitions = newPartitionTracker(s.config.PartitionCheckInterval)
+		if err := s.partitions.init(s.client, s.config.topics()[0]); err != nil {
+			return err
+		}
+	}
+
+	var resumeTopics map[string][]byte
 	if pos != nil {
 		p, err := parsePosition(pos)
 		if err != nil {
@@ -102,9 +440,15 @@ func (s *Source) Open(ctx context.Context, pos opencdc.Position) (err error) {
 		}
 
 		s.config.SubscriptionName = p.SubscriptionName
+		resumeTopics = p.Topics
 
 		sdk.Logger(ctx).Info().Str("subscriptionName", s.config.SubscriptionName).Msg("resuming from position")
 	}
+	if s.config.TopicsPattern != "" || len(s.config.topics()) > 1 {
+		if s.topicPositions, err = newTopicPositions(resumeTopics); err != nil {
+			return err
+		}
+	}
 
 	if s.config.SubscriptionName == "" {
 		// this must be the first run of the connector, create a new group ID
@@ -115,8 +459,86 @@ func (s *Source) Open(ctx context.Context, pos opencdc.Position) (err error) {
 	return nil
 }
 
+// subscribe creates the consumer.
+func (s *Source) subscribe(ctx context.Context) error {
+	err := retryWithBackoff(ctx, s.config.SchemaRegistryMaxRetries, s.config.SchemaRegistryRetryBackoff, func() (err error) {
+		s.consumer, err = callWithTimeout(func() (pulsar.Consumer, error) {
+			return s.client.Subscribe(s.consumerOpts)
+		}, s.config.SubscribeTimeout, func(c pulsar.Consumer) { c.Close() })
+		return err
+	})
+	if err != nil {
+		return fmt.Errorf("failed to create consumer: %w", err)
+	}
+	return nil
+}
+
 func (s *Source) Read(ctx context.Context) (opencdc.Record, error) {
-	msg, err := s.consumer.Receive(ctx)
+	if s.prefetch != nil && s.prefetch.full() {
+		// wait for acknowledgements before reading more records
+		return opencdc.Record{}, sdk.ErrBackoffRetry
+	}
+	if s.watermarks != nil {
+		if watermark, ok := s.watermarks.next(); ok {
+			return s.watermarkRecord(watermark), nil
+		}
+	}
+
+	// schemaIncompatibility is set when a message that doesn't match the
+	// configured schema is passed through
+	var schemaIncompatibility string
+	// the payload is decoded before the message is tracked, so messages that
+	// can't be decoded are handled like other rejected messages
+	var rawPayload []byte
+	var encoding string
+	var payload opencdc.Data
+	msg, err := s.receive(ctx)
+	for err == nil {
+		payload = nil
+		rawPayload, encoding = s.rawPayload(msg)
+		reason, failureType, redeliver := s.dropReason(msg, rawPayload)
+		if reason == "" {
+			var decodeErr error
+			if payload, decodeErr = s.decodePayload(msg, rawPayload); decodeErr != nil {
+				reason, failureType, redeliver = s.schemaFailure(fmt.Sprintf("can't be decoded: %v", decodeErr), s.config.DLQMaxDeliveries > 0)
+			}
+		}
+		if failureType == FailureTypeSchema && s.config.SchemaIncompatibilityAction == SchemaIncompatibilityActionRaw {
+			schemaIncompatibility = reason
+			if payload == nil {
+				payload = opencdc.RawData(rawPayload)
+			}
+			break
+		}
+		if reason == "" {
+			break
+		}
+		sdk.Logger(ctx).Warn().
+			Str("messageID", msg.ID().String()).
+			Uint32("redeliveryCount", msg.RedeliveryCount()).
+			Int("size", len(msg.Payload())).
+			Str("failureType", failureType).
+			Msgf("dropping message that %s", reason)
+		switch {
+		case s.reader != nil:
+			// readers don't acknowledge messages
+		case s.failureProducers[failureType] != nil:
+			if err := s.routeFailure(ctx, msg, failureType, reason); err != nil {
+				return opencdc.Record{}, err
+			}
+		case redeliver:
+			// redelivered until it is routed to the dead letter topic
+			s.redeliver(msg)
+		default:
+			if err = s.consumer.AckID(msg.ID()); err != nil {
+				return opencdc.Record{}, fmt.Errorf("failed to ack dropped message: %w", err)
+			}
+		}
+		msg, err = s.receive(ctx)
+	}
+	if errors.Is(err, sdk.ErrBackoffRetry) {
+		return opencdc.Record{}, err
+	}
 	if err != nil {
 		return opencdc.Record{}, fmt.Errorf("failed to receive message: %w", err)
 	}
@@ -124,14 +546,79 @@ func (s *Source) Read(ctx context.Context) (opencdc.Record, error) {
 	position := Position{
 		MessageID:        msg.ID().Serialize(),
 		SubscriptionName: s.config.SubscriptionName,
+		ReaderCount:      s.readerCount,
+	}
+	if s.topicPositions != nil {
+		position.Topics = s.topicPositions.add(msg)
 	}
 	sdkPos := position.ToSDKPosition()
+	if s.inFlight != nil {
+		s.inFlight.add(msg.ID())
+	}
+	if s.retries != nil {
+		s.retries.add(msg)
+	}
+	if s.ackLatency != nil {
+		s.ackLatency.read(position.MessageID)
+	}
+	if s.deadlines != nil {
+		s.deadlines.add(msg.ID())
+	}
+	if s.acks != nil {
+		s.acks.read(position.MessageID, len(msg.Payload()))
+	}
+	if s.prefetch != nil {
+		s.prefetch.read(position.MessageID)
+	}
+	if s.watermarks != nil {
+		s.watermarks.observe(msg.Topic(), msg.EventTime())
+	}
 
-	metadata := opencdc.Metadata{"pulsar.topic": msg.Topic()}
+	metadata := opencdc.Metadata{
+		metadataTopic:           msg.Topic(),
+		metadataPublishTime:     msg.PublishTime().UTC().Format(time.RFC3339Nano),
+		metadataMessageID:       msg.ID().String(),
+		metadataRedeliveryCount: strconv.FormatUint(uint64(msg.RedeliveryCount()), 10),
+	}
 	metadata.SetCreatedAt(msg.EventTime())
+	if orderingKey := msg.OrderingKey(); orderingKey != "" {
+		metadata[metadataOrderingKey] = orderingKey
+	}
+	if schemaIncompatibility != "" {
+		metadata[metadataSchemaIncompatible] = schemaIncompatibility
+	}
+	for key, val := range msg.Properties() {
+		metadata[metadataPropertiesPrefix+key] = val
+	}
+
+	if s.config.PreserveEncryptionContext {
+		if err := setEncryptionContext(metadata, msg); err != nil {
+			return opencdc.Record{}, err
+		}
+	}
+
+	if s.schemaVersions != nil && msg.SchemaVersion() != nil {
+		version := formatSchemaVersion(msg.SchemaVersion())
+		metadata[metadataSchemaVersion] = version
+		if s.schemaVersions.observe(msg.Topic(), msg.SchemaVersion()) {
+			metadata[metadataSchemaChanged] = "true"
+			sdk.Logger(ctx).Warn().
+				Str("topic", msg.Topic()).
+				Str("schemaVersion", version).
+				Msg("schema of the topic changed")
+		}
+	}
 
 	key := opencdc.RawData(msg.Key())
-	payload := opencdc.RawData(msg.Payload())
+
+	if encoding != "" {
+		metadata[metadataContentEncoding] = encoding
+	}
+	if s.config.InferPayloadType {
+		var contentType string
+		payload, contentType = inferPayload(rawPayload)
+		metadata[metadataContentType] = contentType
+	}
 
 	newRecord := sdk.Util.Source.NewRecordCreate(sdkPos, metadata, key, payload)
 
@@ -140,11 +627,108 @@ func (s *Source) Read(ctx context.Context) (opencdc.Record, error) {
 	return newRecord, nil
 }
 
+// rawPayload returns the payload of the message, decompressed if
+// AutoDecompressPayload is set, together with the detected encoding.
+func (s *Source) rawPayload(msg pulsar.Message) ([]byte, string) {
+	if !s.config.AutoDecompressPayload {
+		return msg.Payload(), ""
+	}
+	return decompressPayload(msg.Payload())
+}
+
+// receive returns the next message, reordered by publish time if a global
+// ordering window is configured.
+func (s *Source) receive(ctx context.Context) (pulsar.Message, error) {
+	next := s.receiveNext
+	if s.partitions != nil {
+		next = s.receiveCheckingPartitions
+	}
+	if s.ordering != nil {
+		return s.ordering.receive(ctx, next)
+	}
+	return next(ctx)
+}
+
+// receiveNext returns the next message, either from the reader, the message
+// channel fed by the consumer or by polling the consumer directly.
+func (s *Source) receiveNext(ctx context.Context) (pulsar.Message, error) {
+	if s.reader != nil {
+		return s.readNext(ctx)
+	}
+	if (s.config.ReadBatchSize > 1 || s.receiverQueue != nil) && s.messages == nil {
+		return s.receiveBatched(ctx)
+	}
+	if s.messages == nil {
+		return s.consumer.Receive(ctx)
+	}
+
+	select {
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	case cm, ok := <-s.messages:
+		if !ok {
+			return nil, errors.New("consumer closed")
+		}
+		return cm.Message, nil
+	}
+}
+
+// dropReason returns why the message should not be returned, or an empty
+// string if it should be returned, together with the failure type used to
+// route it to a dead letter topic. Dropped messages are acknowledged, unless
+// redeliver is true in which case they are negatively acknowledged. The
+// payload is the payload of the message after decompression.
+func (s *Source) dropReason(msg pulsar.Message, payload []byte) (reason, failureType string, redeliver bool) {
+	switch {
+	case s.topicPositions != nil && s.topicPositions.processed(msg):
+		return "was processed before resuming", "", false
+	case s.isUndeliverable(msg):
+		return "exceeded the max deliveries", FailureTypeProcessing, false
+	case s.config.MaxReassembledSize > 0 && len(msg.Payload()) > s.config.MaxReassembledSize:
+		return "exceeded the max reassembled size", "", false
+	case !s.eventTimes.isOpen() && !s.eventTimes.contains(msg):
+		return "is outside the event time range", "", false
+	case s.hasUnpinnedSchema(msg):
+		return s.schemaFailure("doesn't use the pinned schema version", s.config.DLQMaxDeliveries > 0)
+	}
+	if s.payloads != nil {
+		// without a dead letter topic invalid payloads would be redelivered
+		// forever, they are dropped instead
+		if err := s.payloads.validate(payload); errors.Is(err, errPayloadNotJSON) {
+			return "is not valid JSON", FailureTypeDeserialization, s.config.DLQMaxDeliveries > 0
+		} else if err != nil {
+			return s.schemaFailure(fmt.Sprintf("doesn't match the JSON schema: %v", err), s.config.DLQMaxDeliveries > 0)
+		}
+	}
+	return "", "", false
+}
+
+// hasUnpinnedSchema returns true if a schema version is pinned and the message
+// uses another one.
+func (s *Source) hasUnpinnedSchema(msg pulsar.Message) bool {
+	return s.pinnedSchemaVersion != nil && !bytes.Equal(msg.SchemaVersion(), s.pinnedSchemaVersion)
+}
+
+// isUndeliverable returns true if the message exceeded the max deliveries and
+// can't be routed to the dead letter topic.
+func (s *Source) isUndeliverable(msg pulsar.Message) bool {
+	return s.dropUndeliverable && s.config.DLQMaxDeliveries > 0 && msg.RedeliveryCount() >= uint32(s.config.DLQMaxDeliveries)
+}
+
 func (s *Source) Ack(ctx context.Context, position opencdc.Position) error {
+	if s.reader != nil {
+		// readers don't acknowledge messages
+		return nil
+	}
+
 	parsed, err := parsePosition(position)
 	if err != nil {
 		return err
 	}
+	if parsed.Watermark {
+		// watermarks don't refer to a message
+		return nil
+	}
 
 	msgID, err := pulsar.DeserializeMessageID(parsed.MessageID)
 	if err != nil {
@@ -153,30 +737,125 @@ func (s *Source) Ack(ctx context.Context, position opencdc.Position) error {
 
 	sdk.Logger(ctx).Trace().Str("MessageID", msgID.String()).Msg("acked message")
 
-	err = s.consumer.AckID(msgID)
-	if err != nil {
+	if s.partitions != nil && s.partitions.isRemoved(msgID) {
+		// the partition is gone, the message can't be acked anymore
+		sdk.Logger(ctx).Debug().Str("MessageID", msgID.String()).Msg("skipped ack of message from removed partition")
+	} else if s.acks != nil {
+		if err := s.ackIDs(s.acks.add(msgID, parsed.MessageID)); err != nil {
+			return err
+		}
+	} else if err := s.ackID(msgID); err != nil {
 		return fmt.Errorf("failed to ack message: %w", err)
 	}
+	if s.inFlight != nil {
+		s.inFlight.remove(parsed.MessageID)
+	}
+	if s.retries != nil {
+		s.retries.remove(parsed.MessageID)
+	}
+	if s.ackLatency != nil {
+		s.ackLatency.acked(parsed.MessageID)
+	}
+	if s.prefetch != nil {
+		s.prefetch.acked(parsed.MessageID)
+	}
+	if s.deadlines != nil {
+		s.deadlines.remove(parsed.MessageID)
+	}
+	if s.positionStore != nil {
+		if err := s.positionStore.Save(ctx, parsed.SubscriptionName, position); err != nil {
+			return fmt.Errorf("failed to store position: %w", err)
+		}
+	}
 	return nil
 }
 
+// resolvePosition returns the position managed by Conduit, falling back to
+// the position in the position store.
+func (s *Source) resolvePosition(ctx context.Context, pos opencdc.Position) (opencdc.Position, error) {
+	if pos != nil || s.positionStore == nil {
+		return pos, nil
+	}
+
+	stored, err := s.positionStore.Load(ctx, s.config.SubscriptionName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load position: %w", err)
+	}
+	if stored != nil {
+		sdk.Logger(ctx).Info().Msg("resuming from stored position")
+	}
+	return stored, nil
+}
+
 func (s *Source) Teardown(ctx context.Context) error {
+	if s.consumer != nil && s.inFlight != nil && s.config.TeardownTimeout > 0 {
+		s.waitForAcks(ctx)
+	}
+	if s.stopDeadlines != nil {
+		s.stopDeadlines()
+	}
+	if s.stopAckFlush != nil {
+		s.stopAckFlush()
+		if err := s.ackIDs(s.acks.drain()); err != nil {
+			sdk.Logger(ctx).Warn().Err(err).Msg("failed to flush batched acks")
+		}
+	}
+	if s.consumer != nil && s.inFlight != nil && s.config.NackInFlightOnShutdown {
+		s.releaseInFlight(ctx)
+	}
+	s.consumerMu.Lock()
 	if s.consumer != nil {
 		s.consumer.Close()
 	}
+	s.consumerMu.Unlock()
+	if s.reader != nil {
+		s.reader.Close()
+	}
+	closeProducers(s.failureProducers)
 
 	if s.client != nil {
 		s.client.Close()
 	}
+	if s.metricsServer != nil {
+		if err := s.metricsServer.Shutdown(ctx); err != nil {
+			sdk.Logger(ctx).Warn().Err(err).Msg("failed to shut down metrics server")
+		}
+	}
 
 	sdk.Logger(ctx).Debug().Msg("source teardown complete")
 
 	return nil
 }
 
+// releaseInFlight hands messages that were read but not acked back to the
+// broker, so they are redelivered right away instead of after the ack
+// timeout. The client only sends nacks after NackRedeliveryDelay and drops
+// them when the consumer is closed, so the consumer is closed instead. The
+// broker redelivers all messages of a closed consumer that were not acked.
+func (s *Source) releaseInFlight(ctx context.Context) {
+	ids := s.inFlight.drain()
+	s.consumerMu.Lock()
+	s.consumer.Close()
+	s.consumer = nil
+	s.consumerMu.Unlock()
+
+	if len(ids) > 0 {
+		sdk.Logger(ctx).Info().Int("count", len(ids)).Msg("released in-flight messages")
+	}
+}
+
 type Position struct {
 	MessageID        []byte `json:"messageID"`
 	SubscriptionName string `json:"subscriptionName"`
+	// ReaderCount is the number of messages read so far when replaying the
+	// topic with a reader.
+	ReaderCount int `json:"readerCount,omitempty"`
+	// Watermark is set on the positions of watermark records, which don't
+	// refer to a message.
+	Watermark bool `json:"watermark,omitempty"`
+	// Topics contains the serialized ID of the last message read from each
+	// topic when multiple topics are consumed.
+	Topics map[string][]byte `json:"topics,omitempty"`
 }
 
 func parsePosition(pos opencdc.Position) (Position, error) {
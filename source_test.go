@@ -15,13 +15,25 @@
 package pulsar
 
 import (
+	"bytes"
 	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"regexp"
+	"strings"
+	"sync"
 	"testing"
+	"time"
 
 	"github.com/apache/pulsar-client-go/pulsar"
+	"github.com/apache/pulsar-client-go/pulsar/crypto"
+	"github.com/apache/pulsar-client-go/pulsaradmin"
+	"github.com/apache/pulsar-client-go/pulsaradmin/pkg/utils"
 	"github.com/conduitio-labs/conduit-connector-pulsar/test"
 	"github.com/conduitio/conduit-commons/opencdc"
+	sdk "github.com/conduitio/conduit-connector-sdk"
 	"github.com/matryer/is"
 )
 
@@ -96,7 +108,7 @@ func generatePulsarMsgs(from, to int) []*pulsar.ProducerMessage {
 	return msgs
 }
 
-func producePulsarMsgs(is *is.I, topic string, msgs []*pulsar.ProducerMessage) {
+func producePulsarMsgs(is *is.I, topic string, msgs []*pulsar.ProducerMessage) []pulsar.MessageID {
 	client, err := pulsar.NewClient(pulsar.ClientOptions{
 		URL: test.PulsarURL,
 	})
@@ -109,10 +121,13 @@ func producePulsarMsgs(is *is.I, topic string, msgs []*pulsar.ProducerMessage) {
 	is.NoErr(err)
 	defer producer.Close()
 
+	var ids []pulsar.MessageID
 	for _, msg := range msgs {
-		_, err = producer.Send(context.Background(), msg)
+		id, err := producer.Send(context.Background(), msg)
 		is.NoErr(err)
+		ids = append(ids, id)
 	}
+	return ids
 }
 
 // testSourceIntegrationRead reads and acks messages in range [from,to].
@@ -159,3 +174,1525 @@ func testSourceIntegrationRead(
 
 	return positions[len(positions)-1]
 }
+
+func TestSource_Integration_AutoScaleReceiverQueue(t *testing.T) {
+	t.Parallel()
+	is := is.New(t)
+
+	topic := test.SetupTopicName(t, is)
+	cfgMap := newSourceCfg(topic)
+	cfgMap[SourceConfigAutoScaleReceiverQueue] = "true"
+	cfgMap[SourceConfigAutoScaleReceiverQueueMinSize] = "2"
+	cfgMap[SourceConfigAutoScaleReceiverQueueMaxSize] = "4"
+
+	// produce more messages than fit into the queue, so it has to scale up
+	// and refill multiple times
+	recs := generatePulsarMsgs(1, 20)
+	go producePulsarMsgs(is, topic, recs)
+
+	testSourceIntegrationRead(is, cfgMap, nil, recs, false)
+}
+
+func TestSource_Integration_MessageListenerMode(t *testing.T) {
+	t.Parallel()
+	is := is.New(t)
+
+	topic := test.SetupTopicName(t, is)
+	cfgMap := newSourceCfg(topic)
+	cfgMap[SourceConfigMessageListenerMode] = "true"
+
+	recs1 := generatePulsarMsgs(1, 3)
+	go producePulsarMsgs(is, topic, recs1)
+	lastPosition := testSourceIntegrationRead(is, cfgMap, nil, recs1, true)
+
+	// only the first message was acked, the rest is redelivered
+	recs2 := generatePulsarMsgs(4, 6)
+	go producePulsarMsgs(is, topic, recs2)
+
+	var wantRecs []*pulsar.ProducerMessage
+	wantRecs = append(wantRecs, recs1[1:]...)
+	wantRecs = append(wantRecs, recs2...)
+
+	testSourceIntegrationRead(is, cfgMap, lastPosition, wantRecs, false)
+}
+
+func TestSource_Integration_DLQFailurePolicy(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		policy  string
+		wantErr bool
+	}{
+		{policy: DLQFailurePolicyFail, wantErr: true},
+		{policy: DLQFailurePolicyDrop, wantErr: false},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.policy, func(t *testing.T) {
+			is := is.New(t)
+			ctx := context.Background()
+
+			topic := test.SetupTopicName(t, is)
+			cfgMap := newSourceCfg(topic)
+			cfgMap[SourceConfigDlqMaxDeliveries] = "1"
+			cfgMap[SourceConfigDlqTopic] = "persistent://unknown-tenant/unknown-namespace/" + topic + "-DLQ"
+			cfgMap[SourceConfigDlqFailurePolicy] = tc.policy
+
+			underTest := NewSource()
+			defer func() {
+				err := underTest.Teardown(ctx)
+				is.NoErr(err)
+			}()
+
+			err := underTest.Configure(ctx, cfgMap)
+			is.NoErr(err)
+
+			err = underTest.Open(ctx, nil)
+			is.Equal(err != nil, tc.wantErr)
+		})
+	}
+}
+
+func TestSource_Integration_DLQFailurePolicyDrop(t *testing.T) {
+	t.Parallel()
+	is := is.New(t)
+	ctx := context.Background()
+
+	topic := test.SetupTopicName(t, is)
+	dlqTopic := topic + "-DLQ"
+
+	// producers can't be created for a terminated topic, so the dead letter
+	// topic is unavailable while its stats can still be inspected
+	admin, err := pulsaradmin.NewClient(&pulsaradmin.Config{WebServiceURL: test.PulsarAdminURL})
+	is.NoErr(err)
+	dlqTopicName, err := utils.GetTopicName(dlqTopic)
+	is.NoErr(err)
+	is.NoErr(admin.Topics().Create(*dlqTopicName, 0))
+	_, err = admin.Topics().Terminate(*dlqTopicName)
+	is.NoErr(err)
+
+	cfgMap := newSourceCfg(topic)
+	cfgMap[SourceConfigDlqMaxDeliveries] = "1"
+	cfgMap[SourceConfigDlqTopic] = dlqTopic
+	cfgMap[SourceConfigDlqFailurePolicy] = DLQFailurePolicyDrop
+	cfgMap[SourceConfigNackRedeliveryDelay] = "1s"
+
+	// Conduit doesn't nack records, the source is used directly
+	underTest := &Source{positionStore: noopPositionStore{}}
+	defer func() {
+		err := underTest.Teardown(ctx)
+		is.NoErr(err)
+	}()
+
+	err = underTest.Configure(ctx, cfgMap)
+	is.NoErr(err)
+	err = underTest.Open(ctx, nil)
+	is.NoErr(err)
+
+	producePulsarMsgs(is, topic, generatePulsarMsgs(1, 1))
+
+	rec, err := underTest.Read(ctx)
+	is.NoErr(err)
+	err = underTest.Nack(ctx, rec.Position)
+	is.NoErr(err)
+
+	// the redelivered message exceeded the max deliveries, it is acked and
+	// dropped, so no more records are read
+	readCtx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+	_, err = underTest.Read(readCtx)
+	is.True(errors.Is(err, context.DeadlineExceeded))
+
+	topicName, err := utils.GetTopicName(topic)
+	is.NoErr(err)
+	stats, err := admin.Topics().GetStats(*topicName)
+	is.NoErr(err)
+	is.Equal(stats.Subscriptions[cfgMap[SourceConfigSubscriptionName]].MsgBacklog, int64(0))
+
+	dlqStats, err := admin.Topics().GetStats(*dlqTopicName)
+	is.NoErr(err)
+	is.Equal(dlqStats.MsgCounterIn, int64(0))
+}
+
+func TestSource_Integration_PreserveEncryptionContext(t *testing.T) {
+	t.Parallel()
+	is := is.New(t)
+	ctx := context.Background()
+
+	topic := test.SetupTopicName(t, is)
+	cfgMap := newSourceCfg(topic)
+	cfgMap[SourceConfigPreserveEncryptionContext] = "true"
+
+	client, err := pulsar.NewClient(pulsar.ClientOptions{
+		URL: test.PulsarURL,
+	})
+	is.NoErr(err)
+	defer client.Close()
+
+	producer, err := client.CreateProducer(pulsar.ProducerOptions{
+		Topic: topic,
+		Encryption: &pulsar.ProducerEncryptionInfo{
+			KeyReader: crypto.NewFileKeyReader("./test/certs/client.pub.pem", ""),
+			Keys:      []string{"test-encryption-key"},
+		},
+	})
+	is.NoErr(err)
+	defer producer.Close()
+
+	plaintext := []byte("secret payload")
+	_, err = producer.Send(ctx, &pulsar.ProducerMessage{
+		Key:     "test-key",
+		Payload: plaintext,
+	})
+	is.NoErr(err)
+
+	underTest := NewSource()
+	defer func() {
+		err := underTest.Teardown(ctx)
+		is.NoErr(err)
+	}()
+
+	err = underTest.Configure(ctx, cfgMap)
+	is.NoErr(err)
+	err = underTest.Open(ctx, nil)
+	is.NoErr(err)
+
+	rec, err := underTest.Read(ctx)
+	is.NoErr(err)
+
+	// the payload is passed through encrypted
+	is.True(string(rec.Payload.After.Bytes()) != string(plaintext))
+
+	var keys map[string]pulsar.EncryptionKey
+	err = json.Unmarshal([]byte(rec.Metadata[metadataEncryptionKeys]), &keys)
+	is.NoErr(err)
+	_, ok := keys["test-encryption-key"]
+	is.True(ok)
+
+	is.True(rec.Metadata[metadataEncryptionParam] != "")
+}
+
+func TestSource_Integration_BatchIndexAck(t *testing.T) {
+	t.Parallel()
+	is := is.New(t)
+	ctx := context.Background()
+
+	topic := test.SetupTopicName(t, is)
+	cfgMap := newSourceCfg(topic)
+	cfgMap[SourceConfigEnableBatchIndexAck] = "true"
+
+	// produce all messages in a single batch
+	recs := generatePulsarMsgs(1, 3)
+	produceBatchedPulsarMsgs(is, topic, recs)
+
+	underTest := NewSource()
+	err := underTest.Configure(ctx, cfgMap)
+	is.NoErr(err)
+	err = underTest.Open(ctx, nil)
+	is.NoErr(err)
+
+	// the second message fails, all other messages are acked
+	var lastPosition opencdc.Position
+	for i := range recs {
+		rec, err := underTest.Read(ctx)
+		is.NoErr(err)
+		lastPosition = rec.Position
+		if i == 1 {
+			continue
+		}
+		err = underTest.Ack(ctx, rec.Position)
+		is.NoErr(err)
+	}
+
+	err = underTest.Teardown(ctx)
+	is.NoErr(err)
+
+	// only the failed message of the batch is redelivered
+	recs2 := generatePulsarMsgs(4, 4)
+	go producePulsarMsgs(is, topic, recs2)
+
+	wantRecs := []*pulsar.ProducerMessage{recs[1], recs2[0]}
+	testSourceIntegrationRead(is, cfgMap, lastPosition, wantRecs, false)
+}
+
+func produceBatchedPulsarMsgs(is *is.I, topic string, msgs []*pulsar.ProducerMessage) {
+	client, err := pulsar.NewClient(pulsar.ClientOptions{
+		URL: test.PulsarURL,
+	})
+	is.NoErr(err)
+	defer client.Close()
+
+	producer, err := client.CreateProducer(pulsar.ProducerOptions{
+		Topic:                   topic,
+		BatchingMaxPublishDelay: time.Minute,
+		BatchingMaxMessages:     uint(len(msgs)),
+	})
+	is.NoErr(err)
+	defer producer.Close()
+
+	var wg sync.WaitGroup
+	for _, msg := range msgs {
+		wg.Add(1)
+		producer.SendAsync(context.Background(), msg, func(_ pulsar.MessageID, _ *pulsar.ProducerMessage, err error) {
+			defer wg.Done()
+			is.NoErr(err)
+		})
+	}
+	is.NoErr(producer.Flush())
+	wg.Wait()
+}
+
+func TestSource_Integration_FlushAcksOnCommit(t *testing.T) {
+	t.Parallel()
+	is := is.New(t)
+	ctx := context.Background()
+
+	topic := test.SetupTopicName(t, is)
+	cfgMap := newSourceCfg(topic)
+	cfgMap[SourceConfigFlushAcksOnCommit] = "true"
+
+	recs := generatePulsarMsgs(1, 3)
+	producePulsarMsgs(is, topic, recs)
+
+	underTest := NewSource()
+	err := underTest.Configure(ctx, cfgMap)
+	is.NoErr(err)
+	err = underTest.Open(ctx, nil)
+	is.NoErr(err)
+	defer func() {
+		err := underTest.Teardown(ctx)
+		is.NoErr(err)
+	}()
+
+	admin, err := pulsaradmin.NewClient(&pulsaradmin.Config{WebServiceURL: test.PulsarAdminURL})
+	is.NoErr(err)
+	topicName, err := utils.GetTopicName(topic)
+	is.NoErr(err)
+
+	for i := range recs {
+		rec, err := underTest.Read(ctx)
+		is.NoErr(err)
+		err = underTest.Ack(ctx, rec.Position)
+		is.NoErr(err)
+
+		// Ack returned the broker's response, so the broker already moved the
+		// subscription cursor and the backlog reflects the ack
+		stats, err := admin.Topics().GetStats(*topicName)
+		is.NoErr(err)
+		is.Equal(stats.Subscriptions[cfgMap[SourceConfigSubscriptionName]].MsgBacklog, int64(len(recs)-i-1))
+	}
+}
+
+func TestSource_Integration_ReaderMessageLimit(t *testing.T) {
+	t.Parallel()
+	is := is.New(t)
+	ctx := context.Background()
+
+	topic := test.SetupTopicName(t, is)
+	recs := generatePulsarMsgs(1, 5)
+	ids := producePulsarMsgs(is, topic, recs)
+
+	// extract the 2nd and 3rd message
+	cfgMap := newSourceCfg(topic)
+	cfgMap[SourceConfigReaderStartMessageID] = base64.StdEncoding.EncodeToString(ids[1].Serialize())
+	cfgMap[SourceConfigReaderMessageLimit] = "2"
+
+	underTest := NewSource()
+	err := underTest.Configure(ctx, cfgMap)
+	is.NoErr(err)
+	err = underTest.Open(ctx, nil)
+	is.NoErr(err)
+	defer func() {
+		err := underTest.Teardown(ctx)
+		is.NoErr(err)
+	}()
+
+	for _, want := range recs[1:3] {
+		rec, err := underTest.Read(ctx)
+		is.NoErr(err)
+		is.Equal(string(rec.Key.Bytes()), want.Key)
+
+		err = underTest.Ack(ctx, rec.Position)
+		is.NoErr(err)
+	}
+
+	_, err = underTest.Read(ctx)
+	is.True(errors.Is(err, sdk.ErrBackoffRetry))
+}
+
+func TestSource_Configure_ReaderMessageLimitRequiresStartMessageID(t *testing.T) {
+	is := is.New(t)
+
+	cfgMap := newSourceCfg("topic")
+	cfgMap[SourceConfigReaderMessageLimit] = "2"
+
+	err := NewSource().Configure(context.Background(), cfgMap)
+	is.True(err != nil)
+}
+
+func TestSource_Configure_InvalidReaderStartMessageID(t *testing.T) {
+	is := is.New(t)
+
+	cfgMap := newSourceCfg("topic")
+	cfgMap[SourceConfigReaderStartMessageID] = "not a message ID"
+
+	err := NewSource().Configure(context.Background(), cfgMap)
+	is.True(err != nil)
+}
+
+func TestSource_Integration_SubscribeTimeout(t *testing.T) {
+	t.Parallel()
+	is := is.New(t)
+	ctx := context.Background()
+
+	topic := test.SetupTopicName(t, is)
+	cfgMap := newSourceCfg(topic)
+	// subscribing can't complete within the subscribe timeout, even though
+	// the operation timeout is generous
+	cfgMap[SourceConfigSubscribeTimeout] = "1ns"
+
+	underTest := NewSource()
+	err := underTest.Configure(ctx, cfgMap)
+	is.NoErr(err)
+
+	err = underTest.Open(ctx, nil)
+	is.True(errors.Is(err, errOperationTimeout))
+
+	err = underTest.Teardown(ctx)
+	is.NoErr(err)
+}
+
+func TestSource_Integration_MaxReassembledSize(t *testing.T) {
+	t.Parallel()
+	is := is.New(t)
+
+	topic := test.SetupTopicName(t, is)
+	cfgMap := newSourceCfg(topic)
+	cfgMap[SourceConfigMaxReassembledSize] = "1024"
+	cfgMap[SourceConfigMaxPendingChunkedMessage] = "1"
+
+	client, err := pulsar.NewClient(pulsar.ClientOptions{
+		URL: test.PulsarURL,
+	})
+	is.NoErr(err)
+	defer client.Close()
+
+	producer, err := client.CreateProducer(pulsar.ProducerOptions{
+		Topic:               topic,
+		DisableBatching:     true,
+		EnableChunking:      true,
+		ChunkMaxMessageSize: 256,
+	})
+	is.NoErr(err)
+	defer producer.Close()
+
+	// the oversized message is split into chunks and dropped after it is
+	// reassembled, the small message is read normally
+	_, err = producer.Send(context.Background(), &pulsar.ProducerMessage{
+		Key:     "oversized",
+		Payload: bytes.Repeat([]byte("x"), 4096),
+	})
+	is.NoErr(err)
+	recs := generatePulsarMsgs(1, 1)
+	_, err = producer.Send(context.Background(), recs[0])
+	is.NoErr(err)
+
+	testSourceIntegrationRead(is, cfgMap, nil, recs, false)
+}
+
+func TestSource_Configure_ChunkLimits(t *testing.T) {
+	testCases := []struct {
+		name    string
+		cfg     map[string]string
+		wantErr bool
+	}{{
+		name: "defaults",
+		cfg:  map[string]string{},
+	}, {
+		name: "valid",
+		cfg: map[string]string{
+			SourceConfigMaxPendingChunkedMessage:    "10",
+			SourceConfigExpireTimeOfIncompleteChunk: "10s",
+		},
+	}, {
+		name:    "no pending chunked messages",
+		cfg:     map[string]string{SourceConfigMaxPendingChunkedMessage: "0"},
+		wantErr: true,
+	}, {
+		name:    "no expiry",
+		cfg:     map[string]string{SourceConfigExpireTimeOfIncompleteChunk: "0s"},
+		wantErr: true,
+	}}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			is := is.New(t)
+
+			cfgMap := newSourceCfg("topic")
+			for key, val := range tc.cfg {
+				cfgMap[key] = val
+			}
+
+			err := NewSource().Configure(context.Background(), cfgMap)
+			is.Equal(err != nil, tc.wantErr)
+		})
+	}
+}
+
+func TestSource_Integration_EventTimeRange(t *testing.T) {
+	t.Parallel()
+	is := is.New(t)
+
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	topic := test.SetupTopicName(t, is)
+	cfgMap := newSourceCfg(topic)
+	cfgMap[SourceConfigEventTimeFrom] = start.Add(time.Hour).Format(time.RFC3339)
+	cfgMap[SourceConfigEventTimeTo] = start.Add(3 * time.Hour).Format(time.RFC3339)
+
+	// one message per hour, only the ones in hours 1 to 3 are in range, the
+	// last message is in range again
+	msgs := generatePulsarMsgs(0, 6)
+	for i, msg := range msgs {
+		msg.EventTime = start.Add(time.Duration(i) * time.Hour)
+	}
+	msgs[6].EventTime = start.Add(2 * time.Hour)
+	producePulsarMsgs(is, topic, msgs)
+
+	want := []*pulsar.ProducerMessage{msgs[1], msgs[2], msgs[3], msgs[6]}
+	testSourceIntegrationRead(is, cfgMap, nil, want, false)
+}
+
+func TestSource_Configure_EventTimeFromAfterTo(t *testing.T) {
+	is := is.New(t)
+
+	cfgMap := newSourceCfg("topic")
+	cfgMap[SourceConfigEventTimeFrom] = "2024-01-02T00:00:00Z"
+	cfgMap[SourceConfigEventTimeTo] = "2024-01-01T00:00:00Z"
+
+	err := NewSource().Configure(context.Background(), cfgMap)
+	is.True(err != nil)
+}
+
+// nackRecordingConsumer records nacked message IDs and closing the consumer.
+type nackRecordingConsumer struct {
+	pulsar.Consumer
+
+	nacked []pulsar.MessageID
+	closed int
+}
+
+func (c *nackRecordingConsumer) AckID(pulsar.MessageID) error { return nil }
+func (c *nackRecordingConsumer) NackID(id pulsar.MessageID)   { c.nacked = append(c.nacked, id) }
+func (c *nackRecordingConsumer) Close()                       { c.closed++ }
+
+func TestSource_Teardown_NackInFlightOnShutdown(t *testing.T) {
+	is := is.New(t)
+	ctx := context.Background()
+
+	consumer := &nackRecordingConsumer{}
+	underTest := &Source{
+		consumer: consumer,
+		config:   SourceConfig{NackInFlightOnShutdown: true},
+		inFlight: newInFlightTracker(),
+	}
+
+	acked := pulsar.NewMessageID(1, 1, 0, 0)
+	inFlight := pulsar.NewMessageID(1, 2, 0, 0)
+	underTest.inFlight.add(acked)
+	underTest.inFlight.add(inFlight)
+
+	err := underTest.Ack(ctx, Position{MessageID: acked.Serialize()}.ToSDKPosition())
+	is.NoErr(err)
+
+	err = underTest.Teardown(ctx)
+	is.NoErr(err)
+
+	// the message that was not acked is released by closing the consumer,
+	// without waiting for nacks to be sent
+	is.Equal(len(consumer.nacked), 0)
+	is.Equal(consumer.closed, 1)
+	is.Equal(underTest.inFlight.len(), 0)
+}
+
+func TestSource_Teardown_WaitsForAcks(t *testing.T) {
+	testCases := []struct {
+		name        string
+		ackAll      bool
+		wantTimeout bool
+	}{
+		{name: "all acked in time", ackAll: true},
+		{name: "timeout", ackAll: false, wantTimeout: true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			is := is.New(t)
+			ctx := context.Background()
+
+			consumer := &nackRecordingConsumer{}
+			underTest := &Source{
+				consumer: consumer,
+				config: SourceConfig{
+					NackInFlightOnShutdown: true,
+					TeardownTimeout:        200 * time.Millisecond,
+				},
+				inFlight: newInFlightTracker(),
+			}
+
+			first := pulsar.NewMessageID(1, 1, 0, 0)
+			second := pulsar.NewMessageID(1, 2, 0, 0)
+			underTest.inFlight.add(first)
+			underTest.inFlight.add(second)
+
+			// the acks arrive while the source is torn down
+			acked := make(chan struct{})
+			go func() {
+				defer close(acked)
+				time.Sleep(20 * time.Millisecond)
+				is.NoErr(underTest.Ack(ctx, Position{MessageID: first.Serialize()}.ToSDKPosition()))
+				if tc.ackAll {
+					is.NoErr(underTest.Ack(ctx, Position{MessageID: second.Serialize()}.ToSDKPosition()))
+				}
+			}()
+
+			start := time.Now()
+			is.NoErr(underTest.Teardown(ctx))
+			<-acked
+			is.Equal(time.Since(start) >= underTest.config.TeardownTimeout, tc.wantTimeout)
+			is.Equal(consumer.closed, 1)
+		})
+	}
+}
+
+func TestSource_Integration_NotifySchemaChange(t *testing.T) {
+	t.Parallel()
+	is := is.New(t)
+	ctx := context.Background()
+
+	topic := test.SetupTopicName(t, is)
+	cfgMap := newSourceCfg(topic)
+	cfgMap[SourceConfigNotifySchemaChange] = "true"
+
+	client, err := pulsar.NewClient(pulsar.ClientOptions{
+		URL: test.PulsarURL,
+	})
+	is.NoErr(err)
+	defer client.Close()
+
+	// the second schema adds an optional field, evolving the first one
+	schemas := []string{
+		`{"type":"record","name":"Example","fields":[{"name":"id","type":"int"}]}`,
+		`{"type":"record","name":"Example","fields":[{"name":"id","type":"int"},{"name":"name","type":["null","string"],"default":null}]}`,
+	}
+	for i, def := range schemas {
+		producer, err := client.CreateProducer(pulsar.ProducerOptions{
+			Topic:  topic,
+			Schema: pulsar.NewJSONSchema(def, nil),
+		})
+		is.NoErr(err)
+		_, err = producer.Send(ctx, &pulsar.ProducerMessage{
+			Key:   fmt.Sprintf("test-key-%d", i),
+			Value: map[string]any{"id": i},
+		})
+		is.NoErr(err)
+		producer.Close()
+	}
+
+	underTest := NewSource()
+	err = underTest.Configure(ctx, cfgMap)
+	is.NoErr(err)
+	err = underTest.Open(ctx, nil)
+	is.NoErr(err)
+	defer func() {
+		err := underTest.Teardown(ctx)
+		is.NoErr(err)
+	}()
+
+	first, err := underTest.Read(ctx)
+	is.NoErr(err)
+	is.Equal(first.Metadata[metadataSchemaVersion], "0")
+	_, changed := first.Metadata[metadataSchemaChanged]
+	is.True(!changed)
+
+	second, err := underTest.Read(ctx)
+	is.NoErr(err)
+	is.Equal(second.Metadata[metadataSchemaVersion], "1")
+	is.Equal(second.Metadata[metadataSchemaChanged], "true")
+}
+
+func TestSource_Integration_PinnedSchemaVersion(t *testing.T) {
+	t.Parallel()
+	is := is.New(t)
+	ctx := context.Background()
+
+	topic := test.SetupTopicName(t, is)
+	cfgMap := newSourceCfg(topic)
+	cfgMap[SourceConfigAdminURL] = test.PulsarAdminURL
+	cfgMap[SourceConfigPinnedSchemaVersion] = "1"
+
+	client, err := pulsar.NewClient(pulsar.ClientOptions{
+		URL: test.PulsarURL,
+	})
+	is.NoErr(err)
+	defer client.Close()
+
+	schemas := []string{
+		`{"type":"record","name":"Example","fields":[{"name":"id","type":"int"}]}`,
+		`{"type":"record","name":"Example","fields":[{"name":"id","type":"int"},{"name":"name","type":["null","string"],"default":null}]}`,
+	}
+	producers := make([]pulsar.Producer, len(schemas))
+	for i, def := range schemas {
+		producers[i], err = client.CreateProducer(pulsar.ProducerOptions{
+			Topic:  topic,
+			Schema: pulsar.NewJSONSchema(def, nil),
+		})
+		is.NoErr(err)
+		defer producers[i].Close()
+	}
+
+	// messages alternate between schema version 0 and 1
+	for i := 0; i < 4; i++ {
+		_, err = producers[i%2].Send(ctx, &pulsar.ProducerMessage{
+			Key:   fmt.Sprintf("test-key-%d", i),
+			Value: map[string]any{"id": i},
+		})
+		is.NoErr(err)
+	}
+
+	underTest := NewSource()
+	err = underTest.Configure(ctx, cfgMap)
+	is.NoErr(err)
+	err = underTest.Open(ctx, nil)
+	is.NoErr(err)
+	defer func() {
+		err := underTest.Teardown(ctx)
+		is.NoErr(err)
+	}()
+
+	for _, wantKey := range []string{"test-key-1", "test-key-3"} {
+		rec, err := underTest.Read(ctx)
+		is.NoErr(err)
+		is.Equal(string(rec.Key.Bytes()), wantKey)
+	}
+}
+
+func TestSource_Integration_PinnedSchemaVersionMissing(t *testing.T) {
+	t.Parallel()
+	is := is.New(t)
+	ctx := context.Background()
+
+	topic := test.SetupTopicName(t, is)
+	cfgMap := newSourceCfg(topic)
+	cfgMap[SourceConfigAdminURL] = test.PulsarAdminURL
+	cfgMap[SourceConfigPinnedSchemaVersion] = "5"
+	producePulsarMsgs(is, topic, generatePulsarMsgs(1, 1))
+
+	underTest := NewSource()
+	err := underTest.Configure(ctx, cfgMap)
+	is.NoErr(err)
+	err = underTest.Open(ctx, nil)
+	is.True(err != nil)
+	is.NoErr(underTest.Teardown(ctx))
+}
+
+func TestSource_Configure_InvalidPinnedSchemaVersion(t *testing.T) {
+	is := is.New(t)
+
+	for _, version := range []string{"latest", "-1"} {
+		cfgMap := newSourceCfg("topic")
+		cfgMap[SourceConfigAdminURL] = test.PulsarAdminURL
+		cfgMap[SourceConfigPinnedSchemaVersion] = version
+
+		err := NewSource().Configure(context.Background(), cfgMap)
+		is.True(err != nil)
+	}
+}
+
+// versionedMessage is a message with a schema version.
+type versionedMessage struct {
+	readableMessage
+	schemaVersion []byte
+}
+
+func (m versionedMessage) SchemaVersion() []byte { return m.schemaVersion }
+
+// rejectRecordingConsumer records acked and nacked message IDs.
+type rejectRecordingConsumer struct {
+	queueConsumer
+
+	acked  []pulsar.MessageID
+	nacked []pulsar.MessageID
+}
+
+func (c *rejectRecordingConsumer) AckID(id pulsar.MessageID) error {
+	c.acked = append(c.acked, id)
+	return nil
+}
+func (c *rejectRecordingConsumer) NackID(id pulsar.MessageID) { c.nacked = append(c.nacked, id) }
+
+func TestSource_Read_PinnedSchemaVersion(t *testing.T) {
+	v0, _ := parseSchemaVersion("0")
+	v1, _ := parseSchemaVersion("1")
+	unpinned := versionedMessage{readableMessage{fakeMessage{id: pulsar.NewMessageID(1, 1, 0, 0)}}, v0}
+	pinned := versionedMessage{readableMessage{fakeMessage{id: pulsar.NewMessageID(1, 2, 0, 0)}}, v1}
+
+	testCases := []struct {
+		name       string
+		maxDeliver int
+		wantAcked  int
+		wantNacked int
+	}{
+		{name: "without dead letter topic", maxDeliver: 0, wantAcked: 1, wantNacked: 0},
+		{name: "with dead letter topic", maxDeliver: 3, wantAcked: 0, wantNacked: 1},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			is := is.New(t)
+
+			consumer := &rejectRecordingConsumer{queueConsumer: queueConsumer{messages: []pulsar.Message{unpinned, pinned}}}
+			underTest := &Source{
+				consumer:            consumer,
+				config:              SourceConfig{DLQMaxDeliveries: tc.maxDeliver},
+				pinnedSchemaVersion: v1,
+			}
+
+			rec, err := underTest.Read(context.Background())
+			is.NoErr(err)
+			pos, err := parsePosition(rec.Position)
+			is.NoErr(err)
+			is.Equal(pos.MessageID, pinned.ID().Serialize())
+			is.Equal(len(consumer.acked), tc.wantAcked)
+			is.Equal(len(consumer.nacked), tc.wantNacked)
+		})
+	}
+}
+
+func TestSource_Ack_MessageIDFromPosition(t *testing.T) {
+	is := is.New(t)
+	ctx := context.Background()
+
+	msgID := pulsar.NewMessageID(3, 7, 2, 1)
+	consumer := &rejectRecordingConsumer{queueConsumer: queueConsumer{
+		messages: []pulsar.Message{readableMessage{fakeMessage{topic: "test-topic", id: msgID}}},
+	}}
+	underTest := &Source{
+		consumer: consumer,
+		config:   SourceConfig{SubscriptionName: "test-subscription"},
+	}
+
+	rec, err := underTest.Read(ctx)
+	is.NoErr(err)
+
+	pos, err := parsePosition(rec.Position)
+	is.NoErr(err)
+	is.Equal(pos.SubscriptionName, "test-subscription")
+	is.Equal(pos.MessageID, msgID.Serialize())
+
+	err = underTest.Ack(ctx, rec.Position)
+	is.NoErr(err)
+	is.Equal(len(consumer.acked), 1)
+	is.Equal(consumer.acked[0].String(), msgID.String())
+}
+
+// propertiesMessage is a readableMessage with properties.
+type propertiesMessage struct {
+	readableMessage
+	properties map[string]string
+}
+
+func (m propertiesMessage) Properties() map[string]string { return m.properties }
+
+func TestSource_Read_Properties(t *testing.T) {
+	is := is.New(t)
+
+	msg := propertiesMessage{
+		readableMessage{fakeMessage{topic: "test-topic", id: pulsar.NewMessageID(1, 1, 0, 0)}},
+		map[string]string{"origin": "billing", "topic": "from-property"},
+	}
+	underTest := &Source{
+		consumer: &queueConsumer{messages: []pulsar.Message{msg}},
+	}
+
+	rec, err := underTest.Read(context.Background())
+	is.NoErr(err)
+	is.Equal(rec.Metadata["pulsar.properties.origin"], "billing")
+	is.Equal(rec.Metadata["pulsar.properties.topic"], "from-property")
+	// properties don't clobber other metadata
+	is.Equal(rec.Metadata["pulsar.topic"], "test-topic")
+}
+
+// redeliveredMessage is a message that was delivered before.
+type redeliveredMessage struct {
+	readableMessage
+	redeliveryCount uint32
+}
+
+func (m redeliveredMessage) RedeliveryCount() uint32 { return m.redeliveryCount }
+
+func TestSource_Read_MessageMetadata(t *testing.T) {
+	is := is.New(t)
+
+	id := pulsar.NewMessageID(1, 2, 0, 0)
+	publishTime := time.Date(2024, 3, 1, 12, 0, 0, 500, time.UTC)
+	msg := redeliveredMessage{
+		readableMessage{fakeMessage{topic: "test-topic", id: id, publishTime: publishTime}},
+		3,
+	}
+	underTest := &Source{
+		consumer: &queueConsumer{messages: []pulsar.Message{msg}},
+	}
+
+	rec, err := underTest.Read(context.Background())
+	is.NoErr(err)
+	is.Equal(rec.Metadata[metadataTopic], "test-topic")
+	is.Equal(rec.Metadata[metadataPublishTime], "2024-03-01T12:00:00.0000005Z")
+	is.Equal(rec.Metadata[metadataMessageID], id.String())
+	is.Equal(rec.Metadata[metadataRedeliveryCount], "3")
+}
+
+func TestOrderingKey_RoundTrip(t *testing.T) {
+	is := is.New(t)
+	ctx := context.Background()
+
+	msg := orderedMessage{
+		payloadMessage: payloadMessage{
+			readableMessage: readableMessage{fakeMessage{topic: "test-topic", id: pulsar.NewMessageID(1, 1, 0, 0)}},
+			payload:         []byte(exampleMessage),
+		},
+		orderingKey: "customer-42",
+	}
+	source := &Source{
+		consumer: &queueConsumer{messages: []pulsar.Message{msg}},
+	}
+	producer := &recordingProducer{}
+	destination := &Destination{producer: producer}
+
+	rec, err := source.Read(ctx)
+	is.NoErr(err)
+	is.Equal(rec.Metadata[metadataOrderingKey], "customer-42")
+
+	written, err := destination.Write(ctx, []opencdc.Record{rec})
+	is.NoErr(err)
+	is.Equal(written, 1)
+	is.Equal(producer.sent[0].OrderingKey, "customer-42")
+	is.Equal(producer.sent[0].Key, "")
+}
+
+func TestSource_Integration_Properties(t *testing.T) {
+	t.Parallel()
+	is := is.New(t)
+	ctx := context.Background()
+
+	topic := test.SetupTopicName(t, is)
+	cfgMap := newSourceCfg(topic)
+
+	producePulsarMsgs(is, topic, []*pulsar.ProducerMessage{{
+		Payload:    []byte("test-payload"),
+		Properties: map[string]string{"origin": "billing", "traceID": "abc123"},
+	}})
+
+	underTest := NewSource()
+	err := underTest.Configure(ctx, cfgMap)
+	is.NoErr(err)
+	err = underTest.Open(ctx, nil)
+	is.NoErr(err)
+	defer func() {
+		err := underTest.Teardown(ctx)
+		is.NoErr(err)
+	}()
+
+	rec, err := underTest.Read(ctx)
+	is.NoErr(err)
+	is.Equal(rec.Metadata["pulsar.properties.origin"], "billing")
+	is.Equal(rec.Metadata["pulsar.properties.traceID"], "abc123")
+}
+
+func TestSource_Configure_Topics(t *testing.T) {
+	testCases := []struct {
+		name          string
+		topic         string
+		topics        string
+		topicsPattern string
+		want          []string
+		wantErr       bool
+		readerMode    bool
+	}{
+		{name: "topic only", topic: "a", want: []string{"a"}},
+		{name: "topics only", topics: "a,b,a", want: []string{"a", "b"}},
+		{name: "topics pattern only", topicsPattern: "persistent://public/default/events-.*"},
+		{name: "topic and topics", topic: "a", topics: "b,c", wantErr: true},
+		{name: "topic and topics pattern", topic: "a", topicsPattern: "events-.*", wantErr: true},
+		{name: "topics and topics pattern", topics: "a,b", topicsPattern: "events-.*", wantErr: true},
+		{name: "invalid topics pattern", topicsPattern: "events-(", wantErr: true},
+		{name: "no topic", wantErr: true},
+		{name: "reader with multiple topics", topics: "a,b", readerMode: true, wantErr: true},
+		{name: "reader with topics pattern", topicsPattern: "events-.*", readerMode: true, wantErr: true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			is := is.New(t)
+
+			cfgMap := newSourceCfg(tc.topic)
+			if tc.topics != "" {
+				cfgMap[SourceConfigTopics] = tc.topics
+			}
+			if tc.topicsPattern != "" {
+				cfgMap[SourceConfigTopicsPattern] = tc.topicsPattern
+			}
+			if tc.readerMode {
+				cfgMap[SourceConfigReaderStartMessageID] = base64.StdEncoding.EncodeToString(pulsar.EarliestMessageID().Serialize())
+			}
+
+			underTest := &Source{}
+			err := underTest.Configure(context.Background(), cfgMap)
+			if tc.wantErr {
+				is.True(err != nil)
+				return
+			}
+			is.NoErr(err)
+			is.Equal(underTest.config.topics(), tc.want)
+		})
+	}
+}
+
+func TestSource_Integration_Topics(t *testing.T) {
+	t.Parallel()
+	is := is.New(t)
+	ctx := context.Background()
+
+	topic1 := test.SetupTopicName(t, is)
+	topic2 := topic1 + "-2"
+	test.DeletePulsarTopic(is, topic2)
+	cfgMap := newSourceCfg("")
+	cfgMap[SourceConfigTopics] = topic1 + "," + topic2
+	cfgMap[SourceConfigSubscriptionName] = topic1 + "-subscription"
+
+	producePulsarMsgs(is, topic1, generatePulsarMsgs(1, 1))
+	producePulsarMsgs(is, topic2, generatePulsarMsgs(2, 2))
+
+	underTest := NewSource()
+	err := underTest.Configure(ctx, cfgMap)
+	is.NoErr(err)
+	err = underTest.Open(ctx, nil)
+	is.NoErr(err)
+	defer func() {
+		err := underTest.Teardown(ctx)
+		is.NoErr(err)
+	}()
+
+	// the metadata reflects the topic each message originates from
+	gotTopics := make(map[string]string)
+	for i := 0; i < 2; i++ {
+		rec, err := underTest.Read(ctx)
+		is.NoErr(err)
+		gotTopics[string(rec.Key.Bytes())] = rec.Metadata["pulsar.topic"]
+		err = underTest.Ack(ctx, rec.Position)
+		is.NoErr(err)
+	}
+	is.Equal(len(gotTopics), 2)
+	is.True(strings.HasSuffix(gotTopics["test-key-1"], topic1))
+	is.True(strings.HasSuffix(gotTopics["test-key-2"], topic2))
+}
+
+func TestSource_Integration_TopicsPattern(t *testing.T) {
+	t.Parallel()
+	is := is.New(t)
+	ctx := context.Background()
+
+	prefix := test.SetupTopicName(t, is)
+	topic1 := prefix + "-events-1"
+	topic2 := prefix + "-events-2"
+	test.DeletePulsarTopic(is, topic1)
+	test.DeletePulsarTopic(is, topic2)
+
+	cfgMap := newSourceCfg("")
+	cfgMap[SourceConfigTopicsPattern] = "persistent://public/default/" + regexp.QuoteMeta(prefix) + "-events-.*"
+	cfgMap[SourceConfigAutoDiscoveryPeriod] = "1s"
+	cfgMap[SourceConfigSubscriptionName] = topic1 + "-subscription"
+
+	producePulsarMsgs(is, topic1, generatePulsarMsgs(1, 1))
+
+	underTest := NewSource()
+	err := underTest.Configure(ctx, cfgMap)
+	is.NoErr(err)
+	err = underTest.Open(ctx, nil)
+	is.NoErr(err)
+	defer func() {
+		err := underTest.Teardown(ctx)
+		is.NoErr(err)
+	}()
+
+	// the second topic is created after subscribing and discovered later
+	producePulsarMsgs(is, topic2, generatePulsarMsgs(2, 2))
+
+	gotTopics := make(map[string]string)
+	for i := 0; i < 2; i++ {
+		rec, err := underTest.Read(ctx)
+		is.NoErr(err)
+		gotTopics[string(rec.Key.Bytes())] = rec.Metadata["pulsar.topic"]
+		err = underTest.Ack(ctx, rec.Position)
+		is.NoErr(err)
+	}
+	is.True(strings.HasSuffix(gotTopics["test-key-1"], topic1))
+	is.True(strings.HasSuffix(gotTopics["test-key-2"], topic2))
+}
+
+func TestSource_Configure_SubscriptionType(t *testing.T) {
+	testCases := []struct {
+		subscriptionType string
+		want             pulsar.SubscriptionType
+		wantErr          bool
+	}{
+		{subscriptionType: "", want: pulsar.Exclusive},
+		{subscriptionType: "exclusive", want: pulsar.Exclusive},
+		{subscriptionType: "shared", want: pulsar.Shared},
+		{subscriptionType: "failover", want: pulsar.Failover},
+		{subscriptionType: "key_shared", want: pulsar.KeyShared},
+		{subscriptionType: "round_robin", wantErr: true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.subscriptionType, func(t *testing.T) {
+			is := is.New(t)
+
+			cfgMap := newSourceCfg("topic")
+			if tc.subscriptionType != "" {
+				cfgMap[SourceConfigSubscriptionType] = tc.subscriptionType
+			}
+
+			underTest := &Source{}
+			err := underTest.Configure(context.Background(), cfgMap)
+			if tc.wantErr {
+				is.True(err != nil)
+				return
+			}
+			is.NoErr(err)
+			is.Equal(toSubscriptionType(underTest.config.SubscriptionType), tc.want)
+		})
+	}
+}
+
+func TestSource_Configure_AckMode(t *testing.T) {
+	testCases := []struct {
+		ackMode          string
+		subscriptionType string
+		wantErr          bool
+	}{
+		{ackMode: AckModeIndividual, subscriptionType: SubscriptionTypeShared},
+		{ackMode: AckModeCumulative, subscriptionType: SubscriptionTypeExclusive},
+		{ackMode: AckModeCumulative, subscriptionType: SubscriptionTypeFailover},
+		{ackMode: AckModeCumulative, subscriptionType: SubscriptionTypeShared, wantErr: true},
+		{ackMode: AckModeCumulative, subscriptionType: SubscriptionTypeKeyShared, wantErr: true},
+		{ackMode: "batched", subscriptionType: SubscriptionTypeExclusive, wantErr: true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.ackMode+"/"+tc.subscriptionType, func(t *testing.T) {
+			is := is.New(t)
+
+			cfgMap := newSourceCfg("topic")
+			cfgMap[SourceConfigAckMode] = tc.ackMode
+			cfgMap[SourceConfigSubscriptionType] = tc.subscriptionType
+
+			err := (&Source{}).Configure(context.Background(), cfgMap)
+			is.Equal(err != nil, tc.wantErr)
+		})
+	}
+}
+
+func TestSource_Configure_CumulativeAckRedelivery(t *testing.T) {
+	testCases := []struct {
+		name    string
+		cfg     map[string]string
+		wantErr bool
+	}{
+		{name: "processing deadline", cfg: map[string]string{
+			SourceConfigProcessingDeadline: "1m",
+		}, wantErr: true},
+		{name: "ack timeout", cfg: map[string]string{
+			SourceConfigAckTimeout: "1m",
+		}, wantErr: true},
+		{name: "dlq max deliveries", cfg: map[string]string{
+			SourceConfigDlqMaxDeliveries: "3",
+		}, wantErr: true},
+		{name: "pinned schema version", cfg: map[string]string{
+			SourceConfigAdminURL:            test.PulsarAdminURL,
+			SourceConfigPinnedSchemaVersion: "1",
+		}, wantErr: true},
+		{name: "json schema validation", cfg: map[string]string{
+			SourceConfigJsonSchemaValidation: `{"type": "object"}`,
+		}, wantErr: true},
+		{name: "json schema validation skipping invalid messages", cfg: map[string]string{
+			SourceConfigJsonSchemaValidation:        `{"type": "object"}`,
+			SourceConfigSchemaIncompatibilityAction: SchemaIncompatibilityActionSkip,
+		}},
+		{name: "nack in flight on shutdown", cfg: map[string]string{
+			SourceConfigNackInFlightOnShutdown: "true",
+		}, wantErr: true},
+		{name: "retry", cfg: map[string]string{
+			SourceConfigEnableRetry: "true",
+		}, wantErr: true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			is := is.New(t)
+
+			cfgMap := newSourceCfg("topic")
+			cfgMap[SourceConfigAckMode] = AckModeCumulative
+			for k, v := range tc.cfg {
+				cfgMap[k] = v
+			}
+
+			err := (&Source{}).Configure(context.Background(), cfgMap)
+			is.Equal(err != nil, tc.wantErr)
+			if tc.wantErr {
+				is.True(strings.Contains(err.Error(), fmt.Sprintf("%q %q", SourceConfigAckMode, AckModeCumulative)))
+			}
+		})
+	}
+}
+
+func TestSource_Configure_ReadCompacted(t *testing.T) {
+	testCases := []struct {
+		subscriptionType string
+		wantErr          bool
+	}{
+		{subscriptionType: SubscriptionTypeExclusive},
+		{subscriptionType: SubscriptionTypeFailover},
+		{subscriptionType: SubscriptionTypeShared, wantErr: true},
+		{subscriptionType: SubscriptionTypeKeyShared, wantErr: true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.subscriptionType, func(t *testing.T) {
+			is := is.New(t)
+
+			cfgMap := newSourceCfg("topic")
+			cfgMap[SourceConfigReadCompacted] = "true"
+			cfgMap[SourceConfigSubscriptionType] = tc.subscriptionType
+
+			err := (&Source{}).Configure(context.Background(), cfgMap)
+			is.Equal(err != nil, tc.wantErr)
+		})
+	}
+}
+
+func TestConfig_Validate_ListenerName(t *testing.T) {
+	testCases := []struct {
+		name         string
+		listenerName string
+		wantErr      bool
+	}{
+		{name: "unset"},
+		{name: "set", listenerName: "external"},
+		{name: "blank", listenerName: "  ", wantErr: true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			is := is.New(t)
+
+			cfg := Config{
+				ListenerName:      tc.listenerName,
+				KeepAliveInterval: 30 * time.Second,
+				MaxBackoff:        time.Minute,
+			}
+			is.Equal(cfg.Validate() != nil, tc.wantErr)
+		})
+	}
+}
+
+// cumulativeAckConsumer records individual and cumulative acknowledgements.
+type cumulativeAckConsumer struct {
+	queueConsumer
+
+	acked           []pulsar.MessageID
+	ackedCumulative []pulsar.MessageID
+}
+
+func (c *cumulativeAckConsumer) AckID(id pulsar.MessageID) error {
+	c.acked = append(c.acked, id)
+	return nil
+}
+
+func (c *cumulativeAckConsumer) AckIDCumulative(id pulsar.MessageID) error {
+	c.ackedCumulative = append(c.ackedCumulative, id)
+	return nil
+}
+
+// responseAckConsumer acknowledges messages with the broker's response.
+type responseAckConsumer struct {
+	cumulativeAckConsumer
+
+	err        error
+	responded  []pulsar.MessageID
+	cumulative []pulsar.MessageID
+}
+
+func (c *responseAckConsumer) AckIDWithResponse(id pulsar.MessageID) error {
+	c.responded = append(c.responded, id)
+	return c.err
+}
+
+func (c *responseAckConsumer) AckIDWithResponseCumulative(id pulsar.MessageID) error {
+	c.cumulative = append(c.cumulative, id)
+	return c.err
+}
+
+func TestSource_Ack_FlushAcksOnCommit(t *testing.T) {
+	testCases := []struct {
+		name    string
+		ackMode string
+		wantErr error
+	}{
+		{name: "individual", ackMode: AckModeIndividual},
+		{name: "cumulative", ackMode: AckModeCumulative},
+		{name: "broker error", ackMode: AckModeIndividual, wantErr: errors.New("ack rejected")},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			is := is.New(t)
+			ctx := context.Background()
+
+			consumer := &responseAckConsumer{
+				cumulativeAckConsumer: cumulativeAckConsumer{queueConsumer: queueConsumer{messages: []pulsar.Message{
+					readableMessage{fakeMessage{topic: "topic", id: pulsar.NewMessageID(1, 1, 0, 0)}},
+				}}},
+				err: tc.wantErr,
+			}
+			underTest := &Source{
+				consumer: consumer,
+				config: SourceConfig{
+					Config:            Config{Topic: "topic"},
+					AckMode:           tc.ackMode,
+					FlushAcksOnCommit: true,
+				},
+			}
+
+			rec, err := underTest.Read(ctx)
+			is.NoErr(err)
+			err = underTest.Ack(ctx, rec.Position)
+			if tc.wantErr != nil {
+				is.True(errors.Is(err, tc.wantErr))
+			} else {
+				is.NoErr(err)
+			}
+
+			// the ack didn't go through the ack grouping of the client
+			is.Equal(len(consumer.acked)+len(consumer.ackedCumulative), 0)
+			if tc.ackMode == AckModeCumulative {
+				is.Equal(len(consumer.cumulative), 1)
+			} else {
+				is.Equal(len(consumer.responded), 1)
+			}
+		})
+	}
+}
+
+func TestSource_Ack_Cumulative(t *testing.T) {
+	is := is.New(t)
+	ctx := context.Background()
+
+	consumer := &cumulativeAckConsumer{queueConsumer: queueConsumer{messages: []pulsar.Message{
+		readableMessage{fakeMessage{topic: "topic", id: pulsar.NewMessageID(1, 1, 0, 0)}},
+		readableMessage{fakeMessage{topic: "topic", id: pulsar.NewMessageID(1, 2, 0, 0)}},
+	}}}
+	underTest := &Source{
+		consumer: consumer,
+		config:   SourceConfig{Config: Config{Topic: "topic"}, AckMode: AckModeCumulative},
+	}
+
+	for i := 0; i < 2; i++ {
+		rec, err := underTest.Read(ctx)
+		is.NoErr(err)
+		is.NoErr(underTest.Ack(ctx, rec.Position))
+	}
+
+	is.Equal(len(consumer.acked), 0)
+	is.Equal(len(consumer.ackedCumulative), 2)
+	is.Equal(consumer.ackedCumulative[1].EntryID(), int64(2))
+}
+
+func TestSource_Configure_SubscriptionInitialPosition(t *testing.T) {
+	testCases := []struct {
+		position string
+		want     pulsar.SubscriptionInitialPosition
+		wantErr  bool
+	}{
+		{position: "", want: pulsar.SubscriptionPositionEarliest},
+		{position: "earliest", want: pulsar.SubscriptionPositionEarliest},
+		{position: "latest", want: pulsar.SubscriptionPositionLatest},
+		{position: "oldest", wantErr: true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.position, func(t *testing.T) {
+			is := is.New(t)
+
+			cfgMap := newSourceCfg("topic")
+			if tc.position != "" {
+				cfgMap[SourceConfigSubscriptionInitialPosition] = tc.position
+			}
+
+			underTest := &Source{}
+			err := underTest.Configure(context.Background(), cfgMap)
+			if tc.wantErr {
+				is.True(err != nil)
+				return
+			}
+			is.NoErr(err)
+			is.Equal(toSubscriptionInitialPosition(underTest.config.SubscriptionInitialPosition), tc.want)
+		})
+	}
+}
+
+func TestSource_Configure_SubscriptionTypeInitialPosition(t *testing.T) {
+	for _, subscriptionType := range []string{SubscriptionTypeExclusive, SubscriptionTypeShared, SubscriptionTypeFailover, SubscriptionTypeKeyShared} {
+		for _, position := range []string{SubscriptionPositionEarliest, SubscriptionPositionLatest, "oldest"} {
+			t.Run(subscriptionType+"/"+position, func(t *testing.T) {
+				is := is.New(t)
+
+				cfgMap := newSourceCfg("topic")
+				cfgMap[SourceConfigSubscriptionType] = subscriptionType
+				cfgMap[SourceConfigSubscriptionInitialPosition] = position
+
+				underTest := &Source{}
+				err := underTest.Configure(context.Background(), cfgMap)
+				if position == "oldest" {
+					is.True(err != nil) // unsupported with every subscription type
+					return
+				}
+				is.NoErr(err)
+				is.Equal(underTest.config.SubscriptionType, subscriptionType)
+				is.Equal(underTest.config.SubscriptionInitialPosition, position)
+			})
+		}
+	}
+}
+
+func TestSource_Integration_SubscriptionInitialPositionLatest(t *testing.T) {
+	t.Parallel()
+	is := is.New(t)
+	ctx := context.Background()
+
+	topic := test.SetupTopicName(t, is)
+	cfgMap := newSourceCfg(topic)
+	cfgMap[SourceConfigSubscriptionInitialPosition] = SubscriptionPositionLatest
+
+	// produced before the subscription is created, it is skipped
+	producePulsarMsgs(is, topic, generatePulsarMsgs(1, 1))
+
+	underTest := NewSource()
+	err := underTest.Configure(ctx, cfgMap)
+	is.NoErr(err)
+	err = underTest.Open(ctx, nil)
+	is.NoErr(err)
+	defer func() {
+		err := underTest.Teardown(ctx)
+		is.NoErr(err)
+	}()
+
+	producePulsarMsgs(is, topic, generatePulsarMsgs(2, 2))
+
+	rec, err := underTest.Read(ctx)
+	is.NoErr(err)
+	is.Equal(string(rec.Key.Bytes()), "test-key-2")
+}
+
+func TestSource_Integration_SharedSubscription(t *testing.T) {
+	t.Parallel()
+	is := is.New(t)
+	ctx := context.Background()
+
+	topic := test.SetupTopicName(t, is)
+	cfgMap := newSourceCfg(topic)
+	cfgMap[SourceConfigSubscriptionName] = "shared-subscription"
+	cfgMap[SourceConfigSubscriptionType] = SubscriptionTypeShared
+
+	// multiple instances can consume from the same shared subscription
+	for i := 0; i < 2; i++ {
+		underTest := NewSource()
+		err := underTest.Configure(ctx, cfgMap)
+		is.NoErr(err)
+		err = underTest.Open(ctx, nil)
+		is.NoErr(err)
+		defer func() {
+			err := underTest.Teardown(ctx)
+			is.NoErr(err)
+		}()
+	}
+}
+
+func TestSource_Integration_ResetSubscription(t *testing.T) {
+	t.Parallel()
+	is := is.New(t)
+
+	topic := test.SetupTopicName(t, is)
+	cfgMap := newSourceCfg(topic)
+
+	recs := generatePulsarMsgs(1, 3)
+	go producePulsarMsgs(is, topic, recs)
+	lastPosition := testSourceIntegrationRead(is, cfgMap, nil, recs, false)
+
+	// all messages were acked, resetting the subscription replays them
+	cfgMap[SourceConfigAdminURL] = test.PulsarAdminURL
+	cfgMap[SourceConfigResetSubscription] = SubscriptionPositionEarliest
+	testSourceIntegrationRead(is, cfgMap, lastPosition, recs, false)
+}
+
+func TestSource_Configure_ResetSubscriptionRequiresAdminURL(t *testing.T) {
+	is := is.New(t)
+
+	cfgMap := newSourceCfg("topic")
+	cfgMap[SourceConfigResetSubscription] = SubscriptionPositionEarliest
+
+	err := NewSource().Configure(context.Background(), cfgMap)
+	is.True(err != nil)
+}
+
+// memoryPositionStore keeps positions in memory.
+type memoryPositionStore map[string]opencdc.Position
+
+func (m memoryPositionStore) Save(_ context.Context, subscription string, position opencdc.Position) error {
+	m[subscription] = position
+	return nil
+}
+
+func (m memoryPositionStore) Load(_ context.Context, subscription string) (opencdc.Position, error) {
+	return m[subscription], nil
+}
+
+func TestSource_PositionStore(t *testing.T) {
+	is := is.New(t)
+	ctx := context.Background()
+
+	store := memoryPositionStore{}
+	underTest := &Source{
+		consumer:      &nackRecordingConsumer{},
+		config:        SourceConfig{SubscriptionName: "test-subscription"},
+		positionStore: store,
+	}
+
+	position := Position{
+		MessageID:        pulsar.NewMessageID(1, 2, 0, 0).Serialize(),
+		SubscriptionName: "test-subscription",
+	}.ToSDKPosition()
+
+	err := underTest.Ack(ctx, position)
+	is.NoErr(err)
+	is.Equal(store["test-subscription"], position)
+
+	// the stored position is used when Conduit has no position
+	got, err := underTest.resolvePosition(ctx, nil)
+	is.NoErr(err)
+	is.Equal(got, position)
+
+	// the position managed by Conduit takes precedence
+	conduitPosition := Position{SubscriptionName: "test-subscription"}.ToSDKPosition()
+	got, err = underTest.resolvePosition(ctx, conduitPosition)
+	is.NoErr(err)
+	is.Equal(got, conduitPosition)
+}
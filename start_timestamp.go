@@ -0,0 +1,57 @@
+// Copyright © 2024 Meroxa, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pulsar
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/conduitio/conduit-commons/opencdc"
+	sdk "github.com/conduitio/conduit-connector-sdk"
+)
+
+// parseStartTimestamp parses an RFC 3339 timestamp or unix milliseconds.
+func parseStartTimestamp(s string) (time.Time, error) {
+	if millis, err := strconv.ParseInt(s, 10, 64); err == nil {
+		return time.UnixMilli(millis), nil
+	}
+	t, err := time.Parse(time.RFC3339, s)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("expected an RFC 3339 timestamp or unix milliseconds: %w", err)
+	}
+	return t, nil
+}
+
+// seekToStartTimestamp moves the subscription to the first message published
+// at or after StartFromTimestamp. It is skipped when resuming from a position.
+func (s *Source) seekToStartTimestamp(ctx context.Context, pos opencdc.Position) error {
+	if s.config.StartFromTimestamp == "" {
+		return nil
+	}
+	if pos != nil {
+		sdk.Logger(ctx).Debug().Msg("resuming from position, ignoring start timestamp")
+		return nil
+	}
+
+	// the timestamp was validated when configuring the source
+	start, _ := parseStartTimestamp(s.config.StartFromTimestamp)
+	if err := s.consumer.SeekByTime(start); err != nil {
+		return fmt.Errorf("failed to seek to %v: %w", start, err)
+	}
+	sdk.Logger(ctx).Info().Time("timestamp", start).Msg("seeked subscription to start timestamp")
+	return nil
+}
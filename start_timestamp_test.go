@@ -0,0 +1,119 @@
+// Copyright © 2024 Meroxa, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pulsar
+
+import (
+	"context"
+	"encoding/base64"
+	"testing"
+	"time"
+
+	"github.com/apache/pulsar-client-go/pulsar"
+	"github.com/matryer/is"
+)
+
+// seekingConsumer records the times it was seeked to.
+type seekingConsumer struct {
+	queueConsumer
+	seekedTo []time.Time
+}
+
+func (c *seekingConsumer) SeekByTime(t time.Time) error {
+	c.seekedTo = append(c.seekedTo, t)
+	return nil
+}
+
+func TestParseStartTimestamp(t *testing.T) {
+	want := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+	testCases := []struct {
+		in      string
+		wantErr bool
+	}{
+		{in: "2024-03-01T12:00:00Z"},
+		{in: "2024-03-01T13:00:00+01:00"},
+		{in: "1709294400000"},
+		{in: "2024-03-01", wantErr: true},
+		{in: "yesterday", wantErr: true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.in, func(t *testing.T) {
+			is := is.New(t)
+
+			got, err := parseStartTimestamp(tc.in)
+			if tc.wantErr {
+				is.True(err != nil)
+				return
+			}
+			is.NoErr(err)
+			is.True(got.Equal(want))
+		})
+	}
+}
+
+func TestSource_SeekToStartTimestamp(t *testing.T) {
+	is := is.New(t)
+	ctx := context.Background()
+
+	consumer := &seekingConsumer{}
+	underTest := &Source{
+		consumer: consumer,
+		config:   SourceConfig{StartFromTimestamp: "1709294400000"},
+	}
+
+	// a new subscription is moved to the start timestamp
+	is.NoErr(underTest.seekToStartTimestamp(ctx, nil))
+	is.Equal(len(consumer.seekedTo), 1)
+	is.True(consumer.seekedTo[0].Equal(time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)))
+
+	// the timestamp is ignored when resuming
+	pos := Position{SubscriptionName: "sub"}.ToSDKPosition()
+	is.NoErr(underTest.seekToStartTimestamp(ctx, pos))
+	is.Equal(len(consumer.seekedTo), 1)
+}
+
+func TestSource_Configure_StartFromTimestamp(t *testing.T) {
+	testCases := []struct {
+		name    string
+		cfg     map[string]string
+		wantErr bool
+	}{
+		{name: "rfc 3339", cfg: map[string]string{SourceConfigStartFromTimestamp: "2024-03-01T12:00:00Z"}},
+		{name: "unix millis", cfg: map[string]string{SourceConfigStartFromTimestamp: "1709294400000"}},
+		{name: "invalid", cfg: map[string]string{SourceConfigStartFromTimestamp: "yesterday"}, wantErr: true},
+		{name: "multiple topics", cfg: map[string]string{
+			SourceConfigTopic:              "",
+			SourceConfigTopics:             "topic-1,topic-2",
+			SourceConfigStartFromTimestamp: "1709294400000",
+		}, wantErr: true},
+		{name: "reader", cfg: map[string]string{
+			SourceConfigReaderStartMessageID: base64.StdEncoding.EncodeToString(pulsar.EarliestMessageID().Serialize()),
+			SourceConfigStartFromTimestamp:   "1709294400000",
+		}, wantErr: true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			is := is.New(t)
+
+			cfgMap := newSourceCfg("topic")
+			for k, v := range tc.cfg {
+				cfgMap[k] = v
+			}
+			err := (&Source{}).Configure(context.Background(), cfgMap)
+			is.Equal(err != nil, tc.wantErr)
+		})
+	}
+}
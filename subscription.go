@@ -0,0 +1,76 @@
+// Copyright © 2024 Meroxa, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pulsar
+
+import "github.com/apache/pulsar-client-go/pulsar"
+
+// Supported values of SourceConfig.ResetSubscription and
+// SourceConfig.SubscriptionInitialPosition.
+const (
+	// SubscriptionPositionEarliest is the oldest message available in the
+	// topic.
+	SubscriptionPositionEarliest = "earliest"
+	// SubscriptionPositionLatest is the position right after the newest
+	// message in the topic.
+	SubscriptionPositionLatest = "latest"
+)
+
+// Supported values of SourceConfig.SubscriptionType.
+const (
+	// SubscriptionTypeExclusive allows a single consumer on the subscription.
+	SubscriptionTypeExclusive = "exclusive"
+	// SubscriptionTypeShared distributes messages across all consumers of the
+	// subscription.
+	SubscriptionTypeShared = "shared"
+	// SubscriptionTypeFailover delivers messages to a single active consumer,
+	// another consumer takes over if it disconnects.
+	SubscriptionTypeFailover = "failover"
+	// SubscriptionTypeKeyShared distributes messages across all consumers of
+	// the subscription, messages with the same key go to the same consumer.
+	SubscriptionTypeKeyShared = "key_shared"
+)
+
+// Supported values of SourceConfig.AckMode.
+const (
+	// AckModeIndividual acknowledges each message on its own.
+	AckModeIndividual = "individual"
+	// AckModeCumulative acknowledges a message and all messages before it in
+	// the same partition.
+	AckModeCumulative = "cumulative"
+)
+
+// toSubscriptionType maps a supported value of SourceConfig.SubscriptionType
+// to the Pulsar subscription type.
+func toSubscriptionType(subscriptionType string) pulsar.SubscriptionType {
+	switch subscriptionType {
+	case SubscriptionTypeShared:
+		return pulsar.Shared
+	case SubscriptionTypeFailover:
+		return pulsar.Failover
+	case SubscriptionTypeKeyShared:
+		return pulsar.KeyShared
+	default:
+		return pulsar.Exclusive
+	}
+}
+
+// toSubscriptionInitialPosition maps a supported value of
+// SourceConfig.SubscriptionInitialPosition to the Pulsar initial position.
+func toSubscriptionInitialPosition(position string) pulsar.SubscriptionInitialPosition {
+	if position == SubscriptionPositionLatest {
+		return pulsar.SubscriptionPositionLatest
+	}
+	return pulsar.SubscriptionPositionEarliest
+}
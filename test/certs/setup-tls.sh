@@ -28,4 +28,7 @@ openssl req -new -subj "/CN=client" -key client.key.pem -out client.csr.pem -sha
 # Sign client certificate with the CA:
 openssl x509 -req -in client.csr.pem -CA ca.cert.pem -CAkey ca.key.pem -CAcreateserial -out client.cert.pem -days 365 -sha256
 
+# Extract the client's public key, used to encrypt messages in tests:
+openssl rsa -in client.key.pem -pubout -out client.pub.pem
+
 chmod 644 *.pem
@@ -19,6 +19,7 @@ import (
 	"fmt"
 	"io"
 	"net/http"
+	"strconv"
 	"strings"
 	"testing"
 	"time"
@@ -32,6 +33,8 @@ import (
 const (
 	PulsarURL    = "pulsar://127.0.0.1:6650"
 	PulsarTLSURL = "pulsar+ssl://127.0.0.1:6651"
+
+	PulsarAdminURL = "http://127.0.0.1:8080"
 )
 
 // SetupTopicName creates a new topic name for the test and deletes it if it
@@ -70,3 +73,41 @@ func DeletePulsarTopic(is *is.I, topic string) {
 
 	is.Equal(res.StatusCode, http.StatusNoContent)
 }
+
+// SetupPartitionedTopic creates a new partitioned topic for the test, deleting
+// it first if it exists, so that the test can start from a clean slate.
+func SetupPartitionedTopic(t *testing.T, is *is.I, partitions int) string {
+	topic := "pulsar.topic." + t.Name()
+	topic = strings.ReplaceAll(topic, "/", "_")
+
+	url := fmt.Sprintf(
+		"%s/admin/v2/persistent/public/default/%s/partitions",
+		PulsarAdminURL, topic)
+
+	// topic not found is fine, there is nothing to delete
+	status := doAdminRequest(is, http.MethodDelete, url+"?force=true", "")
+	is.True(status == http.StatusNoContent || status == http.StatusNotFound)
+
+	status = doAdminRequest(is, http.MethodPut, url, strconv.Itoa(partitions))
+	is.Equal(status, http.StatusNoContent)
+
+	return topic
+}
+
+func doAdminRequest(is *is.I, method, url, body string) int {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*10)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, method, url, strings.NewReader(body))
+	is.NoErr(err)
+	req.Header.Set("Content-Type", "application/json")
+
+	res, err := http.DefaultClient.Do(req)
+	is.NoErr(err)
+	defer res.Body.Close()
+
+	_, err = io.Copy(io.Discard, res.Body)
+	is.NoErr(err)
+
+	return res.StatusCode
+}
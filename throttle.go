@@ -0,0 +1,72 @@
+// Copyright © 2024 Meroxa, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pulsar
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/apache/pulsar-client-go/pulsar"
+)
+
+// minThrottleDelay is the delay between sends after the first backpressure
+// signal.
+const minThrottleDelay = time.Millisecond
+
+// throttle adapts the delay between sends to the backpressure signaled by the
+// client. The delay doubles with each signal, up to maxDelay, and halves with
+// each message that is sent successfully, until sends are no longer delayed.
+type throttle struct {
+	delay    time.Duration
+	maxDelay time.Duration
+}
+
+func newThrottle(maxDelay time.Duration) *throttle {
+	return &throttle{maxDelay: maxDelay}
+}
+
+// wait blocks for the current delay.
+func (t *throttle) wait(ctx context.Context) error {
+	if t.delay == 0 {
+		return nil
+	}
+	select {
+	case <-ctx.Done():
+		return fmt.Errorf("stopped waiting for backpressure to ease: %w", ctx.Err())
+	case <-time.After(t.delay):
+		return nil
+	}
+}
+
+// slowDown increases the delay after a backpressure signal.
+func (t *throttle) slowDown() {
+	t.delay = min(max(2*t.delay, minThrottleDelay), t.maxDelay)
+}
+
+// speedUp decreases the delay after a successful send.
+func (t *throttle) speedUp() {
+	t.delay /= 2
+	if t.delay < minThrottleDelay {
+		t.delay = 0
+	}
+}
+
+// isBackpressure returns true if the message was rejected by the client
+// because its send queue or memory buffer is full.
+func isBackpressure(err error) bool {
+	return errors.Is(err, pulsar.ErrSendQueueIsFull) || errors.Is(err, pulsar.ErrMemoryBufferIsFull)
+}
@@ -0,0 +1,55 @@
+// Copyright © 2024 Meroxa, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pulsar
+
+import (
+	"testing"
+	"time"
+
+	"github.com/matryer/is"
+)
+
+func TestThrottle(t *testing.T) {
+	is := is.New(t)
+
+	th := newThrottle(5 * time.Millisecond)
+	is.Equal(th.delay, time.Duration(0))
+
+	var delays []time.Duration
+	for i := 0; i < 4; i++ {
+		th.slowDown()
+		delays = append(delays, th.delay)
+	}
+	// the delay doubles up to the max delay
+	is.Equal(delays, []time.Duration{
+		time.Millisecond,
+		2 * time.Millisecond,
+		4 * time.Millisecond,
+		5 * time.Millisecond,
+	})
+
+	delays = nil
+	for i := 0; i < 4; i++ {
+		th.speedUp()
+		delays = append(delays, th.delay)
+	}
+	// the delay halves until sends are no longer delayed
+	is.Equal(delays, []time.Duration{
+		2500 * time.Microsecond,
+		1250 * time.Microsecond,
+		0,
+		0,
+	})
+}
@@ -0,0 +1,73 @@
+// Copyright © 2024 Meroxa, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pulsar
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/apache/pulsar-client-go/pulsar"
+)
+
+var errOperationTimeout = errors.New("operation timed out")
+
+// callWithTimeout calls fn and gives up after timeout. Most client operations
+// can't be cancelled, if fn returns successfully after the timeout its result
+// is passed to discard so it can be released. A timeout of 0 waits
+// indefinitely.
+func callWithTimeout[T any](fn func() (T, error), timeout time.Duration, discard func(T)) (T, error) {
+	if timeout == 0 {
+		return fn()
+	}
+
+	type result struct {
+		val T
+		err error
+	}
+	done := make(chan result)
+	abandoned := make(chan struct{})
+	go func() {
+		val, err := fn()
+		select {
+		case done <- result{val, err}:
+		case <-abandoned:
+			if err == nil {
+				discard(val)
+			}
+		}
+	}()
+
+	select {
+	case res := <-done:
+		return res.val, res.err
+	case <-time.After(timeout):
+		close(abandoned)
+		var zero T
+		return zero, fmt.Errorf("%w after %v", errOperationTimeout, timeout)
+	}
+}
+
+// lookupTopic looks up the partitions of the topic, bounded by timeout. This
+// surfaces slow lookups separately from the operation that follows.
+func lookupTopic(client pulsar.Client, topic string, timeout time.Duration) error {
+	_, err := callWithTimeout(func() ([]string, error) {
+		return client.TopicPartitions(topic)
+	}, timeout, func([]string) {})
+	if err != nil {
+		return fmt.Errorf("failed to look up topic %q: %w", topic, err)
+	}
+	return nil
+}
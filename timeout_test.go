@@ -0,0 +1,55 @@
+// Copyright © 2024 Meroxa, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pulsar
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/matryer/is"
+)
+
+func TestCallWithTimeout(t *testing.T) {
+	is := is.New(t)
+
+	got, err := callWithTimeout(func() (int, error) {
+		return 1, nil
+	}, time.Second, func(int) {})
+	is.NoErr(err)
+	is.Equal(got, 1)
+}
+
+func TestCallWithTimeout_TimesOut(t *testing.T) {
+	is := is.New(t)
+
+	release := make(chan struct{})
+	discarded := make(chan int)
+
+	_, err := callWithTimeout(func() (int, error) {
+		<-release
+		return 1, nil
+	}, 10*time.Millisecond, func(v int) { discarded <- v })
+	is.True(errors.Is(err, errOperationTimeout))
+
+	// the result returned after the timeout is discarded
+	close(release)
+	select {
+	case v := <-discarded:
+		is.Equal(v, 1)
+	case <-time.After(time.Second):
+		t.Fatal("late result was not discarded")
+	}
+}
@@ -0,0 +1,51 @@
+// Copyright © 2024 Meroxa, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pulsar
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"strings"
+	"text/template"
+
+	"github.com/conduitio/conduit-commons/opencdc"
+)
+
+// isTopicTemplate returns true if the topic contains a Go template that needs
+// to be executed for each record.
+func isTopicTemplate(topic string) bool {
+	return strings.Contains(topic, "{{")
+}
+
+func parseTopicTemplate(topic string) (*template.Template, error) {
+	t, err := template.New("topic").Option("missingkey=error").Parse(topic)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse topic template: %w", err)
+	}
+	return t, nil
+}
+
+// resolveTopic executes the topic template with the record as data.
+func resolveTopic(t *template.Template, record opencdc.Record) (string, error) {
+	var buf bytes.Buffer
+	if err := t.Execute(&buf, record); err != nil {
+		return "", fmt.Errorf("failed to execute topic template: %w", err)
+	}
+	if buf.Len() == 0 {
+		return "", errors.New("topic template resolved to an empty topic")
+	}
+	return buf.String(), nil
+}
@@ -0,0 +1,118 @@
+// Copyright © 2024 Meroxa, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pulsar
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/apache/pulsar-client-go/pulsar"
+	sdk "github.com/conduitio/conduit-connector-sdk"
+)
+
+// Supported values of DestinationConfig.TopicNotFoundPolicy.
+const (
+	// TopicNotFoundPolicyError fails the write.
+	TopicNotFoundPolicyError = "error"
+	// TopicNotFoundPolicyRecreate creates the topic using the admin API and
+	// resends the message.
+	TopicNotFoundPolicyRecreate = "recreate"
+	// TopicNotFoundPolicyBuffer holds the message until the topic exists
+	// again and resends it.
+	TopicNotFoundPolicyBuffer = "buffer"
+)
+
+var errTopicNotFound = errors.New("topic not found")
+
+// isTopicNotFound returns true if the message couldn't be produced because
+// the topic doesn't exist. When the topic is deleted, the client closes the
+// producer and fails all pending messages with pulsar.ErrTopicNotfound.
+func isTopicNotFound(err error) bool {
+	var pulsarErr *pulsar.Error
+	return errors.As(err, &pulsarErr) && pulsarErr.Result() == pulsar.TopicNotFound
+}
+
+// recoverProducer replaces the producer of a topic that was deleted,
+// according to the topic not found policy.
+func (d *Destination) recoverProducer(ctx context.Context, topic string) (pulsar.Producer, error) {
+	d.closeProducer(topic)
+
+	switch d.config.TopicNotFoundPolicy {
+	case TopicNotFoundPolicyRecreate:
+		partitions := d.partitionCounts[topic]
+		if err := createTopic(d.admin, topic, partitions); err != nil {
+			return nil, err
+		}
+		sdk.Logger(ctx).Warn().
+			Str("topic", topic).
+			Int("partitions", partitions).
+			Msg("topic was not found, recreated it")
+
+	case TopicNotFoundPolicyBuffer:
+		sdk.Logger(ctx).Warn().Str("topic", topic).Msg("topic was not found, waiting for it to be created")
+	}
+
+	for {
+		producer, err := d.createProducer(ctx, topic)
+		if err == nil {
+			d.setProducer(topic, producer)
+			return producer, nil
+		}
+		if d.config.TopicNotFoundPolicy != TopicNotFoundPolicyBuffer {
+			return nil, err
+		}
+		// the broker reports missing topics on lookup as text, so creating
+		// the producer is retried regardless of the error
+		sdk.Logger(ctx).Debug().Err(err).Str("topic", topic).Msg("failed to create producer, retrying")
+
+		select {
+		case <-ctx.Done():
+			return nil, fmt.Errorf("stopped waiting for topic %q: %w", topic, ctx.Err())
+		case <-time.After(d.config.TopicNotFoundRetryInterval):
+		}
+	}
+}
+
+func (d *Destination) setProducer(topic string, producer pulsar.Producer) {
+	if d.largeProducer != nil && topic == d.config.LargeMessageTopic {
+		d.largeProducer = producer
+		return
+	}
+	if d.topicTemplate == nil {
+		d.producer = producer
+		return
+	}
+	d.producers[topic] = producer
+}
+
+// closeProducer closes the producer of the topic. The producer of a static
+// or large message topic stays in place until it is replaced, so the next
+// write retries the recovery if it fails.
+func (d *Destination) closeProducer(topic string) {
+	if d.largeProducer != nil && topic == d.config.LargeMessageTopic {
+		d.largeProducer.Close()
+		return
+	}
+	if d.topicTemplate == nil {
+		d.producer.Close()
+		return
+	}
+	if producer, ok := d.producers[topic]; ok {
+		producer.Close()
+		delete(d.producers, topic)
+	}
+}
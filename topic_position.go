@@ -0,0 +1,82 @@
+// Copyright © 2024 Meroxa, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pulsar
+
+import (
+	"fmt"
+	"maps"
+
+	"github.com/apache/pulsar-client-go/pulsar"
+)
+
+// topicPositions tracks the last message read from each topic of a source
+// consuming multiple topics. The position of a record contains the last
+// message read from every topic at the time the record was read, so the
+// position acked last tells how far each topic was processed. When resuming,
+// messages of a topic up to that message are skipped. They are redelivered by
+// the broker if their acknowledgements were lost.
+type topicPositions struct {
+	// read contains the serialized ID of the last message read from each
+	// topic.
+	read map[string][]byte
+	// resume contains the ID of the last message processed from each topic
+	// before the source was restarted.
+	resume map[string]pulsar.MessageID
+}
+
+// newTopicPositions creates a tracker resuming from the per topic message IDs
+// of a position. Positions without them, written by older versions of the
+// connector, resume from the subscription alone.
+func newTopicPositions(topics map[string][]byte) (*topicPositions, error) {
+	p := &topicPositions{
+		read:   make(map[string][]byte, len(topics)),
+		resume: make(map[string]pulsar.MessageID, len(topics)),
+	}
+	for topic, serialized := range topics {
+		id, err := pulsar.DeserializeMessageID(serialized)
+		if err != nil {
+			return nil, fmt.Errorf("failed to deserialize message ID of topic %q: %w", topic, err)
+		}
+		p.read[topic] = serialized
+		p.resume[topic] = id
+	}
+	return p, nil
+}
+
+// processed returns true if the message was processed before the source was
+// restarted.
+func (p *topicPositions) processed(msg pulsar.Message) bool {
+	last, ok := p.resume[msg.Topic()]
+	return ok && !messageIDAfter(msg.ID(), last)
+}
+
+// add records the message as the last one read from its topic and returns
+// the last message read from each topic.
+func (p *topicPositions) add(msg pulsar.Message) map[string][]byte {
+	p.read[msg.Topic()] = msg.ID().Serialize()
+	return maps.Clone(p.read)
+}
+
+// messageIDAfter returns true if the message ID a comes after b. Both IDs must
+// belong to the same topic partition.
+func messageIDAfter(a, b pulsar.MessageID) bool {
+	if a.LedgerID() != b.LedgerID() {
+		return a.LedgerID() > b.LedgerID()
+	}
+	if a.EntryID() != b.EntryID() {
+		return a.EntryID() > b.EntryID()
+	}
+	return a.BatchIdx() > b.BatchIdx()
+}
@@ -0,0 +1,112 @@
+// Copyright © 2024 Meroxa, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pulsar
+
+import (
+	"context"
+	"testing"
+
+	"github.com/apache/pulsar-client-go/pulsar"
+	"github.com/conduitio/conduit-commons/opencdc"
+	"github.com/matryer/is"
+)
+
+func TestSource_Read_ResumesEachTopic(t *testing.T) {
+	is := is.New(t)
+	ctx := context.Background()
+
+	orders := func(entry int64) pulsar.Message {
+		return readableMessage{fakeMessage{topic: "orders", id: pulsar.NewMessageID(1, entry, 0, 0)}}
+	}
+	payments := func(entry int64) pulsar.Message {
+		return readableMessage{fakeMessage{topic: "payments", id: pulsar.NewMessageID(2, entry, 0, 0)}}
+	}
+
+	// the first run reads both topics interleaved, the position of the last
+	// record tracks how far each topic was read
+	topicPositions, err := newTopicPositions(nil)
+	is.NoErr(err)
+	firstRun := &Source{
+		consumer:       &queueConsumer{messages: []pulsar.Message{orders(1), payments(1), orders(2), payments(2), orders(3)}},
+		config:         SourceConfig{Config: Config{Topic: "orders"}, Topics: []string{"payments"}, SubscriptionName: "sub"},
+		topicPositions: topicPositions,
+	}
+	var last opencdc.Record
+	for i := 0; i < 4; i++ {
+		last, err = firstRun.Read(ctx)
+		is.NoErr(err)
+	}
+	pos, err := parsePosition(last.Position)
+	is.NoErr(err)
+	is.Equal(len(pos.Topics), 2)
+
+	// the acks of the first run were lost, the broker redelivers all messages
+	topicPositions, err = newTopicPositions(pos.Topics)
+	is.NoErr(err)
+	consumer := &rejectRecordingConsumer{queueConsumer: queueConsumer{messages: []pulsar.Message{
+		payments(1), orders(1), orders(2), payments(2), orders(3), payments(3),
+	}}}
+	secondRun := &Source{
+		consumer:       consumer,
+		config:         firstRun.config,
+		topicPositions: topicPositions,
+	}
+
+	var resumed []pulsar.MessageID
+	for i := 0; i < 2; i++ {
+		rec, err := secondRun.Read(ctx)
+		is.NoErr(err)
+		pos, err := parsePosition(rec.Position)
+		is.NoErr(err)
+		id, err := pulsar.DeserializeMessageID(pos.MessageID)
+		is.NoErr(err)
+		resumed = append(resumed, id)
+	}
+
+	// each topic resumes right after its last processed message, processed
+	// messages are acked
+	is.Equal(len(resumed), 2)
+	is.True(messageIDEqual(resumed[0], orders(3).ID()))
+	is.True(messageIDEqual(resumed[1], payments(3).ID()))
+	is.Equal(len(consumer.acked), 4)
+}
+
+func TestNewTopicPositions_SingleSubscriptionPosition(t *testing.T) {
+	is := is.New(t)
+
+	// positions of older versions don't contain topics
+	pos, err := parsePosition(opencdc.Position(`{"messageID":"CAEQAQ==","subscriptionName":"sub"}`))
+	is.NoErr(err)
+	is.Equal(pos.Topics, nil)
+
+	topicPositions, err := newTopicPositions(pos.Topics)
+	is.NoErr(err)
+	is.True(!topicPositions.processed(readableMessage{fakeMessage{topic: "orders", id: pulsar.NewMessageID(1, 1, 0, 0)}}))
+}
+
+func TestMessageIDAfter(t *testing.T) {
+	is := is.New(t)
+
+	is.True(messageIDAfter(pulsar.NewMessageID(2, 0, 0, 0), pulsar.NewMessageID(1, 5, 0, 0)))
+	is.True(messageIDAfter(pulsar.NewMessageID(1, 6, 0, 0), pulsar.NewMessageID(1, 5, 0, 0)))
+	is.True(messageIDAfter(pulsar.NewMessageID(1, 5, 1, 0), pulsar.NewMessageID(1, 5, 0, 0)))
+	is.True(!messageIDAfter(pulsar.NewMessageID(1, 5, 0, 0), pulsar.NewMessageID(1, 5, 0, 0)))
+	is.True(!messageIDAfter(pulsar.NewMessageID(1, 4, 0, 0), pulsar.NewMessageID(1, 5, 0, 0)))
+}
+
+// messageIDEqual returns true if both IDs refer to the same message.
+func messageIDEqual(a, b pulsar.MessageID) bool {
+	return !messageIDAfter(a, b) && !messageIDAfter(b, a)
+}
@@ -0,0 +1,104 @@
+// Copyright © 2024 Meroxa, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pulsar
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/apache/pulsar-client-go/pulsar"
+	"github.com/conduitio/conduit-commons/opencdc"
+	"github.com/matryer/is"
+)
+
+func TestResolveTopic(t *testing.T) {
+	is := is.New(t)
+
+	tmpl, err := parseTopicTemplate(`events-{{index .Metadata "tenant"}}`)
+	is.NoErr(err)
+
+	topic, err := resolveTopic(tmpl, opencdc.Record{
+		Metadata: opencdc.Metadata{"tenant": "acme"},
+	})
+	is.NoErr(err)
+	is.Equal(topic, "events-acme")
+}
+
+func TestResolveTopic_Empty(t *testing.T) {
+	is := is.New(t)
+
+	tmpl, err := parseTopicTemplate(`{{index .Metadata "tenant"}}`)
+	is.NoErr(err)
+
+	_, err = resolveTopic(tmpl, opencdc.Record{Metadata: opencdc.Metadata{}})
+	is.True(err != nil)
+}
+
+func TestParseTopicTemplate_Invalid(t *testing.T) {
+	is := is.New(t)
+
+	_, err := parseTopicTemplate(`events-{{.Metadata`)
+	is.True(err != nil)
+}
+
+// flushTrackingProducer records whether it was flushed before it was closed.
+type flushTrackingProducer struct {
+	pulsar.Producer
+
+	topic              string
+	flushErr           error
+	flushed            bool
+	flushedBeforeClose bool
+}
+
+func (p *flushTrackingProducer) Topic() string { return p.topic }
+
+func (p *flushTrackingProducer) FlushWithCtx(context.Context) error {
+	p.flushed = true
+	return p.flushErr
+}
+
+func (p *flushTrackingProducer) Close() {
+	p.flushedBeforeClose = p.flushed
+}
+
+func TestDestination_Teardown_FlushesCachedProducers(t *testing.T) {
+	is := is.New(t)
+
+	orders := &flushTrackingProducer{}
+	payments := &flushTrackingProducer{}
+	underTest := &Destination{producers: map[string]pulsar.Producer{
+		"events-orders":   orders,
+		"events-payments": payments,
+	}}
+
+	is.NoErr(underTest.Teardown(context.Background()))
+	is.True(orders.flushedBeforeClose)
+	is.True(payments.flushedBeforeClose)
+}
+
+func TestDestination_Teardown_ReturnsFlushError(t *testing.T) {
+	is := is.New(t)
+
+	errFlush := errors.New("connection closed")
+	producer := &flushTrackingProducer{topic: "orders", flushErr: errFlush}
+	underTest := &Destination{producer: producer}
+
+	err := underTest.Teardown(context.Background())
+	is.True(errors.Is(err, errFlush))
+	// the producer is closed although the flush failed
+	is.True(producer.flushedBeforeClose)
+}
@@ -0,0 +1,44 @@
+// Copyright © 2024 Meroxa, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pulsar
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/apache/pulsar-client-go/pulsar"
+	sdk "github.com/conduitio/conduit-connector-sdk"
+)
+
+// endTransaction commits the transaction of a batch if all its messages were
+// sent, otherwise it aborts it. No record is reported as written unless the
+// transaction was committed, as messages of an aborted transaction are
+// discarded by the broker on all topics.
+func (d *Destination) endTransaction(ctx context.Context, txn pulsar.Transaction, n int, err error) (int, error) {
+	if err != nil {
+		if abortErr := txn.Abort(ctx); abortErr != nil {
+			err = errors.Join(err, fmt.Errorf("failed to abort transaction: %w", abortErr))
+		}
+		sdk.Logger(ctx).Warn().Err(err).Msg("aborted transaction")
+		return 0, err
+	}
+
+	if err := txn.Commit(ctx); err != nil {
+		return 0, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	sdk.Logger(ctx).Trace().Int("count", n).Msg("committed transaction")
+	return n, nil
+}
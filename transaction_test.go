@@ -0,0 +1,130 @@
+// Copyright © 2024 Meroxa, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pulsar
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/apache/pulsar-client-go/pulsar"
+	"github.com/conduitio-labs/conduit-connector-pulsar/test"
+	"github.com/conduitio/conduit-commons/opencdc"
+	sdk "github.com/conduitio/conduit-connector-sdk"
+	"github.com/matryer/is"
+)
+
+type transactionClient struct {
+	pulsar.Client
+
+	txn *recordingTransaction
+}
+
+func (c *transactionClient) NewTransaction(time.Duration) (pulsar.Transaction, error) {
+	return c.txn, nil
+}
+
+type recordingTransaction struct {
+	pulsar.Transaction
+
+	committed bool
+	aborted   bool
+}
+
+func (t *recordingTransaction) Commit(context.Context) error {
+	t.committed = true
+	return nil
+}
+
+func (t *recordingTransaction) Abort(context.Context) error {
+	t.aborted = true
+	return nil
+}
+
+func TestDestination_Write_TransactionAcrossTopics(t *testing.T) {
+	testCases := []struct {
+		name          string
+		failAfter     int
+		wantCommitted bool
+	}{
+		{name: "commit", wantCommitted: true},
+		{name: "abort", failAfter: 1},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			is := is.New(t)
+
+			tmpl, err := parseTopicTemplate(`events-{{index .Metadata "tenant"}}`)
+			is.NoErr(err)
+
+			txn := &recordingTransaction{}
+			acme := &recordingProducer{}
+			globex := &recordingProducer{failAfter: tc.failAfter}
+			con := &Destination{
+				client:        &transactionClient{txn: txn},
+				topicTemplate: tmpl,
+				producers: map[string]pulsar.Producer{
+					"events-acme":   acme,
+					"events-globex": globex,
+				},
+				config: DestinationConfig{TransactionalWrites: true},
+			}
+
+			var records []opencdc.Record
+			for _, tenant := range []string{"acme", "globex", "globex"} {
+				records = append(records, sdk.Util.Source.NewRecordCreate(
+					nil,
+					opencdc.Metadata{"tenant": tenant},
+					opencdc.RawData(tenant),
+					opencdc.RawData(exampleMessage),
+				))
+			}
+
+			written, err := con.Write(context.Background(), records)
+			is.Equal(txn.committed, tc.wantCommitted)
+			is.Equal(txn.aborted, !tc.wantCommitted)
+			if tc.wantCommitted {
+				is.NoErr(err)
+				is.Equal(written, 3)
+			} else {
+				is.True(errors.Is(err, pulsar.ErrProducerBlockedQuotaExceeded))
+				// messages sent before the failure are discarded with the
+				// transaction
+				is.Equal(written, 0)
+			}
+
+			// messages to both topics are part of the same transaction
+			is.True(len(acme.sent) > 0)
+			is.True(len(globex.sent) > 0)
+			for _, msg := range append(acme.sent, globex.sent...) {
+				is.Equal(msg.Transaction, pulsar.Transaction(txn))
+			}
+		})
+	}
+}
+
+func TestDestination_Configure_TransactionalWritesRequiresTransactions(t *testing.T) {
+	is := is.New(t)
+	con := NewDestination()
+
+	err := con.Configure(context.Background(), map[string]string{
+		DestinationConfigUrl:                 test.PulsarURL,
+		DestinationConfigTopic:               "test-topic",
+		DestinationConfigTransactionalWrites: "true",
+	})
+	is.True(err != nil)
+}
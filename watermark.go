@@ -0,0 +1,105 @@
+// Copyright © 2024 Meroxa, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pulsar
+
+import (
+	"maps"
+	"time"
+
+	"github.com/conduitio/conduit-commons/opencdc"
+	sdk "github.com/conduitio/conduit-connector-sdk"
+)
+
+// metadataWatermark is set on watermark records to the minimum event time
+// seen across partitions, formatted as RFC 3339.
+const metadataWatermark = "pulsar.watermark"
+
+// watermarkTracker tracks the latest event time of each partition. The
+// watermark is the minimum of those, no message with an earlier event time is
+// expected once every partition moved past it.
+type watermarkTracker struct {
+	interval time.Duration
+
+	partitions map[string]time.Time
+	emitted    time.Time
+	emittedAt  time.Time
+
+	now func() time.Time
+}
+
+func newWatermarkTracker(interval time.Duration) *watermarkTracker {
+	return &watermarkTracker{
+		interval:   interval,
+		partitions: make(map[string]time.Time),
+		now:        time.Now,
+	}
+}
+
+// observe records the event time of a message read from the partition.
+// Messages without an event time are ignored.
+func (w *watermarkTracker) observe(partition string, eventTime time.Time) {
+	if eventTime.IsZero() {
+		return
+	}
+	if eventTime.After(w.partitions[partition]) {
+		w.partitions[partition] = eventTime
+	}
+}
+
+// watermark returns the minimum of the latest event times of all partitions.
+func (w *watermarkTracker) watermark() time.Time {
+	var min time.Time
+	for _, eventTime := range w.partitions {
+		if min.IsZero() || eventTime.Before(min) {
+			min = eventTime
+		}
+	}
+	return min
+}
+
+// next returns the watermark if the interval elapsed since the last one was
+// emitted and the watermark advanced in the meantime.
+func (w *watermarkTracker) next() (time.Time, bool) {
+	now := w.now()
+	if w.emittedAt.IsZero() {
+		// the first interval starts with the first read
+		w.emittedAt = now
+	}
+	if now.Sub(w.emittedAt) < w.interval {
+		return time.Time{}, false
+	}
+	watermark := w.watermark()
+	if !watermark.After(w.emitted) {
+		return time.Time{}, false
+	}
+	w.emitted = watermark
+	w.emittedAt = now
+	return watermark, true
+}
+
+// watermarkRecord creates a record carrying the watermark in its metadata.
+// Its position is marked as a watermark, so acking it is a no-op.
+func (s *Source) watermarkRecord(watermark time.Time) opencdc.Record {
+	position := Position{
+		SubscriptionName: s.config.SubscriptionName,
+		Watermark:        true,
+	}
+	if s.topicPositions != nil {
+		position.Topics = maps.Clone(s.topicPositions.read)
+	}
+	metadata := opencdc.Metadata{metadataWatermark: watermark.UTC().Format(time.RFC3339Nano)}
+	metadata.SetCreatedAt(watermark)
+	return sdk.Util.Source.NewRecordCreate(position.ToSDKPosition(), metadata, nil, nil)
+}
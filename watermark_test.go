@@ -0,0 +1,86 @@
+// Copyright © 2024 Meroxa, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pulsar
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/apache/pulsar-client-go/pulsar"
+	"github.com/matryer/is"
+)
+
+// timedMessage is a readable message with an event time.
+type timedMessage struct {
+	readableMessage
+	eventTime time.Time
+}
+
+func (m timedMessage) EventTime() time.Time { return m.eventTime }
+
+func TestSource_Read_Watermarks(t *testing.T) {
+	is := is.New(t)
+	ctx := context.Background()
+
+	base := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	message := func(partition string, entry int64, eventTime time.Duration) pulsar.Message {
+		return timedMessage{
+			readableMessage: readableMessage{fakeMessage{topic: partition, id: pulsar.NewMessageID(1, entry, 0, 0)}},
+			eventTime:       base.Add(eventTime),
+		}
+	}
+	consumer := &queueConsumer{messages: []pulsar.Message{
+		message("topic-partition-0", 1, 5*time.Second),
+		message("topic-partition-1", 2, 2*time.Second),
+		message("topic-partition-1", 3, 8*time.Second),
+		message("topic-partition-0", 4, 3*time.Second),
+	}}
+
+	now := base
+	watermarks := newWatermarkTracker(time.Minute)
+	watermarks.now = func() time.Time { return now }
+	underTest := &Source{
+		consumer:   consumer,
+		config:     SourceConfig{Config: Config{Topic: "topic"}},
+		watermarks: watermarks,
+	}
+
+	read := func() string {
+		rec, err := underTest.Read(ctx)
+		is.NoErr(err)
+		is.NoErr(underTest.Ack(ctx, rec.Position))
+		return rec.Metadata[metadataWatermark]
+	}
+
+	// no watermark before messages were read
+	is.Equal(read(), "")
+	is.Equal(read(), "")
+
+	now = now.Add(time.Minute)
+	is.Equal(read(), base.Add(2*time.Second).Format(time.RFC3339Nano))
+
+	// the interval didn't elapse yet
+	is.Equal(read(), "")
+
+	now = now.Add(time.Minute)
+	is.Equal(read(), base.Add(5*time.Second).Format(time.RFC3339Nano))
+
+	// a late message doesn't move the watermark back, so none is emitted
+	is.Equal(read(), "")
+	now = now.Add(time.Minute)
+	_, ok := watermarks.next()
+	is.True(!ok)
+}
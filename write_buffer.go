@@ -0,0 +1,173 @@
+// Copyright © 2024 Meroxa, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pulsar
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/apache/pulsar-client-go/pulsar"
+	sdk "github.com/conduitio/conduit-connector-sdk"
+)
+
+// writeBuffer tracks messages that were sent asynchronously and not yet
+// confirmed by the broker. It is flushed once one of the configured thresholds
+// is reached.
+type writeBuffer struct {
+	maxRecords   int
+	maxBytes     int
+	flushTimeout time.Duration
+
+	// throttle is set when sends are throttled on backpressure.
+	throttle *throttle
+
+	pending  []*bufferedWrite
+	bytes    int
+	openedAt time.Time
+
+	now func() time.Time
+}
+
+// bufferedWrite is a message sent asynchronously for the record at index.
+type bufferedWrite struct {
+	index          int
+	idempotencyKey string
+	producer       pulsar.Producer
+	msg            *pulsar.ProducerMessage
+
+	msgID pulsar.MessageID
+	err   error
+	done  chan struct{}
+}
+
+func newWriteBuffer(cfg DestinationConfig) *writeBuffer {
+	b := &writeBuffer{
+		maxRecords:   cfg.WriteBufferMaxRecords,
+		maxBytes:     cfg.WriteBufferMaxBytes,
+		flushTimeout: cfg.WriteBufferFlushTimeout,
+		now:          time.Now,
+	}
+	if cfg.AdaptiveThrottling {
+		b.throttle = newThrottle(cfg.AdaptiveThrottlingMaxDelay)
+	}
+	return b
+}
+
+// add sends the message asynchronously and tracks it until the next flush.
+func (b *writeBuffer) add(ctx context.Context, w *bufferedWrite) {
+	if len(b.pending) == 0 {
+		b.openedAt = b.now()
+	}
+
+	w.done = make(chan struct{})
+	b.send(ctx, w)
+
+	b.pending = append(b.pending, w)
+	b.bytes += len(w.msg.Payload)
+}
+
+// send sends the message asynchronously. With a throttle, messages the client
+// rejects because its send queue or memory buffer is full are resent after a
+// delay that grows while the pressure persists.
+func (b *writeBuffer) send(ctx context.Context, w *bufferedWrite) {
+	for {
+		if b.throttle != nil {
+			if err := b.throttle.wait(ctx); err != nil {
+				w.err = err
+				close(w.done)
+				return
+			}
+		}
+
+		var backpressure bool
+		w.producer.SendAsync(ctx, w.msg, func(msgID pulsar.MessageID, _ *pulsar.ProducerMessage, err error) {
+			if b.throttle != nil && isBackpressure(err) {
+				// the client rejects the message before SendAsync returns
+				backpressure = true
+				return
+			}
+			w.msgID, w.err = msgID, err
+			close(w.done)
+		})
+		if b.throttle == nil {
+			return
+		}
+		if !backpressure {
+			b.throttle.speedUp()
+			return
+		}
+
+		b.throttle.slowDown()
+		sdk.Logger(ctx).Debug().
+			Dur("delay", b.throttle.delay).
+			Msg("backpressure signaled, slowing down")
+	}
+}
+
+// shouldFlush returns true if the buffer reached one of its thresholds.
+func (b *writeBuffer) shouldFlush() bool {
+	switch {
+	case len(b.pending) == 0:
+		return false
+	case b.maxRecords > 0 && len(b.pending) >= b.maxRecords:
+		return true
+	case b.maxBytes > 0 && b.bytes >= b.maxBytes:
+		return true
+	case b.flushTimeout > 0 && b.now().Sub(b.openedAt) >= b.flushTimeout:
+		return true
+	default:
+		return false
+	}
+}
+
+// flush flushes the producers of the pending messages and waits until the
+// broker confirmed or rejected each of them. It returns the flushed writes in
+// the order they were added, also if it fails to wait for all of them.
+func (b *writeBuffer) flush(ctx context.Context) ([]*bufferedWrite, error) {
+	pending := b.pending
+	b.pending = nil
+	b.bytes = 0
+
+	flushed := make(map[pulsar.Producer]bool)
+	for _, w := range pending {
+		if flushed[w.producer] {
+			continue
+		}
+		flushed[w.producer] = true
+		if err := w.producer.FlushWithCtx(ctx); err != nil {
+			return pending, fmt.Errorf("failed to flush producer: %w", err)
+		}
+	}
+
+	for _, w := range pending {
+		select {
+		case <-ctx.Done():
+			return pending, ctx.Err()
+		case <-w.done:
+		}
+	}
+	return pending, nil
+}
+
+// finished returns true if the broker confirmed or rejected the message.
+func (w *bufferedWrite) finished() bool {
+	select {
+	case <-w.done:
+		return true
+	default:
+		return false
+	}
+}
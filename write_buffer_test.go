@@ -0,0 +1,168 @@
+// Copyright © 2024 Meroxa, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pulsar
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/apache/pulsar-client-go/pulsar"
+	"github.com/conduitio/conduit-commons/opencdc"
+	sdk "github.com/conduitio/conduit-connector-sdk"
+	"github.com/matryer/is"
+)
+
+// asyncProducer holds back send results until it is flushed and records the
+// number of messages confirmed by each flush. Messages at the indexes in fail
+// are rejected.
+type asyncProducer struct {
+	pulsar.Producer
+
+	fail    map[int]bool
+	sent    int
+	pending []func()
+	flushes []int
+}
+
+func (p *asyncProducer) SendAsync(_ context.Context, msg *pulsar.ProducerMessage, callback func(pulsar.MessageID, *pulsar.ProducerMessage, error)) {
+	var err error
+	if p.fail[p.sent] {
+		err = errors.New("message rejected")
+	}
+	p.sent++
+	p.pending = append(p.pending, func() { callback(pulsar.EarliestMessageID(), msg, err) })
+}
+
+func (p *asyncProducer) FlushWithCtx(context.Context) error {
+	for _, confirm := range p.pending {
+		confirm()
+	}
+	p.flushes = append(p.flushes, len(p.pending))
+	p.pending = nil
+	return nil
+}
+
+func newBufferTestRecords(n int) []opencdc.Record {
+	records := make([]opencdc.Record, n)
+	for i := range records {
+		records[i] = sdk.Util.Source.NewRecordCreate(
+			[]byte(fmt.Sprintf("pos-%d", i)),
+			opencdc.Metadata{},
+			opencdc.RawData(fmt.Sprintf("key-%d", i)),
+			opencdc.RawData(exampleMessage),
+		)
+	}
+	return records
+}
+
+func TestDestination_Write_WriteBuffer(t *testing.T) {
+	records := newBufferTestRecords(5)
+	recordSize := len(records[0].Bytes())
+
+	testCases := []struct {
+		name   string
+		config DestinationConfig
+	}{{
+		name:   "max records",
+		config: DestinationConfig{WriteBufferMaxRecords: 2},
+	}, {
+		name:   "max bytes",
+		config: DestinationConfig{WriteBufferMaxBytes: 2 * recordSize},
+	}, {
+		name:   "flush timeout",
+		config: DestinationConfig{WriteBufferFlushTimeout: 2 * time.Second},
+	}}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			is := is.New(t)
+
+			producer := &asyncProducer{}
+			con := &Destination{producer: producer, config: tc.config}
+			con.buffer = newWriteBuffer(tc.config)
+			// each reading of the clock advances it by a second
+			clock := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+			con.buffer.now = func() time.Time {
+				clock = clock.Add(time.Second)
+				return clock
+			}
+
+			written, err := con.Write(context.Background(), records)
+			is.NoErr(err)
+			is.Equal(written, len(records))
+			// the last record is flushed at the end of the write
+			is.Equal(producer.flushes, []int{2, 2, 1})
+		})
+	}
+}
+
+func TestDestination_Write_WriteBufferFailure(t *testing.T) {
+	is := is.New(t)
+
+	cfg := DestinationConfig{WriteBufferMaxRecords: 3}
+	producer := &asyncProducer{fail: map[int]bool{2: true}}
+	con := &Destination{producer: producer, config: cfg, buffer: newWriteBuffer(cfg)}
+
+	written, err := con.Write(context.Background(), newBufferTestRecords(5))
+	is.True(err != nil)
+	is.Equal(written, 2)
+	is.Equal(producer.flushes, []int{3})
+}
+
+func TestDestination_Configure_Batching(t *testing.T) {
+	testCases := []struct {
+		name    string
+		cfg     map[string]string
+		wantErr bool
+	}{
+		{name: "batching options", cfg: map[string]string{
+			DestinationConfigBatchingMaxMessages:     "500",
+			DestinationConfigBatchingMaxPublishDelay: "50ms",
+			DestinationConfigBatchingMaxSize:         "1048576",
+		}},
+		{name: "batching disabled", cfg: map[string]string{DestinationConfigDisableBatching: "true"}},
+		{name: "negative max messages", cfg: map[string]string{DestinationConfigBatchingMaxMessages: "-1"}, wantErr: true},
+		{name: "negative publish delay", cfg: map[string]string{DestinationConfigBatchingMaxPublishDelay: "-1ms"}, wantErr: true},
+		{name: "max pending messages", cfg: map[string]string{
+			DestinationConfigMaxPendingMessages: "100",
+			DestinationConfigBlockIfQueueFull:   "false",
+		}},
+		{name: "negative max pending messages", cfg: map[string]string{DestinationConfigMaxPendingMessages: "-1"}, wantErr: true},
+		{name: "disabled with max size", cfg: map[string]string{
+			DestinationConfigDisableBatching: "true",
+			DestinationConfigBatchingMaxSize: "1024",
+		}, wantErr: true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			is := is.New(t)
+
+			cfgMap := map[string]string{
+				DestinationConfigUrl:   "pulsar://localhost:6650",
+				DestinationConfigTopic: "test-topic",
+			}
+			for key, val := range tc.cfg {
+				cfgMap[key] = val
+			}
+
+			err := NewDestination().Configure(context.Background(), cfgMap)
+			is.Equal(err != nil, tc.wantErr)
+		})
+	}
+}